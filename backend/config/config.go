@@ -2,6 +2,8 @@ package config
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -15,6 +17,61 @@ type AppConfig struct {
 	SMTPUser string `mapstructure:"SMTP_USER"`
 	SMTPPass string `mapstructure:"SMTP_PASS"`
 	SMTPFrom string `mapstructure:"SMTP_FROM"`
+
+	// Base URL of the web client, used to build links in emails
+	FrontendURL string `mapstructure:"FRONTEND_URL"`
+
+	// Reverse proxies, as IPs or CIDRs, whose X-Forwarded-For and X-Real-IP
+	// headers are believed; with none, the client IP is the peer address
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+
+	// Token settings
+	JWTSecret             string        `mapstructure:"JWT_SECRET"`
+	JWTIssuer             string        `mapstructure:"JWT_ISSUER"`
+	AccessTokenTTL        time.Duration `mapstructure:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL       time.Duration `mapstructure:"REFRESH_TOKEN_TTL"`
+	PasswordResetTokenTTL time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_TTL"`
+	InvitationTokenTTL    time.Duration `mapstructure:"INVITATION_TOKEN_TTL"`
+
+	// How often a session's last activity is written at most
+	SessionActivityInterval time.Duration `mapstructure:"SESSION_ACTIVITY_INTERVAL"`
+
+	// Failed sign-in throttling
+	LoginFreeAttempts       int           `mapstructure:"LOGIN_FREE_ATTEMPTS"`
+	LoginDelayBase          time.Duration `mapstructure:"LOGIN_DELAY_BASE"`
+	LoginDelayMax           time.Duration `mapstructure:"LOGIN_DELAY_MAX"`
+	LoginLockoutThreshold   int           `mapstructure:"LOGIN_LOCKOUT_THRESHOLD"`
+	LoginIPLockoutThreshold int           `mapstructure:"LOGIN_IP_LOCKOUT_THRESHOLD"`
+	LoginLockoutDuration    time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginFailureWindow      time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+
+	// Google sign-in; disabled while GOOGLE_CLIENT_ID is empty
+	GoogleOIDCIssuer   string `mapstructure:"GOOGLE_OIDC_ISSUER"`
+	GoogleClientID     string `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret string `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GoogleRedirectURL  string `mapstructure:"GOOGLE_REDIRECT_URL"`
+
+	// Multi-factor authentication; MFA_ENCRYPTION_KEY encrypts stored TOTP
+	// secrets and defaults to JWT_SECRET
+	MFAIssuer        string        `mapstructure:"MFA_ISSUER"`
+	MFAEncryptionKey string        `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAChallengeTTL  time.Duration `mapstructure:"MFA_CHALLENGE_TTL"`
+
+	// SCIM provisioning at /scim/v2; disabled while SCIM_BEARER_TOKEN is empty
+	SCIMBearerToken string `mapstructure:"SCIM_BEARER_TOKEN"`
+
+	// Authorization settings
+	PermissionCacheTTL time.Duration `mapstructure:"PERMISSION_CACHE_TTL"`
+
+	// How long case statistics are reused before they are recomputed
+	CaseStatsCacheTTL time.Duration `mapstructure:"CASE_STATS_CACHE_TTL"`
+
+	// Password hashing settings
+	PasswordHashAlgorithm     string `mapstructure:"PASSWORD_HASH_ALGORITHM"`
+	PasswordArgon2Memory      uint32 `mapstructure:"PASSWORD_ARGON2_MEMORY"`
+	PasswordArgon2Iterations  uint32 `mapstructure:"PASSWORD_ARGON2_ITERATIONS"`
+	PasswordArgon2Parallelism uint8  `mapstructure:"PASSWORD_ARGON2_PARALLELISM"`
+	PasswordBcryptCost        int    `mapstructure:"PASSWORD_BCRYPT_COST"`
 }
 
 var Cfg AppConfig
@@ -23,6 +80,35 @@ func Load() {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv() // override with environment variables
 
+	viper.SetDefault("JWT_SECRET", "")
+	viper.SetDefault("JWT_ISSUER", "trueforce-ai")
+	viper.SetDefault("ACCESS_TOKEN_TTL", "15m")
+	viper.SetDefault("REFRESH_TOKEN_TTL", "720h")
+	viper.SetDefault("PASSWORD_RESET_TOKEN_TTL", "1h")
+	viper.SetDefault("INVITATION_TOKEN_TTL", "72h")
+	viper.SetDefault("SESSION_ACTIVITY_INTERVAL", "1m")
+	viper.SetDefault("LOGIN_FREE_ATTEMPTS", 3)
+	viper.SetDefault("LOGIN_DELAY_BASE", "1s")
+	viper.SetDefault("LOGIN_DELAY_MAX", "30s")
+	viper.SetDefault("LOGIN_LOCKOUT_THRESHOLD", 10)
+	viper.SetDefault("LOGIN_IP_LOCKOUT_THRESHOLD", 50)
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("LOGIN_FAILURE_WINDOW", "15m")
+	viper.SetDefault("FRONTEND_URL", "http://localhost:3000")
+	viper.SetDefault("TRUSTED_PROXIES", "")
+	viper.SetDefault("GOOGLE_OIDC_ISSUER", "https://accounts.google.com")
+	viper.SetDefault("GOOGLE_REDIRECT_URL", "http://localhost:3000/auth/google/callback")
+	viper.SetDefault("MFA_ISSUER", "TrueForce AI")
+	viper.SetDefault("MFA_CHALLENGE_TTL", "5m")
+	viper.SetDefault("SCIM_BEARER_TOKEN", "")
+	viper.SetDefault("PERMISSION_CACHE_TTL", "5m")
+	viper.SetDefault("CASE_STATS_CACHE_TTL", "1m")
+	viper.SetDefault("PASSWORD_HASH_ALGORITHM", "argon2id")
+	viper.SetDefault("PASSWORD_ARGON2_MEMORY", 65536)
+	viper.SetDefault("PASSWORD_ARGON2_ITERATIONS", 3)
+	viper.SetDefault("PASSWORD_ARGON2_PARALLELISM", 2)
+	viper.SetDefault("PASSWORD_BCRYPT_COST", 12)
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Config file not found, relying on ENV vars")
 	}
@@ -30,4 +116,30 @@ func Load() {
 	if err := viper.Unmarshal(&Cfg); err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+
+	if Cfg.JWTSecret == "" {
+		log.Fatalf("JWT_SECRET must be set")
+	}
+	Cfg.TrustedProxies = splitList(Cfg.TrustedProxies)
+	if Cfg.MFAEncryptionKey == "" {
+		Cfg.MFAEncryptionKey = Cfg.JWTSecret
+	}
+
+	if Cfg.PasswordHashAlgorithm != "argon2id" && Cfg.PasswordHashAlgorithm != "bcrypt" {
+		log.Fatalf("PASSWORD_HASH_ALGORITHM must be argon2id or bcrypt, got %q", Cfg.PasswordHashAlgorithm)
+	}
+}
+
+// splitList trims the entries of a comma-separated setting and drops empty
+// ones, returning nil when none are left.
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			if entry = strings.TrimSpace(entry); entry != "" {
+				list = append(list, entry)
+			}
+		}
+	}
+	return list
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func ConnectDatabase() *gorm.DB {
-	db, err := gorm.Open(postgres.Open(Cfg.DBUrl), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(Cfg.DBUrl), &gorm.Config{TranslateError: true})
 	if err != nil {
 		panic("failed to connect to database")
 	}
@@ -0,0 +1,72 @@
+package clientinfo
+
+import (
+	"context"
+	"strings"
+)
+
+type contextKey struct{}
+
+// Info describes the client that sent a request.
+type Info struct {
+	IPAddress string
+	UserAgent string
+}
+
+func NewContext(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, contextKey{}, info)
+}
+
+// FromContext returns the client of the request, or a zero Info outside of
+// HTTP requests.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(contextKey{}).(Info)
+	return info
+}
+
+// DeviceInfo summarises the user agent as "<browser> on <platform>" for
+// display in session lists. Unrecognised parts are left out.
+func (i Info) DeviceInfo() string {
+	ua := i.UserAgent
+
+	browser := firstMatch(ua, []match{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	})
+	platform := firstMatch(ua, []match{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	})
+
+	switch {
+	case browser != "" && platform != "":
+		return browser + " on " + platform
+	case browser != "":
+		return browser
+	default:
+		return platform
+	}
+}
+
+type match struct {
+	token string
+	name  string
+}
+
+func firstMatch(ua string, matches []match) string {
+	for _, m := range matches {
+		if strings.Contains(ua, m.token) {
+			return m.name
+		}
+	}
+	return ""
+}
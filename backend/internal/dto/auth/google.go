@@ -0,0 +1,16 @@
+package auth
+
+type GoogleAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+	// Binding is set as a cookie so that only the browser that started the
+	// sign-in can complete it.
+	Binding string `json:"-"`
+}
+
+type GoogleCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+	// Binding is read from the cookie set with the authorization URL.
+	Binding string `json:"-"`
+}
@@ -0,0 +1,5 @@
+package auth
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
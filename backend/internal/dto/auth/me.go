@@ -0,0 +1,12 @@
+package auth
+
+type CurrentUserResponse struct {
+	ID           uint     `json:"id"`
+	Email        string   `json:"email"`
+	FirstName    string   `json:"first_name"`
+	LastName     string   `json:"last_name"`
+	BadgeNumber  string   `json:"badge_number"`
+	DepartmentID *uint    `json:"department_id,omitempty"`
+	Roles        []string `json:"roles"`
+	Permissions  []string `json:"permissions"`
+}
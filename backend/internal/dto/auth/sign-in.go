@@ -5,6 +5,13 @@ type SignInRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// SignInResponse carries either a token pair or, when the user must pass a
+// second factor, an MFA challenge to redeem at /auth/mfa/verify.
 type SignInResponse struct {
-	Token string `json:"token"`
+	*TokenPair
+	MFARequired           bool     `json:"mfa_required"`
+	MFAEnrollmentRequired bool     `json:"mfa_enrollment_required,omitempty"`
+	MFAChallengeToken     string   `json:"mfa_challenge_token,omitempty"`
+	MFAChallengeExpiresIn int      `json:"mfa_challenge_expires_in,omitempty"`
+	RecoveryCodes         []string `json:"recovery_codes,omitempty"`
 }
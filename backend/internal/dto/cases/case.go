@@ -0,0 +1,82 @@
+// Package cases holds the case API types. It is not named after the model
+// because case is a Go keyword.
+package cases
+
+import "time"
+
+type CaseResponse struct {
+	ID            uint         `json:"id"`
+	CaseNumber    string       `json:"case_number"`
+	Title         string       `json:"title"`
+	Description   string       `json:"description"`
+	Location      string       `json:"location"`
+	IncidentDate  *time.Time   `json:"incident_date"`
+	Status        string       `json:"status"`
+	Priority      string       `json:"priority"`
+	CreatedBy     *UserSummary `json:"created_by"`
+	ClosedAt      *time.Time   `json:"closed_at"`
+	ClosedBy      *UserSummary `json:"closed_by"`
+	ClosureReason string       `json:"closure_reason"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type CaseDetailResponse struct {
+	CaseResponse
+	Officers []OfficerSummary  `json:"officers"`
+	Tags     []TagSummary      `json:"tags"`
+	Evidence []EvidenceSummary `json:"evidence"`
+}
+
+type UserSummary struct {
+	ID          uint   `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	BadgeNumber string `json:"badge_number"`
+}
+
+type OfficerSummary struct {
+	UserSummary
+	Role       string    `json:"role"`
+	Notes      string    `json:"notes"`
+	AssignedAt time.Time `json:"assigned_at"`
+}
+
+type TagSummary struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type EvidenceSummary struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	FileType       string    `json:"file_type"`
+	FileSize       int64     `json:"file_size"`
+	IsConfidential bool      `json:"is_confidential"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateCaseRequest opens a case. The case number is generated from the
+// format for case_type, or from the creator's department format or the
+// default format when case_type is empty. The case starts in the initial
+// status of the workflow; priority defaults to models.CasePriorityMedium.
+type CreateCaseRequest struct {
+	CaseType     string     `json:"case_type" binding:"omitempty,max=50"`
+	Title        string     `json:"title" binding:"required,max=200"`
+	Description  string     `json:"description"`
+	Location     string     `json:"location"`
+	IncidentDate *time.Time `json:"incident_date"`
+	Priority     string     `json:"priority"`
+}
+
+// UpdateCaseRequest changes only the fields that are present. The case
+// number cannot change, and the status changes through
+// ChangeCaseStatusRequest.
+type UpdateCaseRequest struct {
+	Title        *string    `json:"title" binding:"omitempty,min=1,max=200"`
+	Description  *string    `json:"description"`
+	Location     *string    `json:"location"`
+	IncidentDate *time.Time `json:"incident_date"`
+	Priority     *string    `json:"priority"`
+}
@@ -0,0 +1,31 @@
+package cases
+
+import (
+	"backend/internal/dto/pagination"
+	"time"
+)
+
+// ListCasesQuery selects a page of the cases the caller may see. Status,
+// priority and tag_id may be repeated to match any of the values; with
+// tag_match=all a case must carry every listed tag. The incident dates are
+// inclusive days. Sort is a comma-separated list of fields, each prefixed
+// with "-" for descending order, such as "-priority,created_at".
+type ListCasesQuery struct {
+	Search       string     `form:"q" binding:"max=100"`
+	Status       []string   `form:"status"`
+	Priority     []string   `form:"priority"`
+	TagIDs       []uint     `form:"tag_id"`
+	TagMatch     string     `form:"tag_match" binding:"omitempty,oneof=any all"`
+	OfficerID    *uint      `form:"officer_id"`
+	DepartmentID *uint      `form:"department_id"`
+	IncidentFrom *time.Time `form:"incident_from" time_format:"2006-01-02"`
+	IncidentTo   *time.Time `form:"incident_to" time_format:"2006-01-02"`
+	Sort         string     `form:"sort" binding:"max=200"`
+	Limit        int        `form:"limit" binding:"omitempty,min=1,max=100"`
+	Page         int        `form:"page" binding:"omitempty,min=1"`
+}
+
+type CaseListResponse struct {
+	Items      []CaseResponse  `json:"items"`
+	Pagination pagination.Meta `json:"pagination"`
+}
@@ -0,0 +1,33 @@
+package cases
+
+import "time"
+
+type CaseNumberFormatResponse struct {
+	ID          uint               `json:"id"`
+	CaseType    string             `json:"case_type"`
+	Prefix      string             `json:"prefix"`
+	Padding     int                `json:"padding"`
+	IncludeYear bool               `json:"include_year"`
+	Department  *DepartmentSummary `json:"department"`
+	IsDefault   bool               `json:"is_default"`
+	Example     string             `json:"example"` // the first number of the current year
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+type DepartmentSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// CaseNumberFormatRequest creates a format or replaces all of its
+// settings. Numbers already issued keep their old form; the sequence
+// carries on where it was.
+type CaseNumberFormatRequest struct {
+	CaseType     string `json:"case_type" binding:"required,max=50"`
+	Prefix       string `json:"prefix" binding:"required,alphanum,max=10"`
+	Padding      int    `json:"padding" binding:"required,min=1,max=10"`
+	IncludeYear  bool   `json:"include_year"`
+	DepartmentID *uint  `json:"department_id"`
+	IsDefault    bool   `json:"is_default"`
+}
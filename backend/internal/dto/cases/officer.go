@@ -0,0 +1,33 @@
+package cases
+
+import "time"
+
+// AssignOfficerRequest assigns an officer to a case. Role is free text,
+// except that a case has a single models.CaseOfficerRoleLead.
+type AssignOfficerRequest struct {
+	OfficerID uint   `json:"officer_id" binding:"required"`
+	Role      string `json:"role" binding:"required,max=50"`
+	Notes     string `json:"notes"`
+}
+
+// UpdateAssignmentRequest changes only the fields that are present.
+type UpdateAssignmentRequest struct {
+	Role  *string `json:"role" binding:"omitempty,min=1,max=50"`
+	Notes *string `json:"notes"`
+}
+
+type ListAssignmentsQuery struct {
+	IncludeHistory bool `form:"include_history"`
+}
+
+// AssignmentResponse is one assignment of an officer to a case.
+// UnassignedAt is set on ended assignments.
+type AssignmentResponse struct {
+	ID           uint         `json:"id"`
+	Officer      *UserSummary `json:"officer"`
+	Role         string       `json:"role"`
+	Notes        string       `json:"notes"`
+	AssignedBy   *UserSummary `json:"assigned_by"`
+	AssignedAt   time.Time    `json:"assigned_at"`
+	UnassignedAt *time.Time   `json:"unassigned_at"`
+}
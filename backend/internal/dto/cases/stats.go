@@ -0,0 +1,77 @@
+package cases
+
+import "time"
+
+// CaseStatsQuery selects the trend window of the case statistics. The
+// window covers whole intervals from the one containing from to the one
+// containing to, which are inclusive days. Without them it ends today and
+// spans twelve intervals.
+type CaseStatsQuery struct {
+	Interval string     `form:"interval" binding:"omitempty,oneof=day week month"`
+	From     *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To       *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// CaseStatsResponse aggregates the cases the caller may see. A case is
+// closed while it has a closed_at, that is while it is in a closing status.
+// Statistics are cached briefly, as of GeneratedAt.
+type CaseStatsResponse struct {
+	Total                  int64             `json:"total"`
+	Open                   int64             `json:"open"`
+	Closed                 int64             `json:"closed"`
+	MeanTimeToCloseSeconds *float64          `json:"mean_time_to_close_seconds"`
+	ByStatus               []StatusCount     `json:"by_status"`
+	ByPriority             []PriorityCount   `json:"by_priority"`
+	ByTag                  []TagCount        `json:"by_tag"`
+	ByDepartment           []DepartmentCount `json:"by_department"`
+	ByOfficer              []OfficerCount    `json:"by_officer"`
+	Trend                  CaseTrend         `json:"trend"`
+	GeneratedAt            time.Time         `json:"generated_at"`
+}
+
+type StatusCount struct {
+	Status   string `json:"status"`
+	IsClosed bool   `json:"is_closed"`
+	Count    int64  `json:"count"`
+}
+
+type PriorityCount struct {
+	Priority string `json:"priority"`
+	Count    int64  `json:"count"`
+}
+
+type TagCount struct {
+	Tag   TagSummary `json:"tag"`
+	Count int64      `json:"count"`
+}
+
+// DepartmentCount counts cases by the department of their creator. Cases
+// whose creator has no department have a nil DepartmentID.
+type DepartmentCount struct {
+	DepartmentID *uint  `json:"department_id"`
+	Name         string `json:"name"`
+	Count        int64  `json:"count"`
+}
+
+// OfficerCount counts the cases an officer is currently assigned to.
+type OfficerCount struct {
+	Officer UserSummary `json:"officer"`
+	Count   int64       `json:"count"`
+}
+
+type CaseTrend struct {
+	Interval string            `json:"interval"`
+	Periods  []CaseTrendPeriod `json:"periods"`
+}
+
+// CaseTrendPeriod counts the cases opened and closed during one interval,
+// in UTC. OpenAtEnd is the number of cases open when it ended. Cases that
+// were reopened count only by their latest closure, if any.
+type CaseTrendPeriod struct {
+	Start                  time.Time `json:"start"`
+	End                    time.Time `json:"end"`
+	Opened                 int64     `json:"opened"`
+	Closed                 int64     `json:"closed"`
+	OpenAtEnd              int64     `json:"open_at_end"`
+	MeanTimeToCloseSeconds *float64  `json:"mean_time_to_close_seconds"`
+}
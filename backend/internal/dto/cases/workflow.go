@@ -0,0 +1,73 @@
+package cases
+
+import "time"
+
+type CaseStatusResponse struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsInitial   bool      `json:"is_initial"`
+	IsClosed    bool      `json:"is_closed"`
+	SortOrder   int       `json:"sort_order"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type StatusSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type CaseTransitionResponse struct {
+	ID                 uint           `json:"id"`
+	Name               string         `json:"name"`
+	FromStatus         *StatusSummary `json:"from_status"`
+	ToStatus           *StatusSummary `json:"to_status"`
+	RequiredPermission string         `json:"required_permission"`
+	RequiresReason     bool           `json:"requires_reason"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+type WorkflowResponse struct {
+	Statuses    []CaseStatusResponse     `json:"statuses"`
+	Transitions []CaseTransitionResponse `json:"transitions"`
+}
+
+// CaseStatusRequest creates a status or replaces all of its settings.
+// Renaming a status renames it on every case in it; is_closed can only
+// change while no case is in it.
+type CaseStatusRequest struct {
+	Name        string `json:"name" binding:"required,max=50"`
+	Description string `json:"description"`
+	IsInitial   bool   `json:"is_initial"`
+	IsClosed    bool   `json:"is_closed"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// CaseTransitionRequest creates a transition or replaces all of its
+// settings. RequiredPermission is a permission code, needed on top of
+// case.edit when set.
+type CaseTransitionRequest struct {
+	Name               string `json:"name" binding:"required,max=50"`
+	FromStatusID       uint   `json:"from_status_id" binding:"required"`
+	ToStatusID         uint   `json:"to_status_id" binding:"required"`
+	RequiredPermission string `json:"required_permission" binding:"omitempty,max=100"`
+	RequiresReason     bool   `json:"requires_reason"`
+}
+
+// ChangeCaseStatusRequest moves a case to another status. The workflow
+// must have a transition from the current status to it.
+type ChangeCaseStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+// AvailableTransitionResponse is a transition the caller may perform on a
+// case right now.
+type AvailableTransitionResponse struct {
+	ID             uint          `json:"id"`
+	Name           string        `json:"name"`
+	ToStatus       StatusSummary `json:"to_status"`
+	RequiresReason bool          `json:"requires_reason"`
+}
@@ -0,0 +1,46 @@
+package department
+
+import "time"
+
+type DepartmentResponse struct {
+	ID                  uint         `json:"id"`
+	Name                string       `json:"name"`
+	Description         string       `json:"description"`
+	EmailDomain         string       `json:"email_domain"`
+	GoogleAutoProvision bool         `json:"google_auto_provision"`
+	Head                *HeadSummary `json:"head"`
+	CreatedAt           time.Time    `json:"created_at"`
+	UpdatedAt           time.Time    `json:"updated_at"`
+}
+
+type DepartmentDetailResponse struct {
+	DepartmentResponse
+	MemberCount int64 `json:"member_count"`
+}
+
+type HeadSummary struct {
+	ID          uint   `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	BadgeNumber string `json:"badge_number"`
+}
+
+type CreateDepartmentRequest struct {
+	Name                string `json:"name" binding:"required,max=100"`
+	Description         string `json:"description"`
+	EmailDomain         string `json:"email_domain" binding:"omitempty,fqdn,max=100"`
+	GoogleAutoProvision bool   `json:"google_auto_provision"`
+}
+
+type UpdateDepartmentRequest struct {
+	Name                *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description         *string `json:"description"`
+	EmailDomain         *string `json:"email_domain" binding:"omitempty,fqdn,max=100"`
+	GoogleAutoProvision *bool   `json:"google_auto_provision"`
+}
+
+// SetHeadRequest names the department head, or removes the head when
+// user_id is null.
+type SetHeadRequest struct {
+	UserID *uint `json:"user_id"`
+}
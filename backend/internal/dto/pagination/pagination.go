@@ -0,0 +1,12 @@
+package pagination
+
+// Meta describes where a page sits in a list. Offset pages carry Page,
+// Total and TotalPages; cursor pages carry NextCursor while HasMore is set.
+type Meta struct {
+	Limit      int    `json:"limit"`
+	Page       int    `json:"page,omitempty"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages *int64 `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
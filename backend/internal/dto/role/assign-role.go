@@ -0,0 +1,14 @@
+package role
+
+import "time"
+
+type AssignRoleRequest struct {
+	RoleID uint `json:"role_id" binding:"required"`
+}
+
+type UserRoleResponse struct {
+	Role         RoleRef   `json:"role"`
+	Level        int       `json:"level"`
+	AssignedByID *uint     `json:"assigned_by_id,omitempty"`
+	AssignedAt   time.Time `json:"assigned_at"`
+}
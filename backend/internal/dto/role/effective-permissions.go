@@ -0,0 +1,20 @@
+package role
+
+type RoleRef struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type EffectivePermission struct {
+	ID            uint      `json:"id"`
+	Code          string    `json:"code"`
+	Name          string    `json:"name"`
+	Direct        bool      `json:"direct"`
+	InheritedFrom []RoleRef `json:"inherited_from"`
+}
+
+type EffectivePermissionsResponse struct {
+	Role        RoleRef               `json:"role"`
+	Level       int                   `json:"level"`
+	Permissions []EffectivePermission `json:"permissions"`
+}
@@ -0,0 +1,33 @@
+package role
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PermissionResponse struct {
+	ID          uint   `json:"id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type PermissionCategoryResponse struct {
+	ID          uint                 `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Permissions []PermissionResponse `json:"permissions"`
+}
+
+type GrantPermissionRequest struct {
+	PermissionID uint `json:"permission_id" binding:"required"`
+}
+
+type RoleChangeResponse struct {
+	ID           uint            `json:"id"`
+	Action       string          `json:"action"`
+	ChangedByID  uint            `json:"changed_by_id"`
+	PreviousData json.RawMessage `json:"previous_data"`
+	NewData      json.RawMessage `json:"new_data"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
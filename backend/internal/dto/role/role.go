@@ -0,0 +1,35 @@
+package role
+
+import "time"
+
+type RoleResponse struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	ParentRoleID *uint     `json:"parent_role_id"`
+	Level        int       `json:"level"`
+	IsSystemRole bool      `json:"is_system_role"`
+	MFARequired  bool      `json:"mfa_required"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type RoleDetailResponse struct {
+	RoleResponse
+	Permissions []PermissionResponse `json:"permissions"`
+}
+
+type CreateRoleRequest struct {
+	Name         string `json:"name" binding:"required,max=50"`
+	Description  string `json:"description"`
+	ParentRoleID *uint  `json:"parent_role_id"`
+	Level        int    `json:"level" binding:"required,min=1"`
+	MFARequired  bool   `json:"mfa_required"`
+}
+
+type UpdateRoleRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Description *string `json:"description"`
+	Level       *int    `json:"level" binding:"omitempty,min=1"`
+	MFARequired *bool   `json:"mfa_required"`
+}
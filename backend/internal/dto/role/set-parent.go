@@ -0,0 +1,5 @@
+package role
+
+type SetParentRequest struct {
+	ParentRoleID *uint `json:"parent_role_id"` // null detaches the role from its parent
+}
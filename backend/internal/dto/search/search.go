@@ -0,0 +1,50 @@
+package search
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/dto/pagination"
+)
+
+// SearchQuery is a full-text search over cases and their evidence. Q uses
+// web search syntax: words must all match, "quoted phrases" match in order,
+// OR allows either side and a leading - excludes a word.
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+}
+
+// SearchResponse lists the matching cases, best matches first.
+type SearchResponse struct {
+	Items      []CaseHit       `json:"items"`
+	Pagination pagination.Meta `json:"pagination"`
+}
+
+// CaseHit is a matching case with its best matching evidence, at most five.
+// Highlights are HTML-escaped snippets with the matched words wrapped in
+// <mark>, present only for the fields that matched.
+type CaseHit struct {
+	Case       cases.CaseResponse `json:"case"`
+	Rank       float64            `json:"rank"`
+	Highlights CaseHighlights     `json:"highlights"`
+	Evidence   []EvidenceHit      `json:"evidence"`
+}
+
+type CaseHighlights struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Location    string `json:"location,omitempty"`
+}
+
+type EvidenceHit struct {
+	ID         uint               `json:"id"`
+	Title      string             `json:"title"`
+	Rank       float64            `json:"rank"`
+	Highlights EvidenceHighlights `json:"highlights"`
+}
+
+type EvidenceHighlights struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Metadata    string `json:"metadata,omitempty"`
+}
@@ -0,0 +1,13 @@
+package session
+
+import "time"
+
+type SessionResponse struct {
+	ID           uint      `json:"id"`
+	DeviceInfo   string    `json:"device_info"`
+	IPAddress    string    `json:"ip_address"`
+	UserAgent    string    `json:"user_agent"`
+	LastActivity time.Time `json:"last_activity"`
+	CreatedAt    time.Time `json:"created_at"`
+	Current      bool      `json:"current"`
+}
@@ -0,0 +1,45 @@
+package tag
+
+import "time"
+
+// TagResponse carries the number of cases visible to the caller that have
+// the tag, for filter lists.
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CaseCount int64     `json:"case_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateTagRequest creates a tag. Color is a hex color, #RGB or #RRGGBB,
+// and is stored as #RRGGBB.
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"required"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Color *string `json:"color" binding:"omitempty,min=1"`
+}
+
+// MergeTagRequest merges the tag in the path into TargetTagID: its cases
+// are tagged with the target instead and the tag is deleted.
+type MergeTagRequest struct {
+	TargetTagID uint `json:"target_tag_id" binding:"required"`
+}
+
+type AttachTagRequest struct {
+	TagID uint `json:"tag_id" binding:"required"`
+}
+
+// CaseTagResponse is a tag attached to a case.
+type CaseTagResponse struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	Color      string    `json:"color"`
+	AttachedBy *uint     `json:"attached_by_id"`
+	AttachedAt time.Time `json:"attached_at"`
+}
@@ -0,0 +1,39 @@
+package user
+
+// ImportUsersQuery controls a CSV import. A dry run validates every row and
+// reports what would be created without writing anything.
+type ImportUsersQuery struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// ImportRowError describes one problem with a CSV row. Row is the line
+// number in the file, counting the header as line 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportedUser struct {
+	Row          int      `json:"row"`
+	ID           uint     `json:"id,omitempty"` // zero on dry runs
+	Email        string   `json:"email"`
+	FirstName    string   `json:"first_name"`
+	LastName     string   `json:"last_name"`
+	BadgeNumber  string   `json:"badge_number"`
+	DepartmentID *uint    `json:"department_id"`
+	Roles        []string `json:"roles"`
+}
+
+// ImportUsersResponse is the import report. Nothing is created unless every
+// row is valid, so Created is zero whenever Errors is not empty.
+// InvitationsQueued invitation emails are sent after the response; the ones
+// that fail show up in the audit log as user_invitation_failed.
+type ImportUsersResponse struct {
+	DryRun            bool             `json:"dry_run"`
+	TotalRows         int              `json:"total_rows"`
+	Created           int              `json:"created"`
+	Errors            []ImportRowError `json:"errors"`
+	Users             []ImportedUser   `json:"users"`
+	InvitationsQueued int              `json:"invitations_queued"`
+}
@@ -0,0 +1,38 @@
+package user
+
+import (
+	"backend/internal/dto/pagination"
+	"time"
+)
+
+// ListUsersQuery selects a page of the user directory. Without page the
+// list is cursor paginated: pass back next_cursor to continue. With page it
+// is offset paginated and includes totals. Sort is a field name, prefixed
+// with "-" for descending order.
+type ListUsersQuery struct {
+	Search       string `form:"q" binding:"max=100"`
+	DepartmentID *uint  `form:"department_id"`
+	RoleID       *uint  `form:"role_id"`
+	IsActive     *bool  `form:"is_active"`
+	Sort         string `form:"sort"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Page         int    `form:"page" binding:"omitempty,min=1,excluded_with=Cursor"`
+	Cursor       string `form:"cursor"`
+}
+
+type UserListItem struct {
+	ID           uint       `json:"id"`
+	Email        string     `json:"email"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	BadgeNumber  string     `json:"badge_number"`
+	DepartmentID *uint      `json:"department_id"`
+	IsActive     bool       `json:"is_active"`
+	LastLogin    *time.Time `json:"last_login"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type UserListResponse struct {
+	Items      []UserListItem  `json:"items"`
+	Pagination pagination.Meta `json:"pagination"`
+}
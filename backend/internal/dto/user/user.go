@@ -0,0 +1,54 @@
+package user
+
+import "time"
+
+type UserResponse struct {
+	ID              uint       `json:"id"`
+	Email           string     `json:"email"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	PhoneNumber     string     `json:"phone_number"`
+	BadgeNumber     string     `json:"badge_number"`
+	DepartmentID    *uint      `json:"department_id"`
+	ProfileImageURL string     `json:"profile_image_url"`
+	IsActive        bool       `json:"is_active"`
+	MFAEnabled      bool       `json:"mfa_enabled"`
+	LastLogin       *time.Time `json:"last_login"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+}
+
+type UserDetailResponse struct {
+	UserResponse
+	Department *DepartmentSummary `json:"department"`
+	Roles      []RoleSummary      `json:"roles"`
+}
+
+type DepartmentSummary struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type RoleSummary struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Level int    `json:"level"`
+}
+
+// UpdateUserRequest changes only the fields that are present. An empty
+// badge_number removes the badge.
+type UpdateUserRequest struct {
+	FirstName       *string `json:"first_name" binding:"omitempty,min=1,max=50"`
+	LastName        *string `json:"last_name" binding:"omitempty,min=1,max=50"`
+	Email           *string `json:"email" binding:"omitempty,email,max=100"`
+	PhoneNumber     *string `json:"phone_number" binding:"omitempty,max=20"`
+	BadgeNumber     *string `json:"badge_number" binding:"omitempty,max=20"`
+	ProfileImageURL *string `json:"profile_image_url" binding:"omitempty,url"`
+}
+
+// TransferDepartmentRequest moves a user to another department, or out of
+// any department when department_id is null.
+type TransferDepartmentRequest struct {
+	DepartmentID *uint `json:"department_id"`
+}
@@ -1,22 +1,150 @@
 package handler
 
 import (
-	"backend/internal/middleware"
 	"backend/internal/dto/auth"
+	"backend/internal/middleware"
 	"backend/internal/service"
-	"github.com/gin-gonic/gin"
 	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
 )
 
-func Login(c *gin.Context) {
+type AuthHandler struct {
+	authService          service.AuthService
+	passwordResetService service.PasswordResetService
+}
+
+func NewAuthHandler(authService service.AuthService, passwordResetService service.PasswordResetService) *AuthHandler {
+	return &AuthHandler{
+		authService:          authService,
+		passwordResetService: passwordResetService,
+	}
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
 	var req auth.SignInRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		middleware.JSON(c, http.StatusBadRequest, "error", err.Error(), nil)
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
 		return
 	}
 
-	user, err := service.UserService.GetUserByEmail(req.Email)
+	res, err := h.authService.SignIn(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
 
-	
+	respondSignIn(c, res)
 }
 
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req auth.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.authService.VerifyMFA(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Signed in successfully", res, nil)
+}
+
+// respondSignIn writes the result of the first sign-in step, which is
+// either tokens or an MFA challenge.
+func respondSignIn(c *gin.Context, res *auth.SignInResponse) {
+	if res.MFARequired {
+		middleware.JSON(c, http.StatusOK, "Multi-factor authentication required", res, nil)
+		return
+	}
+	middleware.JSON(c, http.StatusOK, "Signed in successfully", res, nil)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req auth.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.authService.Refresh(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Token refreshed successfully", res, nil)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req auth.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Logged out successfully", nil, nil)
+}
+
+func (h *AuthHandler) Me(c *gin.Context) {
+	p := middleware.CurrentPrincipal(c)
+
+	roles := make([]string, 0, len(p.User.UserRoles))
+	for _, userRole := range p.User.UserRoles {
+		if userRole.Role != nil {
+			roles = append(roles, userRole.Role.Name)
+		}
+	}
+	permissions := p.PermissionCodes()
+	sort.Strings(permissions)
+
+	middleware.JSON(c, http.StatusOK, "Current user", auth.CurrentUserResponse{
+		ID:           p.User.ID,
+		Email:        p.User.Email,
+		FirstName:    p.User.FirstName,
+		LastName:     p.User.LastName,
+		BadgeNumber:  p.User.BadgeNumber,
+		DepartmentID: p.User.DepartmentID,
+		Roles:        roles,
+		Permissions:  permissions,
+	}, nil)
+}
+
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req auth.ForgetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.passwordResetService.RequestReset(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "If an account exists for this email, a password reset link has been sent", nil, nil)
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req auth.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.passwordResetService.ResetPassword(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Password reset successfully", nil, nil)
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseHandler struct {
+	caseService service.CaseService
+}
+
+func NewCaseHandler(caseService service.CaseService) *CaseHandler {
+	return &CaseHandler{
+		caseService: caseService,
+	}
+}
+
+func (h *CaseHandler) ListCases(c *gin.Context) {
+	var query cases.ListCasesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid query parameters", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseService.ListCases(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Cases", res, nil)
+}
+
+func (h *CaseHandler) GetCase(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.caseService.GetCase(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case details", res, nil)
+}
+
+func (h *CaseHandler) CreateCase(c *gin.Context) {
+	var req cases.CreateCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseService.CreateCase(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Case created successfully", res, nil)
+}
+
+func (h *CaseHandler) UpdateCase(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.UpdateCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseService.UpdateCase(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case updated successfully", res, nil)
+}
+
+func (h *CaseHandler) DeleteCase(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.caseService.DeleteCase(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case deleted successfully", nil, nil)
+}
+
+func (h *CaseHandler) ChangeStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.ChangeCaseStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseService.ChangeStatus(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case status changed successfully", res, nil)
+}
+
+func (h *CaseHandler) AvailableTransitions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.caseService.AvailableTransitions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Available transitions", res, nil)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseNumberHandler struct {
+	caseNumberService service.CaseNumberService
+}
+
+func NewCaseNumberHandler(caseNumberService service.CaseNumberService) *CaseNumberHandler {
+	return &CaseNumberHandler{
+		caseNumberService: caseNumberService,
+	}
+}
+
+func (h *CaseNumberHandler) ListFormats(c *gin.Context) {
+	res, err := h.caseNumberService.ListFormats(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case number formats", res, nil)
+}
+
+func (h *CaseNumberHandler) CreateFormat(c *gin.Context) {
+	var req cases.CaseNumberFormatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseNumberService.CreateFormat(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Case number format created successfully", res, nil)
+}
+
+func (h *CaseNumberHandler) UpdateFormat(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.CaseNumberFormatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseNumberService.UpdateFormat(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case number format updated successfully", res, nil)
+}
+
+func (h *CaseNumberHandler) DeleteFormat(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.caseNumberService.DeleteFormat(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case number format deleted successfully", nil, nil)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseOfficerHandler struct {
+	caseOfficerService service.CaseOfficerService
+}
+
+func NewCaseOfficerHandler(caseOfficerService service.CaseOfficerService) *CaseOfficerHandler {
+	return &CaseOfficerHandler{
+		caseOfficerService: caseOfficerService,
+	}
+}
+
+func (h *CaseOfficerHandler) ListAssignments(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var query cases.ListAssignmentsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid query parameters", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseOfficerService.ListAssignments(c.Request.Context(), id, query.IncludeHistory)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case officers", res, nil)
+}
+
+func (h *CaseOfficerHandler) AssignOfficer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.AssignOfficerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseOfficerService.AssignOfficer(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Officer assigned successfully", res, nil)
+}
+
+func (h *CaseOfficerHandler) UpdateAssignment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	officerID, ok := parseIDParam(c, "officerId")
+	if !ok {
+		return
+	}
+
+	var req cases.UpdateAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseOfficerService.UpdateAssignment(c.Request.Context(), id, officerID, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Assignment updated successfully", res, nil)
+}
+
+func (h *CaseOfficerHandler) UnassignOfficer(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	officerID, ok := parseIDParam(c, "officerId")
+	if !ok {
+		return
+	}
+
+	if err := h.caseOfficerService.UnassignOfficer(c.Request.Context(), id, officerID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Officer unassigned successfully", nil, nil)
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseStatsHandler struct {
+	caseStatsService service.CaseStatsService
+}
+
+func NewCaseStatsHandler(caseStatsService service.CaseStatsService) *CaseStatsHandler {
+	return &CaseStatsHandler{
+		caseStatsService: caseStatsService,
+	}
+}
+
+func (h *CaseStatsHandler) GetStats(c *gin.Context) {
+	var query cases.CaseStatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid query parameters", nil, err.Error())
+		return
+	}
+
+	res, err := h.caseStatsService.GetStats(c.Request.Context(), query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case statistics", res, nil)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseWorkflowHandler struct {
+	workflowService service.CaseWorkflowService
+}
+
+func NewCaseWorkflowHandler(workflowService service.CaseWorkflowService) *CaseWorkflowHandler {
+	return &CaseWorkflowHandler{
+		workflowService: workflowService,
+	}
+}
+
+func (h *CaseWorkflowHandler) GetWorkflow(c *gin.Context) {
+	res, err := h.workflowService.GetWorkflow(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case workflow", res, nil)
+}
+
+func (h *CaseWorkflowHandler) CreateStatus(c *gin.Context) {
+	var req cases.CaseStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.workflowService.CreateStatus(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Case status created successfully", res, nil)
+}
+
+func (h *CaseWorkflowHandler) UpdateStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.CaseStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.workflowService.UpdateStatus(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case status updated successfully", res, nil)
+}
+
+func (h *CaseWorkflowHandler) DeleteStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.workflowService.DeleteStatus(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case status deleted successfully", nil, nil)
+}
+
+func (h *CaseWorkflowHandler) CreateTransition(c *gin.Context) {
+	var req cases.CaseTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.workflowService.CreateTransition(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Case transition created successfully", res, nil)
+}
+
+func (h *CaseWorkflowHandler) UpdateTransition(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req cases.CaseTransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.workflowService.UpdateTransition(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case transition updated successfully", res, nil)
+}
+
+func (h *CaseWorkflowHandler) DeleteTransition(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.workflowService.DeleteTransition(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Case transition deleted successfully", nil, nil)
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"backend/internal/dto/department"
+	"backend/internal/dto/user"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DepartmentHandler struct {
+	departmentService service.DepartmentService
+}
+
+func NewDepartmentHandler(departmentService service.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{
+		departmentService: departmentService,
+	}
+}
+
+func (h *DepartmentHandler) ListDepartments(c *gin.Context) {
+	res, err := h.departmentService.ListDepartments(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Departments", res, nil)
+}
+
+func (h *DepartmentHandler) GetDepartment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.departmentService.GetDepartment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Department details", res, nil)
+}
+
+func (h *DepartmentHandler) CreateDepartment(c *gin.Context) {
+	var req department.CreateDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.departmentService.CreateDepartment(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Department created successfully", res, nil)
+}
+
+func (h *DepartmentHandler) UpdateDepartment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req department.UpdateDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.departmentService.UpdateDepartment(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Department updated successfully", res, nil)
+}
+
+func (h *DepartmentHandler) DeleteDepartment(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.departmentService.DeleteDepartment(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Department deleted successfully", nil, nil)
+}
+
+func (h *DepartmentHandler) ListMembers(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var query user.ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid query parameters", nil, err.Error())
+		return
+	}
+
+	res, err := h.departmentService.ListMembers(c.Request.Context(), id, query)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Department members", res, nil)
+}
+
+func (h *DepartmentHandler) SetHead(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req department.SetHeadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.departmentService.SetHead(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Department head updated successfully", res, nil)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorStatuses maps service errors to the HTTP status returned to clients.
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{service.ErrInvalidCredentials, http.StatusUnauthorized},
+	{service.ErrInvalidRefreshToken, http.StatusUnauthorized},
+	{service.ErrRefreshTokenReused, http.StatusUnauthorized},
+	{service.ErrInvalidAccessToken, http.StatusUnauthorized},
+	{service.ErrUnauthenticated, http.StatusUnauthorized},
+	{service.ErrUserInactive, http.StatusForbidden},
+	{service.ErrForbidden, http.StatusForbidden},
+	{service.ErrEmailTaken, http.StatusConflict},
+	{service.ErrRoleNotFound, http.StatusNotFound},
+	{service.ErrParentRoleNotFound, http.StatusUnprocessableEntity},
+	{service.ErrRoleHierarchyCycle, http.StatusUnprocessableEntity},
+	{service.ErrParentRoleNotSenior, http.StatusUnprocessableEntity},
+	{service.ErrRoleNotManageable, http.StatusForbidden},
+	{service.ErrRoleEscalation, http.StatusForbidden},
+	{service.ErrUserOutranksActor, http.StatusForbidden},
+	{service.ErrRoleAlreadyAssigned, http.StatusConflict},
+	{service.ErrRoleNotAssigned, http.StatusNotFound},
+	{service.ErrUserNotFound, http.StatusNotFound},
+	{service.ErrRoleNameTaken, http.StatusConflict},
+	{service.ErrSystemRoleProtected, http.StatusForbidden},
+	{service.ErrRoleInUse, http.StatusConflict},
+	{service.ErrPermissionNotFound, http.StatusNotFound},
+	{service.ErrPermissionEscalation, http.StatusForbidden},
+	{service.ErrPermissionAlreadyGranted, http.StatusConflict},
+	{service.ErrPermissionNotGranted, http.StatusNotFound},
+	{service.ErrInvalidResetToken, http.StatusBadRequest},
+	{service.ErrGoogleSignInDisabled, http.StatusNotFound},
+	{service.ErrInvalidOIDCState, http.StatusBadRequest},
+	{service.ErrGoogleSignInFailed, http.StatusUnauthorized},
+	{service.ErrGoogleAccountNotLinked, http.StatusForbidden},
+	{service.ErrGoogleAccountMismatch, http.StatusConflict},
+	{service.ErrInvalidMFAChallenge, http.StatusUnauthorized},
+	{service.ErrInvalidMFACode, http.StatusUnauthorized},
+	{service.ErrMFAAlreadyEnabled, http.StatusConflict},
+	{service.ErrMFANotEnabled, http.StatusConflict},
+	{service.ErrMFARequiredByRole, http.StatusForbidden},
+	{service.ErrSessionNotFound, http.StatusNotFound},
+	{service.ErrTooManyLoginAttempts, http.StatusTooManyRequests},
+	{service.ErrAccountLocked, http.StatusLocked},
+	{service.ErrBadgeNumberTaken, http.StatusConflict},
+	{service.ErrUserNotManageable, http.StatusForbidden},
+	{service.ErrCannotDeactivateSelf, http.StatusForbidden},
+	{service.ErrCannotReactivateSelf, http.StatusForbidden},
+	{service.ErrCannotTransferSelf, http.StatusForbidden},
+	{service.ErrDepartmentNotFound, http.StatusNotFound},
+	{service.ErrInvalidCursor, http.StatusBadRequest},
+	{service.ErrInvalidSort, http.StatusBadRequest},
+	{service.ErrInvalidDateRange, http.StatusBadRequest},
+	{service.ErrStatsRangeTooLong, http.StatusBadRequest},
+	{service.ErrDepartmentNameTaken, http.StatusConflict},
+	{service.ErrDepartmentInUse, http.StatusConflict},
+	{service.ErrDepartmentDomainRequired, http.StatusUnprocessableEntity},
+	{service.ErrHeadNotMember, http.StatusUnprocessableEntity},
+	{service.ErrInvalidCSV, http.StatusBadRequest},
+	{service.ErrImportConflict, http.StatusConflict},
+	{service.ErrCaseNotFound, http.StatusNotFound},
+	{service.ErrCaseNumberTaken, http.StatusConflict},
+	{service.ErrInvalidCaseStatus, http.StatusUnprocessableEntity},
+	{service.ErrInvalidCasePriority, http.StatusUnprocessableEntity},
+	{service.ErrCaseNumberFormatNotFound, http.StatusNotFound},
+	{service.ErrCaseTypeRequired, http.StatusUnprocessableEntity},
+	{service.ErrCaseTypeTaken, http.StatusConflict},
+	{service.ErrCasePrefixTaken, http.StatusConflict},
+	{service.ErrDepartmentHasFormat, http.StatusConflict},
+	{service.ErrUnknownCaseType, http.StatusUnprocessableEntity},
+	{service.ErrNoCaseNumberFormat, http.StatusUnprocessableEntity},
+	{service.ErrCaseNumberExhausted, http.StatusConflict},
+	{service.ErrCaseStatusNotFound, http.StatusNotFound},
+	{service.ErrCaseStatusNameTaken, http.StatusConflict},
+	{service.ErrCaseStatusInUse, http.StatusConflict},
+	{service.ErrClosingStatusInUse, http.StatusConflict},
+	{service.ErrInitialStatusConflict, http.StatusConflict},
+	{service.ErrNoInitialStatus, http.StatusUnprocessableEntity},
+	{service.ErrCaseTransitionNotFound, http.StatusNotFound},
+	{service.ErrCaseTransitionExists, http.StatusConflict},
+	{service.ErrInvalidTransition, http.StatusUnprocessableEntity},
+	{service.ErrTransitionNotAllowed, http.StatusUnprocessableEntity},
+	{service.ErrReasonRequired, http.StatusUnprocessableEntity},
+	{service.ErrOfficerInactive, http.StatusUnprocessableEntity},
+	{service.ErrOfficerAlreadyAssigned, http.StatusConflict},
+	{service.ErrOfficerNotAssigned, http.StatusNotFound},
+	{service.ErrCaseHasLead, http.StatusConflict},
+	{service.ErrTagNotFound, http.StatusNotFound},
+	{service.ErrTagNameTaken, http.StatusConflict},
+	{service.ErrInvalidTagColor, http.StatusUnprocessableEntity},
+	{service.ErrMergeSameTag, http.StatusUnprocessableEntity},
+	{service.ErrTagAlreadyAttached, http.StatusConflict},
+	{service.ErrTagNotAttached, http.StatusNotFound},
+}
+
+// respondError writes err using the status registered for it, hiding
+// unexpected errors behind a generic 500.
+func respondError(c *gin.Context, err error) {
+	var details interface{}
+	var throttled *service.LoginThrottleError
+	if errors.As(err, &throttled) {
+		seconds := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		details = gin.H{"retry_after": seconds}
+	}
+
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.err) {
+			middleware.JSON(c, e.status, err.Error(), nil, details)
+			return
+		}
+	}
+
+	log.Printf("[ERROR] %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	middleware.JSON(c, http.StatusInternalServerError, "Internal Server Error", nil, nil)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+)
+
+// googleStateCookie binds a Google sign-in attempt to the browser that
+// started it.
+const googleStateCookie = "google_sign_in_state"
+
+type GoogleAuthHandler struct {
+	googleAuthService service.GoogleAuthService
+	secureCookies     bool
+}
+
+// NewGoogleAuthHandler creates the handler. secureCookies marks the state
+// cookie Secure and should be set whenever the API is served over HTTPS.
+func NewGoogleAuthHandler(googleAuthService service.GoogleAuthService, secureCookies bool) *GoogleAuthHandler {
+	return &GoogleAuthHandler{
+		googleAuthService: googleAuthService,
+		secureCookies:     secureCookies,
+	}
+}
+
+func (h *GoogleAuthHandler) Authorize(c *gin.Context) {
+	res, err := h.googleAuthService.AuthorizationURL(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.setStateCookie(c, res.Binding, int(service.OIDCLoginStateTTL.Seconds()))
+	middleware.JSON(c, http.StatusOK, "Google authorization URL", res, nil)
+}
+
+func (h *GoogleAuthHandler) Callback(c *gin.Context) {
+	var req auth.GoogleCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+	req.Binding, _ = c.Cookie(googleStateCookie)
+
+	// The state can only be redeemed once, so the cookie is spent either way.
+	h.setStateCookie(c, "", -1)
+
+	res, err := h.googleAuthService.SignIn(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondSignIn(c, res)
+}
+
+// setStateCookie scopes the cookie to the Google sign-in routes, which share
+// the parent path of the current one.
+func (h *GoogleAuthHandler) setStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(googleStateCookie, value, maxAge, path.Dir(c.Request.URL.Path), "", h.secureCookies, true)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoginThrottleHandler struct {
+	loginThrottleService service.LoginThrottleService
+}
+
+func NewLoginThrottleHandler(loginThrottleService service.LoginThrottleService) *LoginThrottleHandler {
+	return &LoginThrottleHandler{
+		loginThrottleService: loginThrottleService,
+	}
+}
+
+func (h *LoginThrottleHandler) Unlock(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.loginThrottleService.Unlock(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Account unlocked successfully", nil, nil)
+}
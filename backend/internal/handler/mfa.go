@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MFAHandler struct {
+	mfaService service.MFAService
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// BeginChallengeEnrollment starts enrollment for a user whose role requires
+// MFA, authenticated by the challenge from their sign-in attempt.
+func (h *MFAHandler) BeginChallengeEnrollment(c *gin.Context) {
+	var req auth.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.mfaService.BeginChallengeEnrollment(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Scan the provisioning URI, then verify a code to finish signing in", res, nil)
+}
+
+func (h *MFAHandler) BeginEnrollment(c *gin.Context) {
+	res, err := h.mfaService.BeginEnrollment(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Scan the provisioning URI, then confirm a code to enable MFA", res, nil)
+}
+
+func (h *MFAHandler) ConfirmEnrollment(c *gin.Context) {
+	var req auth.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.mfaService.ConfirmEnrollment(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "MFA enabled; store these recovery codes safely", res, nil)
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req auth.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Recovery codes regenerated; previous codes no longer work", res, nil)
+}
+
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req auth.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "MFA disabled", nil, nil)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"backend/internal/dto/user"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize caps the CSV upload for bulk onboarding.
+const maxImportFileSize = 5 << 20
+
+type OnboardingHandler struct {
+	onboardingService service.OnboardingService
+}
+
+func NewOnboardingHandler(onboardingService service.OnboardingService) *OnboardingHandler {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// ImportUsers accepts the CSV either as the "file" field of a multipart
+// form or as a text/csv request body.
+func (h *OnboardingHandler) ImportUsers(c *gin.Context) {
+	var query user.ImportUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid query parameters", nil, err.Error())
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+
+	var file io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+			return
+		}
+		f, err := header.Open()
+		if err != nil {
+			middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+			return
+		}
+		defer f.Close()
+		file = f
+	}
+
+	res, err := h.onboardingService.ImportUsers(c.Request.Context(), file, query.DryRun)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	switch {
+	case len(res.Errors) > 0 && !res.DryRun:
+		middleware.JSON(c, http.StatusUnprocessableEntity, "Import has invalid rows, no users were created", res, nil)
+	case res.DryRun:
+		middleware.JSON(c, http.StatusOK, "Import validated", res, nil)
+	default:
+		middleware.JSON(c, http.StatusCreated, "Users imported successfully", res, nil)
+	}
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam reads a numeric path parameter, writing a 400 response and
+// returning false when it is not a valid ID.
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 64)
+	if err != nil || id == 0 {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid "+name, nil, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
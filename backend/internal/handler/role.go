@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"backend/internal/dto/role"
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+func (h *RoleHandler) GetEffectivePermissions(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.roleService.GetEffectivePermissions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Effective role permissions", res, nil)
+}
+
+func (h *RoleHandler) SetParent(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req role.SetParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.roleService.SetParent(c.Request.Context(), id, req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Parent role updated successfully", nil, nil)
+}
+
+func (h *RoleHandler) ListUserRoles(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.roleService.ListUserRoles(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "User roles", res, nil)
+}
+
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req role.AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.roleService.AssignRole(c.Request.Context(), userID, req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Role assigned successfully", nil, nil)
+}
+
+func (h *RoleHandler) UnassignRole(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	roleID, ok := parseIDParam(c, "roleId")
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.UnassignRole(c.Request.Context(), userID, roleID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Role unassigned successfully", nil, nil)
+}
+
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	res, err := h.roleService.ListRoles(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Roles", res, nil)
+}
+
+func (h *RoleHandler) GetRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.roleService.GetRole(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Role", res, nil)
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var req role.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.roleService.CreateRole(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Role created successfully", res, nil)
+}
+
+func (h *RoleHandler) UpdateRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req role.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	res, err := h.roleService.UpdateRole(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Role updated successfully", res, nil)
+}
+
+func (h *RoleHandler) DeleteRole(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.DeleteRole(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Role deleted successfully", nil, nil)
+}
+
+func (h *RoleHandler) GetRoleHistory(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.roleService.GetRoleHistory(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Role change history", res, nil)
+}
+
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	res, err := h.roleService.ListPermissions(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Permissions", res, nil)
+}
+
+func (h *RoleHandler) GrantPermission(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req role.GrantPermissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.JSON(c, http.StatusBadRequest, "Invalid request body", nil, err.Error())
+		return
+	}
+
+	if err := h.roleService.GrantPermission(c.Request.Context(), id, req); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusCreated, "Permission granted successfully", nil, nil)
+}
+
+func (h *RoleHandler) RevokePermission(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	permissionID, ok := parseIDParam(c, "permissionId")
+	if !ok {
+		return
+	}
+
+	if err := h.roleService.RevokePermission(c.Request.Context(), id, permissionID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Permission revoked successfully", nil, nil)
+}
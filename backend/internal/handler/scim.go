@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"backend/internal/scim"
+	"backend/internal/service"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMHandler serves the SCIM 2.0 provisioning API. Responses use the
+// SCIM media type and error format rather than middleware.JSON.
+type SCIMHandler struct {
+	scimService service.ScimService
+}
+
+func NewSCIMHandler(scimService service.ScimService) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+	}
+}
+
+func (h *SCIMHandler) ServiceProviderConfig(c *gin.Context) {
+	respondSCIM(c, http.StatusOK, scim.ServiceProviderConfig())
+}
+
+func (h *SCIMHandler) ResourceTypes(c *gin.Context) {
+	respondSCIM(c, http.StatusOK, scim.ResourceTypes())
+}
+
+func (h *SCIMHandler) ListUsers(c *gin.Context) {
+	var query scim.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondSCIMError(c, &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: "Invalid query parameters"})
+		return
+	}
+
+	res, err := h.scimService.ListUsers(c.Request.Context(), query)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) GetUser(c *gin.Context) {
+	res, err := h.scimService.GetUser(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) CreateUser(c *gin.Context) {
+	var payload scim.User
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.CreateUser(c.Request.Context(), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusCreated, res)
+}
+
+func (h *SCIMHandler) ReplaceUser(c *gin.Context) {
+	var payload scim.User
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.ReplaceUser(c.Request.Context(), c.Param("id"), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) PatchUser(c *gin.Context) {
+	var payload scim.PatchRequest
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.PatchUser(c.Request.Context(), c.Param("id"), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) DeleteUser(c *gin.Context) {
+	if err := h.scimService.DeleteUser(c.Request.Context(), c.Param("id")); err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) ListGroups(c *gin.Context) {
+	var query scim.ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondSCIMError(c, &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: "Invalid query parameters"})
+		return
+	}
+
+	res, err := h.scimService.ListGroups(c.Request.Context(), query)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) GetGroup(c *gin.Context) {
+	res, err := h.scimService.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) CreateGroup(c *gin.Context) {
+	var payload scim.Group
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.CreateGroup(c.Request.Context(), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusCreated, res)
+}
+
+func (h *SCIMHandler) ReplaceGroup(c *gin.Context) {
+	var payload scim.Group
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.ReplaceGroup(c.Request.Context(), c.Param("id"), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) PatchGroup(c *gin.Context) {
+	var payload scim.PatchRequest
+	if !bindSCIM(c, &payload) {
+		return
+	}
+
+	res, err := h.scimService.PatchGroup(c.Request.Context(), c.Param("id"), payload)
+	if err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	respondSCIM(c, http.StatusOK, res)
+}
+
+func (h *SCIMHandler) DeleteGroup(c *gin.Context) {
+	if err := h.scimService.DeleteGroup(c.Request.Context(), c.Param("id")); err != nil {
+		respondSCIMError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func bindSCIM(c *gin.Context, payload interface{}) bool {
+	if err := c.ShouldBindJSON(payload); err != nil {
+		respondSCIMError(c, &scim.Error{ScimType: scim.ErrTypeInvalidSyntax, Detail: "Invalid request body: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+func respondSCIM(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scim.ContentType)
+	c.JSON(status, body)
+}
+
+// respondSCIMError reports client errors as 400 with their scimType and
+// service errors with the status registered in errorStatuses.
+func respondSCIMError(c *gin.Context, err error) {
+	var scimErr *scim.Error
+	if errors.As(err, &scimErr) {
+		respondSCIM(c, http.StatusBadRequest, scim.NewErrorResponse(http.StatusBadRequest, scimErr.ScimType, scimErr.Detail))
+		return
+	}
+
+	for _, e := range errorStatuses {
+		if errors.Is(err, e.err) {
+			scimType := ""
+			if e.status == http.StatusConflict {
+				scimType = scim.ErrTypeUniqueness
+			}
+			respondSCIM(c, e.status, scim.NewErrorResponse(e.status, scimType, err.Error()))
+			return
+		}
+	}
+
+	log.Printf("[ERROR] %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+	respondSCIM(c, http.StatusInternalServerError, scim.NewErrorResponse(http.StatusInternalServerError, "", "Internal Server Error"))
+}
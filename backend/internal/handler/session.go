@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"backend/internal/middleware"
+	"backend/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionService
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+	}
+}
+
+func (h *SessionHandler) ListSessions(c *gin.Context) {
+	res, err := h.sessionService.ListSessions(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Active sessions", res, nil)
+}
+
+func (h *SessionHandler) RevokeSession(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Session revoked successfully", nil, nil)
+}
+
+func (h *SessionHandler) RevokeOtherSessions(c *gin.Context) {
+	if err := h.sessionService.RevokeOtherSessions(c.Request.Context()); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "All other sessions revoked successfully", nil, nil)
+}
+
+func (h *SessionHandler) ListUserSessions(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.sessionService.ListUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "Active sessions", res, nil)
+}
+
+func (h *SessionHandler) ForceLogout(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.sessionService.EndAllSessions(c.Request.Context(), userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	middleware.JSON(c, http.StatusOK, "User signed out of all sessions", nil, nil)
+}
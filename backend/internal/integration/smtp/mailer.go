@@ -3,6 +3,7 @@ package smtp
 import (
 	"fmt"
 	"net/smtp"
+	"time"
 )
 
 type SMTPConfig struct {
@@ -15,6 +16,10 @@ type SMTPConfig struct {
 
 type Mailer interface {
 	SendWelcomeEmail(to, name string) error
+	SendPasswordResetEmail(to, name, resetURL string, validFor time.Duration) error
+	SendAccountLockedEmail(to, name string, lockedFor time.Duration) error
+	SendInvitationEmail(to, name, setPasswordURL string, validFor time.Duration) error
+	SendCaseAssignmentEmail(to, name, caseNumber, caseTitle, role, caseURL string) error
 }
 
 type smtpMailer struct {
@@ -29,6 +34,54 @@ func (m *smtpMailer) SendWelcomeEmail(to, name string) error {
 	subject := "Welcome to TrueForce AI"
 	body := fmt.Sprintf("Hello %s,\n\nWelcome to TrueForce AI. We're glad to have you on board!", name)
 
+	return m.send(to, subject, body)
+}
+
+func (m *smtpMailer) SendPasswordResetEmail(to, name, resetURL string, validFor time.Duration) error {
+	subject := "Reset your TrueForce AI password"
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"We received a request to reset your TrueForce AI password. Use the link below to choose a new one:\n\n"+
+		"%s\n\n"+
+		"The link expires in %s and can only be used once. If you did not request a reset, you can ignore this email.",
+		name, resetURL, validFor)
+
+	return m.send(to, subject, body)
+}
+
+func (m *smtpMailer) SendAccountLockedEmail(to, name string, lockedFor time.Duration) error {
+	subject := "Your TrueForce AI account has been locked"
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"Your TrueForce AI account was locked for %s after too many failed sign-in attempts.\n\n"+
+		"If this was you, wait and try again, or reset your password. If it was not you, "+
+		"someone may be trying to guess your password: reset it and contact your administrator.",
+		name, lockedFor)
+
+	return m.send(to, subject, body)
+}
+
+func (m *smtpMailer) SendInvitationEmail(to, name, setPasswordURL string, validFor time.Duration) error {
+	subject := "You have been invited to TrueForce AI"
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"An account has been created for you on TrueForce AI. Use the link below to choose your password and sign in:\n\n"+
+		"%s\n\n"+
+		"The link expires in %s and can only be used once. If it expires, use \"Forgot password\" on the sign-in page to get a new one.",
+		name, setPasswordURL, validFor)
+
+	return m.send(to, subject, body)
+}
+
+func (m *smtpMailer) SendCaseAssignmentEmail(to, name, caseNumber, caseTitle, role, caseURL string) error {
+	subject := fmt.Sprintf("You have been assigned to case %s", caseNumber)
+	body := fmt.Sprintf("Hello %s,\n\n"+
+		"You have been assigned to case %s, \"%s\", as %s.\n\n"+
+		"Open the case here:\n\n"+
+		"%s",
+		name, caseNumber, caseTitle, role, caseURL)
+
+	return m.send(to, subject, body)
+}
+
+func (m *smtpMailer) send(to, subject, body string) error {
 	message := fmt.Sprintf("From: %s\r\n"+
 		"To: %s\r\n"+
 		"Subject: %s\r\n"+
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"backend/internal/principal"
+	"backend/internal/service"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "principal"
+
+// Authenticate validates the bearer access token and loads the caller into
+// both the gin context and the request context.
+func Authenticate(authService service.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			JSON(c, http.StatusUnauthorized, "Missing bearer token", nil, nil)
+			c.Abort()
+			return
+		}
+
+		p, err := authService.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInvalidAccessToken):
+				c.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				JSON(c, http.StatusUnauthorized, err.Error(), nil, nil)
+			case errors.Is(err, service.ErrUserInactive):
+				JSON(c, http.StatusForbidden, err.Error(), nil, nil)
+			default:
+				JSON(c, http.StatusInternalServerError, "Internal Server Error", nil, nil)
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set(principalKey, p)
+		c.Request = c.Request.WithContext(principal.NewContext(c.Request.Context(), p))
+		c.Next()
+	}
+}
+
+// CurrentPrincipal returns the caller loaded by Authenticate, or nil on
+// routes that are not behind it.
+func CurrentPrincipal(c *gin.Context) *principal.Principal {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return nil
+	}
+	p, _ := value.(*principal.Principal)
+	return p
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"backend/internal/clientinfo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientInfo records the caller's IP address and user agent in the request
+// context for sessions and audit records.
+func ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		info := clientinfo.Info{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		c.Request = c.Request.WithContext(clientinfo.NewContext(c.Request.Context(), info))
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission aborts with 403 unless the authenticated caller holds
+// every one of the given permission codes. It must run after Authenticate.
+func RequirePermission(codes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p := CurrentPrincipal(c)
+		if p == nil {
+			JSON(c, http.StatusUnauthorized, "Authentication required", nil, nil)
+			c.Abort()
+			return
+		}
+
+		for _, code := range codes {
+			if !p.HasPermission(code) {
+				JSON(c, http.StatusForbidden, "You do not have permission to perform this action", nil, gin.H{"required_permission": code})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"backend/internal/scim"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SCIMAuth admits requests bearing the configured SCIM token. Both tokens
+// are hashed first so the comparison takes the same time whatever their
+// lengths.
+func SCIMAuth(token string) gin.HandlerFunc {
+	want := sha256.Sum256([]byte(token))
+	return func(c *gin.Context) {
+		scheme, got, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		sum := sha256.Sum256([]byte(got))
+		if !found || !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare(sum[:], want[:]) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+			c.Header("Content-Type", scim.ContentType)
+			c.JSON(http.StatusUnauthorized, scim.NewErrorResponse(http.StatusUnauthorized, "", "Invalid bearer token"))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,4 +1,3 @@
-
 package models
 
 import (
@@ -7,15 +6,73 @@ import (
 	"gorm.io/datatypes"
 )
 
+const (
+	AuditLoginFailed     = "login_failed"
+	AuditLoginBlocked    = "login_blocked"
+	AuditAccountLocked   = "account_locked"
+	AuditAccountUnlocked = "account_unlocked"
+	AuditUserCreated     = "user_created"
+	AuditUserUpdated     = "user_updated"
+	AuditUserDeactivated = "user_deactivated"
+	AuditUserReactivated = "user_reactivated"
+	AuditUserDeleted     = "user_deleted"
+	AuditUserRestored    = "user_restored"
+	AuditUserTransferred = "user_department_changed"
+	// AuditUserInvitationFailed records an imported user whose invitation
+	// email could not be sent.
+	AuditUserInvitationFailed = "user_invitation_failed"
+
+	AuditDepartmentCreated     = "department_created"
+	AuditDepartmentUpdated     = "department_updated"
+	AuditDepartmentDeleted     = "department_deleted"
+	AuditDepartmentHeadChanged = "department_head_changed"
+
+	AuditCaseCreated = "case_created"
+	AuditCaseUpdated = "case_updated"
+	AuditCaseDeleted = "case_deleted"
+	// AuditCaseStatusChanged records a workflow transition, with the reason
+	// given for it.
+	AuditCaseStatusChanged = "case_status_changed"
+
+	AuditCaseOfficerAssigned   = "case_officer_assigned"
+	AuditCaseOfficerUpdated    = "case_officer_updated"
+	AuditCaseOfficerUnassigned = "case_officer_unassigned"
+	AuditCaseTagAdded          = "case_tag_added"
+	AuditCaseTagRemoved        = "case_tag_removed"
+
+	AuditCaseNumberFormatCreated = "case_number_format_created"
+	AuditCaseNumberFormatUpdated = "case_number_format_updated"
+	AuditCaseNumberFormatDeleted = "case_number_format_deleted"
+
+	AuditCaseStatusCreated     = "case_status_created"
+	AuditCaseStatusUpdated     = "case_status_updated"
+	AuditCaseStatusDeleted     = "case_status_deleted"
+	AuditCaseTransitionCreated = "case_transition_created"
+	AuditCaseTransitionUpdated = "case_transition_updated"
+	AuditCaseTransitionDeleted = "case_transition_deleted"
+
+	AuditTagCreated = "tag_created"
+	AuditTagUpdated = "tag_updated"
+	AuditTagDeleted = "tag_deleted"
+	AuditTagMerged  = "tag_merged"
+
+	// Role changes made over SCIM have no acting user, which
+	// RoleChangeHistory requires, so they are recorded here instead.
+	AuditRoleCreated        = "role_created"
+	AuditRoleUpdated        = "role_updated"
+	AuditRoleDeleted        = "role_deleted"
+	AuditRoleMembersChanged = "role_members_changed"
+)
+
 type AuditLog struct {
 	ID         uint           `gorm:"primaryKey" json:"id"`
 	UserID     *uint          `json:"user_id,omitempty"`
 	User       *User          `json:"user,omitempty"`
-	Action     string         `gorm:"type:varchar(50);not null" json:"action"`
+	Action     string         `gorm:"type:varchar(50);not null" json:"action"` // one of the Audit* constants
 	EntityType string         `gorm:"type:varchar(50)" json:"entity_type"`
 	EntityID   *uint          `json:"entity_id,omitempty"`
 	Details    datatypes.JSON `gorm:"type:jsonb" json:"details"`
 	IPAddress  string         `gorm:"type:varchar(45)" json:"ip_address"`
 	UserAgent  string         `gorm:"type:text" json:"user_agent"`
 	CreatedAt  time.Time      `json:"created_at"`
-}
\ No newline at end of file
+}
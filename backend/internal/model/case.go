@@ -1,25 +1,35 @@
-
 package models
 
 import (
 	"time"
 )
 
+const (
+	CasePriorityLow      = "Low"
+	CasePriorityMedium   = "Medium"
+	CasePriorityHigh     = "High"
+	CasePriorityCritical = "Critical"
+)
+
+var CasePriorities = []string{CasePriorityLow, CasePriorityMedium, CasePriorityHigh, CasePriorityCritical}
+
 type Case struct {
 	Base
-	CaseNumber   string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"case_number"`
-	Title        string         `gorm:"type:varchar(200);not null" json:"title"`
-	Description  string         `gorm:"type:text" json:"description"`
-	Location     string         `gorm:"type:text" json:"location"`
-	IncidentDate *time.Time     `json:"incident_date,omitempty"`
-	Status       string         `gorm:"type:varchar(50);not null" json:"status"`
-	Priority     string         `gorm:"type:varchar(20)" json:"priority"`
-	CreatedByID  *uint          `json:"created_by_id,omitempty"`
-	CreatedBy    *User          `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-	ClosedAt     *time.Time     `json:"closed_at,omitempty"`
-	ClosedByID   *uint          `json:"closed_by_id,omitempty"`
-	ClosedBy     *User          `gorm:"foreignKey:ClosedByID" json:"closed_by,omitempty"`
-	Officers     []*CaseOfficer `gorm:"foreignKey:CaseID" json:"officers,omitempty"`
-	Tags         []*CaseTag     `gorm:"foreignKey:CaseID" json:"tags,omitempty"`
-	Evidences    []*Evidence    `gorm:"foreignKey:CaseID" json:"evidences,omitempty"`
-}
\ No newline at end of file
+	CaseNumber    string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"case_number"`
+	Title         string         `gorm:"type:varchar(200);not null" json:"title"`
+	Description   string         `gorm:"type:text" json:"description"`
+	Location      string         `gorm:"type:text" json:"location"`
+	IncidentDate  *time.Time     `json:"incident_date,omitempty"`
+	Status        string         `gorm:"type:varchar(50);not null" json:"status"` // a CaseStatus name
+	Priority      string         `gorm:"type:varchar(20)" json:"priority"`
+	CreatedByID   *uint          `json:"created_by_id,omitempty"`
+	CreatedBy     *User          `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+	ClosedAt      *time.Time     `json:"closed_at,omitempty"`
+	ClosedByID    *uint          `json:"closed_by_id,omitempty"`
+	ClosedBy      *User          `gorm:"foreignKey:ClosedByID" json:"closed_by,omitempty"`
+	ClosureReason string         `gorm:"type:text" json:"closure_reason"`
+	Officers      []*CaseOfficer `gorm:"foreignKey:CaseID" json:"officers,omitempty"`
+	Tags          []*CaseTag     `gorm:"foreignKey:CaseID" json:"tags,omitempty"`
+	Evidences     []*Evidence    `gorm:"foreignKey:CaseID" json:"evidences,omitempty"`
+	SearchVector  string         `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B') || setweight(to_tsvector('english', coalesce(location, '')), 'C')) STORED;->:false;<-:false;index:idx_cases_search_vector,type:gin" json:"-"` // maintained by PostgreSQL for full-text search
+}
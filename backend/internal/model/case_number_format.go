@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CaseNumberFormat describes how case numbers are generated for one case
+// type, such as HOM-2025-001 for "Homicide". A format may also be the
+// default for a department's cases, and one format may be the default for
+// everything else.
+type CaseNumberFormat struct {
+	Base
+	CaseType     string      `gorm:"type:varchar(50);not null" json:"case_type"`
+	Prefix       string      `gorm:"type:varchar(10);not null" json:"prefix"`
+	Padding      int         `gorm:"not null;default:3" json:"padding"` // minimum digits of the sequence
+	IncludeYear  bool        `gorm:"not null;default:true" json:"include_year"`
+	DepartmentID *uint       `json:"department_id,omitempty"`
+	Department   *Department `json:"department,omitempty"`
+	IsDefault    bool        `gorm:"not null;default:false" json:"is_default"`
+	CreatedByID  *uint       `json:"created_by_id,omitempty"`
+	CreatedBy    *User       `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
+
+// CaseNumberSequence is the last number issued for a format in a year.
+// Formats without the year in their numbers use Year 0 and never reset.
+type CaseNumberSequence struct {
+	ID        uint              `gorm:"primaryKey" json:"id"`
+	FormatID  uint              `gorm:"not null;uniqueIndex:idx_case_number_sequence_format_year" json:"format_id"`
+	Format    *CaseNumberFormat `json:"format,omitempty"`
+	Year      int               `gorm:"not null;uniqueIndex:idx_case_number_sequence_format_year" json:"year"`
+	LastValue int               `gorm:"not null;default:0" json:"last_value"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -1,14 +1,20 @@
-
 package models
 
+// CaseOfficerRoleLead is the role of a case's lead investigator. A case has
+// at most one active assignment with it.
+const CaseOfficerRoleLead = "Lead Investigator"
+
+// CaseOfficer assigns an officer to a case. Unassigning soft-deletes the
+// row, so deleted rows are the assignment history; an officer has at most
+// one active assignment per case.
 type CaseOfficer struct {
 	Base
-	CaseID      uint      `gorm:"not null" json:"case_id"`
-	Case        *Case     `json:"case,omitempty"`
-	OfficerID   uint      `gorm:"not null" json:"officer_id"`
-	Officer     *User     `json:"officer,omitempty"`
-	Role        string    `gorm:"type:varchar(50)" json:"role"`
-	Notes       string    `gorm:"type:text" json:"notes"`
-	CreatedByID *uint     `json:"created_by_id,omitempty"`
-	CreatedBy   *User     `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-}
\ No newline at end of file
+	CaseID      uint   `gorm:"not null;uniqueIndex:idx_case_officers_active,where:deleted_at IS NULL;uniqueIndex:idx_case_officers_lead,where:role = 'Lead Investigator' AND deleted_at IS NULL" json:"case_id"`
+	Case        *Case  `json:"case,omitempty"`
+	OfficerID   uint   `gorm:"not null;uniqueIndex:idx_case_officers_active,where:deleted_at IS NULL" json:"officer_id"`
+	Officer     *User  `json:"officer,omitempty"`
+	Role        string `gorm:"type:varchar(50)" json:"role"`
+	Notes       string `gorm:"type:text" json:"notes"`
+	CreatedByID *uint  `json:"created_by_id,omitempty"`
+	CreatedBy   *User  `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
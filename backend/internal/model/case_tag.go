@@ -1,12 +1,13 @@
-
 package models
 
+// CaseTag attaches a tag to a case. Detaching soft-deletes the row; a tag
+// is attached to a case at most once at a time.
 type CaseTag struct {
 	Base
-	CaseID      uint    `gorm:"not null" json:"case_id"`
-	Case        *Case   `json:"case,omitempty"`
-	TagID       uint    `gorm:"not null" json:"tag_id"`
-	Tag         *Tag    `json:"tag,omitempty"`
-	CreatedByID *uint   `json:"created_by_id,omitempty"`
-	CreatedBy   *User   `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-}
\ No newline at end of file
+	CaseID      uint  `gorm:"not null;uniqueIndex:idx_case_tags_active,where:deleted_at IS NULL" json:"case_id"`
+	Case        *Case `json:"case,omitempty"`
+	TagID       uint  `gorm:"not null;uniqueIndex:idx_case_tags_active,where:deleted_at IS NULL" json:"tag_id"`
+	Tag         *Tag  `json:"tag,omitempty"`
+	CreatedByID *uint `json:"created_by_id,omitempty"`
+	CreatedBy   *User `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
+}
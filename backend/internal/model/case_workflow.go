@@ -0,0 +1,30 @@
+package models
+
+// CaseStatus is one state of the case lifecycle. Case.Status holds its
+// Name, so renaming a status renames it on every case. Names are unique
+// regardless of case.
+type CaseStatus struct {
+	Base
+	Name        string `gorm:"type:varchar(50);not null;uniqueIndex:idx_case_statuses_name,expression:LOWER(name),where:deleted_at IS NULL" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	// IsInitial marks the status new cases start in. At most one status
+	// has it.
+	IsInitial bool `gorm:"not null;default:false;uniqueIndex:idx_case_statuses_initial,where:is_initial AND deleted_at IS NULL" json:"is_initial"`
+	// IsClosed marks closing statuses: entering one sets Case.ClosedAt and
+	// ClosedByID, leaving it clears them.
+	IsClosed  bool `gorm:"not null;default:false" json:"is_closed"`
+	SortOrder int  `gorm:"not null;default:0" json:"sort_order"`
+}
+
+// CaseTransition allows moving a case from one status to another.
+type CaseTransition struct {
+	Base
+	Name         string      `gorm:"type:varchar(50);not null" json:"name"`
+	FromStatusID uint        `gorm:"not null;index" json:"from_status_id"`
+	FromStatus   *CaseStatus `gorm:"foreignKey:FromStatusID" json:"from_status,omitempty"`
+	ToStatusID   uint        `gorm:"not null" json:"to_status_id"`
+	ToStatus     *CaseStatus `gorm:"foreignKey:ToStatusID" json:"to_status,omitempty"`
+	// RequiredPermission is needed on top of case.edit, if set.
+	RequiredPermission string `gorm:"type:varchar(100)" json:"required_permission"`
+	RequiresReason     bool   `gorm:"not null;default:false" json:"requires_reason"`
+}
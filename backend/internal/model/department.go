@@ -5,5 +5,12 @@ type Department struct {
 	Base
 	Name        string  `gorm:"type:varchar(100);not null" json:"name"`
 	Description string  `gorm:"type:text" json:"description"`
+	EmailDomain string  `gorm:"type:varchar(100);index" json:"email_domain,omitempty"`
+	// GoogleAutoProvision lets unknown users with a verified EmailDomain
+	// address create an account by signing in with Google.
+	GoogleAutoProvision bool `gorm:"not null;default:false" json:"google_auto_provision"`
+	// HeadID is the member who leads the department, if any.
+	HeadID      *uint   `json:"head_id,omitempty"`
+	Head        *User   `gorm:"foreignKey:HeadID" json:"head,omitempty"`
 	Users       []*User `gorm:"foreignKey:DepartmentID" json:"users,omitempty"`
 }
\ No newline at end of file
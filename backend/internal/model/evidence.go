@@ -1,10 +1,15 @@
-
 package models
 
 import (
 	"gorm.io/datatypes"
 )
 
+// EvidenceSearchKeys are the Metadata keys whose values are full-text
+// searchable, in the order search_vector joins them. A search for black bag
+// finds evidence with {"color": "black", "item": "bag"}. The list is part
+// of the column definition and changes only through a migration.
+var EvidenceSearchKeys = []string{"item", "description", "notes", "label", "contents", "brand", "model", "color", "serial_number", "location"}
+
 type Evidence struct {
 	Base
 	CaseID         uint           `gorm:"not null" json:"case_id"`
@@ -19,4 +24,5 @@ type Evidence struct {
 	IsConfidential bool           `gorm:"default:false" json:"is_confidential"`
 	CreatedByID    *uint          `json:"created_by_id,omitempty"`
 	CreatedBy      *User          `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
-}
\ No newline at end of file
+	SearchVector   string         `gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B') || setweight(to_tsvector('english', coalesce(metadata->>'item', '') || ' ' || coalesce(metadata->>'description', '') || ' ' || coalesce(metadata->>'notes', '') || ' ' || coalesce(metadata->>'label', '') || ' ' || coalesce(metadata->>'contents', '') || ' ' || coalesce(metadata->>'brand', '') || ' ' || coalesce(metadata->>'model', '') || ' ' || coalesce(metadata->>'color', '') || ' ' || coalesce(metadata->>'serial_number', '') || ' ' || coalesce(metadata->>'location', '')), 'C')) STORED;->:false;<-:false;index:idx_evidences_search_vector,type:gin" json:"-"` // maintained by PostgreSQL for full-text search
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	LoginThrottleAccount = "account"
+	LoginThrottleIP      = "ip"
+)
+
+// LoginThrottle counts recent failed sign-ins for one account or one client
+// IP. Accounts are keyed by normalised email so that unknown addresses are
+// throttled exactly like existing ones.
+type LoginThrottle struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Scope          string     `gorm:"type:varchar(10);not null;uniqueIndex:idx_login_throttle_scope_key" json:"scope"` // one of the LoginThrottle* constants
+	Key            string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_login_throttle_scope_key" json:"key"`
+	FailedAttempts int        `gorm:"not null;default:0" json:"failed_attempts"`
+	LastFailedAt   *time.Time `json:"last_failed_at,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+)
+
+type MFARecoveryCode struct {
+	Base
+	UserID   uint       `gorm:"not null;index" json:"user_id"`
+	User     *User      `json:"user,omitempty"`
+	CodeHash string     `gorm:"type:varchar(64);not null" json:"-"` // SHA-256 of the normalised code
+	UsedAt   *time.Time `json:"used_at,omitempty"`
+}
+
+// MFAChallenge is issued after the first factor succeeds and must be
+// redeemed with a TOTP or recovery code before tokens are issued.
+type MFAChallenge struct {
+	Base
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	User      *User      `json:"user,omitempty"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// OIDCLoginState holds the per-attempt secrets of an in-flight OIDC login
+// between the redirect to the provider and the callback.
+type OIDCLoginState struct {
+	Base
+	StateHash    string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"` // SHA-256 of the state parameter
+	Nonce        string     `gorm:"type:varchar(64);not null" json:"-"`
+	CodeVerifier string     `gorm:"type:varchar(128);not null" json:"-"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+}
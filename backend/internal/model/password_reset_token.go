@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+type PasswordResetToken struct {
+	Base
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	User      *User      `json:"user,omitempty"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"` // SHA-256 of the emailed token
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+}
@@ -1,4 +1,3 @@
-
 package models
 
 import (
@@ -7,11 +6,13 @@ import (
 
 type RefreshToken struct {
 	Base
-	UserID     uint         `gorm:"not null" json:"user_id"`
-	User       *User        `json:"user,omitempty"`
-	Token      string       `gorm:"type:varchar(255);not null;uniqueIndex" json:"token"`
-	ExpiryDate time.Time    `gorm:"not null" json:"expiry_date"`
-	IsRevoked  bool         `gorm:"default:false" json:"is_revoked"`
-	RevokedAt  *time.Time   `json:"revoked_at,omitempty"`
-	Sessions   []*UserSession `gorm:"foreignKey:RefreshTokenID" json:"sessions,omitempty"`
-}
\ No newline at end of file
+	UserID       uint           `gorm:"not null" json:"user_id"`
+	User         *User          `json:"user,omitempty"`
+	Token        string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"-"` // SHA-256 of the opaque token
+	FamilyID     string         `gorm:"type:varchar(64);not null;index" json:"family_id"`
+	ReplacedByID *uint          `json:"replaced_by_id,omitempty"`
+	ExpiryDate   time.Time      `gorm:"not null" json:"expiry_date"`
+	IsRevoked    bool           `gorm:"default:false" json:"is_revoked"`
+	RevokedAt    *time.Time     `json:"revoked_at,omitempty"`
+	Sessions     []*UserSession `gorm:"foreignKey:RefreshTokenID" json:"sessions,omitempty"`
+}
@@ -1,12 +1,5 @@
 package models
 
-import (
-	"encoding/json"
-
-	"gorm.io/datatypes"
-	"gorm.io/gorm"
-)
-
 type Role struct {
 	Base
 	Name            string                      `gorm:"type:varchar(50);not null" json:"name"`
@@ -16,6 +9,7 @@ type Role struct {
 	ChildRoles      []*Role                     `gorm:"foreignKey:ParentRoleID" json:"child_roles,omitempty"`
 	Level           int                         `gorm:"not null;default:1" json:"level"` // Higher = more senior
 	IsSystemRole    bool                        `gorm:"not null;default:false" json:"is_system_role"`
+	MFARequired     bool                        `gorm:"not null;default:false" json:"mfa_required"` // holders must sign in with a second factor
 	CreatedByID     *uint                       `json:"created_by_id,omitempty"`
 	CreatedBy       *User                       `gorm:"foreignKey:CreatedByID" json:"created_by,omitempty"`
 	UpdatedByID     *uint                       `json:"updated_by_id,omitempty"`
@@ -26,27 +20,3 @@ type Role struct {
 	ManagedByRoles  []*RoleManagementPermission `gorm:"foreignKey:ManageableRoleID" json:"managed_by_roles,omitempty"`
 	ChangeHistory   []*RoleChangeHistory        `gorm:"foreignKey:RoleID" json:"change_history,omitempty"`
 }
-
-func (r *Role) AfterUpdate(tx *gorm.DB) error {
-	// Create a change history record when a role is updated
-	var oldRole Role
-	if err := tx.Model(&Role{}).Where("id = ?", r.ID).First(&oldRole).Error; err != nil {
-		return err
-	}
-
-	oldData, _ := json.Marshal(oldRole)
-	newData, _ := json.Marshal(r)
-
-	oldDataJSON := datatypes.JSON(oldData)
-	newDataJSON := datatypes.JSON(newData)
-
-	history := RoleChangeHistory{
-		RoleID:       r.ID,
-		ChangedByID:  *r.UpdatedByID,
-		Action:       "updated",
-		PreviousData: oldDataJSON,
-		NewData:      newDataJSON,
-	}
-
-	return tx.Create(&history).Error
-}
@@ -6,14 +6,23 @@ import (
 
 	"gorm.io/datatypes"
 )
+
+const (
+	RoleChangeCreated           = "created"
+	RoleChangeUpdated           = "updated"
+	RoleChangeDeleted           = "deleted"
+	RoleChangePermissionAdded   = "permission_added"
+	RoleChangePermissionRemoved = "permission_removed"
+)
+
 type RoleChangeHistory struct {
 	ID          uint          `gorm:"primaryKey" json:"id"`
 	RoleID      uint          `gorm:"not null" json:"role_id"`
 	Role        *Role         `json:"role,omitempty"`
 	ChangedByID uint          `gorm:"not null" json:"changed_by_id"`
 	ChangedBy   *User         `json:"changed_by,omitempty"`
-	Action      string        `gorm:"type:varchar(50);not null" json:"action"` // created, updated, permission_added, etc.
+	Action      string        `gorm:"type:varchar(50);not null" json:"action"` // one of the RoleChange* constants
 	PreviousData datatypes.JSON `gorm:"type:jsonb" json:"previous_data"`
 	NewData     datatypes.JSON `gorm:"type:jsonb" json:"new_data"`
 	CreatedAt   time.Time     `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
-}
\ No newline at end of file
+}
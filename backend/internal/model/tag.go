@@ -1,10 +1,8 @@
-
-
 package models
 
 type Tag struct {
 	Base
-	Name     string    `gorm:"type:varchar(50);not null;uniqueIndex" json:"name"`
-	Color    string    `gorm:"type:varchar(7)" json:"color"`
+	Name     string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_tags_name,where:deleted_at IS NULL" json:"name"`
+	Color    string     `gorm:"type:varchar(7)" json:"color"` // #RRGGBB
 	CaseTags []*CaseTag `gorm:"foreignKey:TagID" json:"case_tags,omitempty"`
 }
@@ -9,21 +9,28 @@ type User struct {
 	Base
 	FirstName       string          `gorm:"type:varchar(50);not null" json:"first_name"`
 	LastName        string          `gorm:"type:varchar(50);not null" json:"last_name"`
-	Email           string          `gorm:"type:varchar(100);not null;uniqueIndex" json:"email"`
+	Email           string          `gorm:"type:varchar(100);not null;uniqueIndex;index:idx_users_email_lower,expression:LOWER(email)" json:"email"`
 	PasswordHash    string          `gorm:"type:varchar(255)" json:"-"`
-	GoogleID        string          `gorm:"type:varchar(100)" json:"google_id,omitempty"`
+	GoogleID        string          `gorm:"type:varchar(100);index" json:"google_id,omitempty"`
 	PhoneNumber     string          `gorm:"type:varchar(20)" json:"phone_number,omitempty"`
-	BadgeNumber     string          `gorm:"type:varchar(20);uniqueIndex" json:"badge_number"`
+	BadgeNumber     string          `gorm:"type:varchar(20);uniqueIndex:idx_users_badge_number,where:badge_number <> ''" json:"badge_number"` // empty means no badge
 	DepartmentID    *uint           `json:"department_id,omitempty"`
 	Department      *Department     `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
 	ProfileImageURL string          `gorm:"type:text" json:"profile_image_url,omitempty"`
 	IsActive        bool            `gorm:"default:true" json:"is_active"`
 	LastLogin       *time.Time      `json:"last_login,omitempty"`
+	MFAEnabled      bool            `gorm:"not null;default:false" json:"mfa_enabled"`
+	MFAEnabledAt    *time.Time      `json:"mfa_enabled_at,omitempty"`
+	MFASecret       string          `gorm:"type:varchar(255)" json:"-"` // encrypted TOTP seed, set once enrollment starts
+	MFALastUsedStep int64           `gorm:"not null;default:0" json:"-"` // last accepted TOTP time step, to reject replays
 	UserRoles       []*UserRole     `gorm:"foreignKey:UserID" json:"user_roles,omitempty"`
 	CreatedCases    []*Case         `gorm:"foreignKey:CreatedByID" json:"created_cases,omitempty"`
 	ClosedCases     []*Case         `gorm:"foreignKey:ClosedByID" json:"closed_cases,omitempty"`
 	CaseAssignments []*CaseOfficer  `gorm:"foreignKey:OfficerID" json:"case_assignments,omitempty"`
 	RefreshTokens   []*RefreshToken `gorm:"foreignKey:UserID" json:"refresh_tokens,omitempty"`
+	PasswordResetTokens []*PasswordResetToken `gorm:"foreignKey:UserID" json:"-"`
+	MFARecoveryCodes    []*MFARecoveryCode    `gorm:"foreignKey:UserID" json:"-"`
+	MFAChallenges       []*MFAChallenge       `gorm:"foreignKey:UserID" json:"-"`
 	UserSessions    []*UserSession  `gorm:"foreignKey:UserID" json:"user_sessions,omitempty"`
 	AuditLogs       []*AuditLog     `gorm:"foreignKey:UserID" json:"audit_logs,omitempty"`
 	RoleAssignments []*UserRole     `gorm:"foreignKey:AssignedByID" json:"role_assignments,omitempty"`
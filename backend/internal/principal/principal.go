@@ -0,0 +1,50 @@
+package principal
+
+import (
+	"backend/internal/model"
+	"context"
+)
+
+type contextKey struct{}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	User        *models.User
+	SessionID   uint
+	Permissions map[string]struct{}
+}
+
+func (p *Principal) UserID() uint {
+	return p.User.ID
+}
+
+// UserIDRef returns the caller's ID in the form used by CreatedByID and
+// UpdatedByID columns.
+func (p *Principal) UserIDRef() *uint {
+	id := p.User.ID
+	return &id
+}
+
+func (p *Principal) HasPermission(code string) bool {
+	_, ok := p.Permissions[code]
+	return ok
+}
+
+// PermissionCodes returns the caller's permission codes in no particular order.
+func (p *Principal) PermissionCodes() []string {
+	codes := make([]string, 0, len(p.Permissions))
+	for code := range p.Permissions {
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal stored by the authentication middleware.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	return getDB(ctx, r.db).Omit("User").Create(entry).Error
+}
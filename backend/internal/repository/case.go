@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"backend/internal/principal"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PermissionViewAllCases lifts the department restriction on case queries.
+const PermissionViewAllCases = "case.view_all"
+
+type CaseRepository interface {
+	Create(ctx context.Context, c *models.Case) error
+	Update(ctx context.Context, c *models.Case) error
+	UpdateStatus(ctx context.Context, c *models.Case) error
+	Delete(ctx context.Context, id uint) error
+	FindByID(ctx context.Context, id uint) (*models.Case, error)
+	FindByIDForUpdate(ctx context.Context, id uint) (*models.Case, error)
+	FindByIDWithDetails(ctx context.Context, id uint) (*models.Case, error)
+	ExistsByCaseNumber(ctx context.Context, caseNumber string) (bool, error)
+	CountByTag(ctx context.Context) (map[uint]int64, error)
+	List(ctx context.Context, query CaseListQuery) ([]models.Case, error)
+	Count(ctx context.Context, query CaseListQuery) (int64, error)
+	Search(ctx context.Context, query CaseSearchQuery) ([]CaseSearchHit, error)
+	CountSearch(ctx context.Context, query CaseSearchQuery) (int64, error)
+	SearchEvidence(ctx context.Context, query CaseSearchQuery, caseIDs []uint) ([]EvidenceSearchHit, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Case, error)
+	Summarize(ctx context.Context) (*CaseStatsSummary, error)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+	CountByPriority(ctx context.Context) (map[string]int64, error)
+	CountByDepartment(ctx context.Context) ([]DepartmentCaseCount, error)
+	CountByOfficer(ctx context.Context) ([]OfficerCaseCount, error)
+	CountOpenedByPeriod(ctx context.Context, unit string, from, to time.Time) ([]CasePeriodCount, error)
+	CountClosedByPeriod(ctx context.Context, unit string, from, to time.Time) ([]CasePeriodCount, error)
+	CountOpenAt(ctx context.Context, at time.Time) (int64, error)
+}
+
+// CaseListQuery filters, orders and bounds a case search. Sort columns must
+// be cases columns chosen by the caller, never raw client input.
+type CaseListQuery struct {
+	Search         string
+	Statuses       []string
+	Priorities     []string
+	TagIDs         []uint
+	MatchAllTags   bool // cases must carry every tag in TagIDs, not just one
+	OfficerID      *uint
+	DepartmentID   *uint
+	IncidentFrom   *time.Time
+	IncidentBefore *time.Time
+	Sort           []CaseSort
+	Offset         int
+	Limit          int
+}
+
+type CaseSort struct {
+	Column string
+	Desc   bool
+}
+
+type caseRepository struct {
+	db *gorm.DB
+}
+
+func NewCaseRepository(db *gorm.DB) CaseRepository {
+	return &caseRepository{db: db}
+}
+
+func (r *caseRepository) Create(ctx context.Context, c *models.Case) error {
+	return getDB(ctx, r.db).Omit("CreatedBy", "ClosedBy", "Officers", "Tags", "Evidences").Create(c).Error
+}
+
+// Update saves the editable columns. Callers load the case through FindByID
+// first, which applies the visibility rule.
+func (r *caseRepository) Update(ctx context.Context, c *models.Case) error {
+	return getDB(ctx, r.db).Model(c).
+		Select("title", "description", "location", "incident_date", "priority").
+		Updates(c).Error
+}
+
+// UpdateStatus saves the status and closure columns. Status changes go
+// through the case workflow, never through Update, and load the case
+// through FindByIDForUpdate.
+func (r *caseRepository) UpdateStatus(ctx context.Context, c *models.Case) error {
+	return getDB(ctx, r.db).Model(c).
+		Select("status", "closed_at", "closed_by_id", "closure_reason").
+		Updates(c).Error
+}
+
+func (r *caseRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.Case{}, id).Error
+}
+
+// FindByID returns the case if it exists and the caller may see it.
+func (r *caseRepository) FindByID(ctx context.Context, id uint) (*models.Case, error) {
+	var c models.Case
+	if err := r.visible(ctx).First(&c, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindByIDForUpdate is FindByID that also locks the case row, so that
+// concurrent status changes are checked against the status they replace.
+// Callers must run inside a transaction.
+func (r *caseRepository) FindByIDForUpdate(ctx context.Context, id uint) (*models.Case, error) {
+	var c models.Case
+	err := r.visible(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&c, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// FindByIDWithDetails is FindByID with the creator, closer, assigned
+// officers, tags and evidence loaded. Deleted users are still loaded, as in
+// List.
+func (r *caseRepository) FindByIDWithDetails(ctx context.Context, id uint) (*models.Case, error) {
+	var c models.Case
+	err := r.visible(ctx).
+		Preload("CreatedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ClosedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Officers", func(db *gorm.DB) *gorm.DB { return db.Order("case_officers.id") }).
+		Preload("Officers.Officer", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("case_tags.id") }).
+		Preload("Tags.Tag").
+		Preload("Evidences", func(db *gorm.DB) *gorm.DB { return db.Order("evidences.id") }).
+		First(&c, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ExistsByCaseNumber reports whether any case, deleted or not and visible
+// or not, uses caseNumber. Case numbers stay reserved after deletion.
+func (r *caseRepository) ExistsByCaseNumber(ctx context.Context, caseNumber string) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Unscoped().Model(&models.Case{}).
+		Where("case_number = ?", caseNumber).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountByTag counts the visible cases each tag is attached to, keyed by
+// tag ID. Tags on no visible case are left out.
+func (r *caseRepository) CountByTag(ctx context.Context) (map[uint]int64, error) {
+	var rows []struct {
+		TagID uint
+		Count int64
+	}
+	err := r.visible(ctx).
+		Joins("JOIN case_tags ON case_tags.case_id = cases.id AND case_tags.deleted_at IS NULL").
+		Select("case_tags.tag_id, COUNT(DISTINCT cases.id) AS count").
+		Group("case_tags.tag_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.Count
+	}
+	return counts, nil
+}
+
+// List returns one page of the visible cases matching query, with the
+// creator and closer loaded. Rows that tie on every sort column are ordered
+// by id.
+func (r *caseRepository) List(ctx context.Context, query CaseListQuery) ([]models.Case, error) {
+	db := r.filter(ctx, r.visible(ctx), query).
+		Preload("CreatedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ClosedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order(caseOrder(query.Sort))
+
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+
+	var list []models.Case
+	if err := db.Limit(query.Limit).Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Count returns how many visible cases match the filters of query.
+func (r *caseRepository) Count(ctx context.Context, query CaseListQuery) (int64, error) {
+	var count int64
+	err := r.filter(ctx, r.visible(ctx), query).Count(&count).Error
+	return count, err
+}
+
+func (r *caseRepository) filter(ctx context.Context, db *gorm.DB, query CaseListQuery) *gorm.DB {
+	if len(query.Statuses) > 0 {
+		db = db.Where("cases.status IN ?", query.Statuses)
+	}
+	if len(query.Priorities) > 0 {
+		db = db.Where("cases.priority IN ?", query.Priorities)
+	}
+	if len(query.TagIDs) > 0 {
+		tagged := getDB(ctx, r.db).Model(&models.CaseTag{}).
+			Select("case_id").
+			Where("tag_id IN ?", query.TagIDs)
+		if query.MatchAllTags {
+			tagged = tagged.Group("case_id").Having("COUNT(DISTINCT tag_id) = ?", len(query.TagIDs))
+		}
+		db = db.Where("cases.id IN (?)", tagged)
+	}
+	if query.OfficerID != nil {
+		db = db.Where("cases.id IN (?)", getDB(ctx, r.db).Model(&models.CaseOfficer{}).
+			Select("case_id").
+			Where("officer_id = ?", *query.OfficerID))
+	}
+	if query.DepartmentID != nil {
+		members := getDB(ctx, r.db).Unscoped().Model(&models.User{}).
+			Select("id").
+			Where("department_id = ?", *query.DepartmentID)
+		db = r.involving(ctx, db, members)
+	}
+	if query.IncidentFrom != nil {
+		db = db.Where("cases.incident_date >= ?", *query.IncidentFrom)
+	}
+	if query.IncidentBefore != nil {
+		db = db.Where("cases.incident_date < ?", *query.IncidentBefore)
+	}
+
+	// Every search term must match the case number, title, description or
+	// location.
+	for _, term := range strings.Fields(query.Search) {
+		pattern := "%" + escapeLike(strings.ToLower(term)) + "%"
+		db = db.Where(
+			`LOWER(cases.case_number) LIKE ? ESCAPE '\' OR LOWER(cases.title) LIKE ? ESCAPE '\' OR `+
+				`LOWER(cases.description) LIKE ? ESCAPE '\' OR LOWER(cases.location) LIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern, pattern,
+		)
+	}
+	return db
+}
+
+// caseOrder builds the ORDER BY clause for sorts, ending with id in the direction
+// of the last sort. Priorities order by severity rather than by name, so
+// "-priority" puts critical cases first.
+//
+// The order is a single expression because gorm drops an expression order
+// when another order is added after it.
+func caseOrder(sorts []CaseSort) clause.OrderBy {
+	var (
+		sql  strings.Builder
+		vars []interface{}
+		desc bool
+	)
+	for _, sort := range sorts {
+		if sort.Column == "priority" {
+			sql.WriteString("CASE cases.priority")
+			for i, priority := range models.CasePriorities {
+				sql.WriteString(" WHEN ? THEN " + strconv.Itoa(i))
+				vars = append(vars, priority)
+			}
+			sql.WriteString(" END")
+		} else {
+			sql.WriteString("?")
+			vars = append(vars, clause.Column{Table: "cases", Name: sort.Column})
+		}
+		if sort.Desc {
+			sql.WriteString(" DESC")
+		}
+		sql.WriteString(", ")
+		desc = sort.Desc
+	}
+
+	sql.WriteString("cases.id")
+	if desc {
+		sql.WriteString(" DESC")
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql.String(), Vars: vars, WithoutParentheses: true}}
+}
+
+// unrestrictedCasesKey marks a context whose case queries skip the
+// visibility rule.
+const unrestrictedCasesKey contextKey = "unrestricted_cases"
+
+// WithUnrestrictedCases lets internal jobs, which run without a principal,
+// see every case. Request handlers must never use it.
+func WithUnrestrictedCases(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unrestrictedCasesKey, true)
+}
+
+func unrestrictedCases(ctx context.Context) bool {
+	unrestricted, _ := ctx.Value(unrestrictedCasesKey).(bool)
+	return unrestricted
+}
+
+// visible starts a case query restricted to what the caller in ctx may see.
+// Every read in this repository goes through it, so handlers and services
+// cannot forget the rule.
+//
+// Callers holding PermissionViewAllCases see every case. Everyone else sees
+// the cases created by or assigned to members of their own department, or
+// only their own cases while they belong to no department. Contexts without
+// a principal see nothing unless they opted in with WithUnrestrictedCases.
+func (r *caseRepository) visible(ctx context.Context) *gorm.DB {
+	db := getDB(ctx, r.db).Model(&models.Case{})
+	if unrestrictedCases(ctx) {
+		return db
+	}
+
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return db.Where("1 = 0")
+	}
+	if p.HasPermission(PermissionViewAllCases) {
+		return db
+	}
+
+	// Soft-deleted members still count, so their cases stay visible to the
+	// department.
+	var members interface{} = []uint{p.UserID()}
+	if p.User.DepartmentID != nil {
+		members = getDB(ctx, r.db).Unscoped().Model(&models.User{}).
+			Select("id").
+			Where("department_id = ?", *p.User.DepartmentID)
+	}
+
+	return r.involving(ctx, db, members)
+}
+
+// CaseVisibilityScope names the set of cases the caller in ctx may see.
+// Callers with the same scope see the same cases, so results computed for
+// one of them may be shared with the others. It follows the rule in
+// visible and must change with it.
+func CaseVisibilityScope(ctx context.Context) string {
+	if unrestrictedCases(ctx) {
+		return "all"
+	}
+
+	p, ok := principal.FromContext(ctx)
+	switch {
+	case !ok:
+		return "none"
+	case p.HasPermission(PermissionViewAllCases):
+		return "all"
+	case p.User.DepartmentID != nil:
+		return "department:" + strconv.FormatUint(uint64(*p.User.DepartmentID), 10)
+	default:
+		return "user:" + strconv.FormatUint(uint64(p.UserID()), 10)
+	}
+}
+
+// involving restricts db to the cases created by or assigned to members,
+// which is a list of user IDs or a subquery selecting them.
+func (r *caseRepository) involving(ctx context.Context, db *gorm.DB, members interface{}) *gorm.DB {
+	assigned := getDB(ctx, r.db).Model(&models.CaseOfficer{}).
+		Select("case_id").
+		Where("officer_id IN (?)", members)
+
+	return db.Where("cases.created_by_id IN (?) OR cases.id IN (?)", members, assigned)
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CaseNumberFormatRepository interface {
+	Create(ctx context.Context, format *models.CaseNumberFormat) error
+	Update(ctx context.Context, format *models.CaseNumberFormat) error
+	Delete(ctx context.Context, id uint) error
+	FindAll(ctx context.Context) ([]models.CaseNumberFormat, error)
+	FindByID(ctx context.Context, id uint) (*models.CaseNumberFormat, error)
+	FindByCaseType(ctx context.Context, caseType string) (*models.CaseNumberFormat, error)
+	FindByDepartmentID(ctx context.Context, departmentID uint) (*models.CaseNumberFormat, error)
+	FindDefault(ctx context.Context) (*models.CaseNumberFormat, error)
+	ExistsByCaseType(ctx context.Context, caseType string, excludeID uint) (bool, error)
+	ExistsByPrefix(ctx context.Context, prefix string, excludeID uint) (bool, error)
+	ExistsByDepartmentID(ctx context.Context, departmentID uint, excludeID uint) (bool, error)
+	ClearDefault(ctx context.Context, excludeID uint) error
+	NextSequence(ctx context.Context, formatID uint, year int) (int, error)
+}
+
+type caseNumberFormatRepository struct {
+	db *gorm.DB
+}
+
+func NewCaseNumberFormatRepository(db *gorm.DB) CaseNumberFormatRepository {
+	return &caseNumberFormatRepository{db: db}
+}
+
+func (r *caseNumberFormatRepository) Create(ctx context.Context, format *models.CaseNumberFormat) error {
+	// include_year defaults to true: an insert leaves out false and reads
+	// the default back into format.
+	includeYear := format.IncludeYear
+
+	db := getDB(ctx, r.db)
+	if err := db.Omit("Department", "CreatedBy").Create(format).Error; err != nil {
+		return err
+	}
+	if !includeYear {
+		format.IncludeYear = false
+		return db.Model(format).Update("include_year", false).Error
+	}
+	return nil
+}
+
+func (r *caseNumberFormatRepository) Update(ctx context.Context, format *models.CaseNumberFormat) error {
+	return getDB(ctx, r.db).Model(format).
+		Select("case_type", "prefix", "padding", "include_year", "department_id", "is_default").
+		Updates(format).Error
+}
+
+func (r *caseNumberFormatRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CaseNumberFormat{}, id).Error
+}
+
+func (r *caseNumberFormatRepository) FindAll(ctx context.Context) ([]models.CaseNumberFormat, error) {
+	var formats []models.CaseNumberFormat
+	if err := getDB(ctx, r.db).Preload("Department").Order("case_type, id").Find(&formats).Error; err != nil {
+		return nil, err
+	}
+	return formats, nil
+}
+
+func (r *caseNumberFormatRepository) FindByID(ctx context.Context, id uint) (*models.CaseNumberFormat, error) {
+	return r.first(getDB(ctx, r.db).Preload("Department").Where("id = ?", id))
+}
+
+// FindByCaseType matches the case type ignoring case.
+func (r *caseNumberFormatRepository) FindByCaseType(ctx context.Context, caseType string) (*models.CaseNumberFormat, error) {
+	return r.first(getDB(ctx, r.db).Where("LOWER(case_type) = LOWER(?)", caseType))
+}
+
+func (r *caseNumberFormatRepository) FindByDepartmentID(ctx context.Context, departmentID uint) (*models.CaseNumberFormat, error) {
+	return r.first(getDB(ctx, r.db).Where("department_id = ?", departmentID))
+}
+
+func (r *caseNumberFormatRepository) FindDefault(ctx context.Context) (*models.CaseNumberFormat, error) {
+	return r.first(getDB(ctx, r.db).Where("is_default = ?", true))
+}
+
+func (r *caseNumberFormatRepository) first(db *gorm.DB) (*models.CaseNumberFormat, error) {
+	var format models.CaseNumberFormat
+	if err := db.Order("id").First(&format).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &format, nil
+}
+
+// ExistsByCaseType reports whether another live format than excludeID is
+// for caseType, ignoring case.
+func (r *caseNumberFormatRepository) ExistsByCaseType(ctx context.Context, caseType string, excludeID uint) (bool, error) {
+	return r.exists(ctx, "LOWER(case_type) = LOWER(?)", caseType, excludeID)
+}
+
+func (r *caseNumberFormatRepository) ExistsByPrefix(ctx context.Context, prefix string, excludeID uint) (bool, error) {
+	return r.exists(ctx, "UPPER(prefix) = UPPER(?)", prefix, excludeID)
+}
+
+func (r *caseNumberFormatRepository) ExistsByDepartmentID(ctx context.Context, departmentID uint, excludeID uint) (bool, error) {
+	return r.exists(ctx, "department_id = ?", departmentID, excludeID)
+}
+
+func (r *caseNumberFormatRepository) exists(ctx context.Context, cond string, value interface{}, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.CaseNumberFormat{}).
+		Where(cond, value).
+		Where("id <> ?", excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ClearDefault unsets IsDefault on every format except excludeID.
+func (r *caseNumberFormatRepository) ClearDefault(ctx context.Context, excludeID uint) error {
+	return getDB(ctx, r.db).Model(&models.CaseNumberFormat{}).
+		Where("is_default = ? AND id <> ?", true, excludeID).
+		Update("is_default", false).Error
+}
+
+// NextSequence issues the next number of the format's sequence for year.
+// The sequence row stays locked until the surrounding transaction ends, so
+// concurrent callers queue behind it instead of issuing the same number,
+// and a rolled-back case creation gives its number back.
+func (r *caseNumberFormatRepository) NextSequence(ctx context.Context, formatID uint, year int) (int, error) {
+	db := getDB(ctx, r.db)
+
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.CaseNumberSequence{FormatID: formatID, Year: year}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var seq models.CaseNumberSequence
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("format_id = ? AND year = ?", formatID, year).
+		First(&seq).Error
+	if err != nil {
+		return 0, err
+	}
+
+	seq.LastValue++
+	if err := db.Model(&seq).Update("last_value", seq.LastValue).Error; err != nil {
+		return 0, err
+	}
+	return seq.LastValue, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type CaseOfficerRepository interface {
+	Create(ctx context.Context, assignment *models.CaseOfficer) error
+	Update(ctx context.Context, assignment *models.CaseOfficer) error
+	Delete(ctx context.Context, id uint) error
+	FindActive(ctx context.Context, caseID, officerID uint) (*models.CaseOfficer, error)
+	FindLead(ctx context.Context, caseID uint) (*models.CaseOfficer, error)
+	FindByCaseID(ctx context.Context, caseID uint, includeHistory bool) ([]models.CaseOfficer, error)
+}
+
+type caseOfficerRepository struct {
+	db *gorm.DB
+}
+
+func NewCaseOfficerRepository(db *gorm.DB) CaseOfficerRepository {
+	return &caseOfficerRepository{db: db}
+}
+
+func (r *caseOfficerRepository) Create(ctx context.Context, assignment *models.CaseOfficer) error {
+	return getDB(ctx, r.db).Omit("Case", "Officer", "CreatedBy").Create(assignment).Error
+}
+
+func (r *caseOfficerRepository) Update(ctx context.Context, assignment *models.CaseOfficer) error {
+	return getDB(ctx, r.db).Model(assignment).
+		Select("role", "notes").
+		Updates(assignment).Error
+}
+
+// Delete unassigns the officer. The soft-deleted row stays as history.
+func (r *caseOfficerRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CaseOfficer{}, id).Error
+}
+
+// FindActive returns the officer's current assignment to the case, with
+// the officer and the assigning user loaded.
+func (r *caseOfficerRepository) FindActive(ctx context.Context, caseID, officerID uint) (*models.CaseOfficer, error) {
+	return r.first(getDB(ctx, r.db).Where("case_id = ? AND officer_id = ?", caseID, officerID))
+}
+
+// FindLead returns the case's current lead investigator assignment.
+func (r *caseOfficerRepository) FindLead(ctx context.Context, caseID uint) (*models.CaseOfficer, error) {
+	return r.first(getDB(ctx, r.db).Where("case_id = ? AND role = ?", caseID, models.CaseOfficerRoleLead))
+}
+
+func (r *caseOfficerRepository) first(db *gorm.DB) (*models.CaseOfficer, error) {
+	var assignment models.CaseOfficer
+	err := db.
+		Preload("Officer").
+		Preload("CreatedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("id").
+		First(&assignment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// FindByCaseID lists the case's assignments in the order they were made,
+// with the officer and the assigning user loaded. With includeHistory,
+// ended assignments are listed as well.
+func (r *caseOfficerRepository) FindByCaseID(ctx context.Context, caseID uint, includeHistory bool) ([]models.CaseOfficer, error) {
+	db := getDB(ctx, r.db)
+	if includeHistory {
+		db = db.Unscoped()
+	}
+
+	var assignments []models.CaseOfficer
+	err := db.
+		Preload("Officer", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("CreatedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("case_id = ?", caseID).
+		Order("id").
+		Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
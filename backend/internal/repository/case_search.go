@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchConfig is the text search configuration of the search_vector
+// columns. Queries must use the same one to match them.
+const searchConfig = "english"
+
+// maxEvidenceHitsPerCase bounds the evidence SearchEvidence returns for
+// each case.
+const maxEvidenceHitsPerCase = 5
+
+// Highlighted words in search snippets are wrapped in these control
+// characters so that the caller can escape the snippet before marking them
+// up. They are removed from the searched text before ts_headline runs, so
+// every marker in a snippet comes from a match.
+const (
+	SearchHighlightStart = "\x02"
+	SearchHighlightStop  = "\x03"
+)
+
+var searchHeadlineOptions = "StartSel=" + SearchHighlightStart + ", StopSel=" + SearchHighlightStop +
+	", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// headline selects the highlighted snippet of text, an SQL expression,
+// for search_query. Each call takes the arguments from headlineArgs.
+func headline(text string) string {
+	return "ts_headline(?, translate(" + text + ", ?, ''), search_query, ?)"
+}
+
+// headlineArgs returns the arguments of n headline calls.
+func headlineArgs(n int) []interface{} {
+	args := make([]interface{}, 0, 3*n)
+	for range n {
+		args = append(args, searchConfig, SearchHighlightStart+SearchHighlightStop, searchHeadlineOptions)
+	}
+	return args
+}
+
+// evidenceMetadataText is the searchable Metadata text of an evidence row,
+// the same expression the evidences.search_vector column indexes.
+var evidenceMetadataText = func() string {
+	parts := make([]string, 0, len(models.EvidenceSearchKeys))
+	for _, key := range models.EvidenceSearchKeys {
+		parts = append(parts, "coalesce(evidences.metadata->>'"+key+"', '')")
+	}
+	return strings.Join(parts, " || ' ' || ")
+}()
+
+// CaseSearchQuery is a full-text search over cases. Text uses the web
+// search syntax: quoted phrases, OR, and a leading - to exclude a word.
+type CaseSearchQuery struct {
+	Text             string
+	MatchEvidence    bool // cases also match through their evidence
+	ShowConfidential bool // confidential evidence may match
+	Offset           int
+	Limit            int
+}
+
+// CaseSearchHit is a matching case with its rank and highlighted snippets.
+// Rank is the better of the case's own rank and its best evidence rank.
+type CaseSearchHit struct {
+	CaseID             uint
+	Rank               float64
+	TitleSnippet       string
+	DescriptionSnippet string
+	LocationSnippet    string
+}
+
+// EvidenceSearchHit is a matching evidence row with highlighted snippets.
+type EvidenceSearchHit struct {
+	EvidenceID         uint
+	CaseID             uint
+	Title              string
+	Rank               float64
+	TitleSnippet       string
+	DescriptionSnippet string
+	MetadataSnippet    string
+}
+
+// Search returns one page of the visible cases matching query, best
+// matches first.
+func (r *caseRepository) Search(ctx context.Context, query CaseSearchQuery) ([]CaseSearchHit, error) {
+	rank := "ts_rank(cases.search_vector, search_query)"
+	if query.MatchEvidence {
+		rank = "GREATEST(" + rank + ", COALESCE(evidence_matches.rank, 0))"
+	}
+
+	db := r.search(ctx, query).
+		Select(
+			"cases.id AS case_id, "+rank+" AS rank, "+
+				headline("cases.title")+" AS title_snippet, "+
+				headline("coalesce(cases.description, '')")+" AS description_snippet, "+
+				headline("coalesce(cases.location, '')")+" AS location_snippet",
+			headlineArgs(3)...,
+		).
+		Order("rank DESC, cases.id DESC")
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+
+	var hits []CaseSearchHit
+	if err := db.Limit(query.Limit).Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// CountSearch returns how many visible cases match query.
+func (r *caseRepository) CountSearch(ctx context.Context, query CaseSearchQuery) (int64, error) {
+	var count int64
+	err := r.search(ctx, query).Count(&count).Error
+	return count, err
+}
+
+// SearchEvidence returns the evidence of the given cases that matches
+// query, best matches first and at most maxEvidenceHitsPerCase per case. It
+// does not check case visibility; callers pass case IDs from Search.
+func (r *caseRepository) SearchEvidence(ctx context.Context, query CaseSearchQuery, caseIDs []uint) ([]EvidenceSearchHit, error) {
+	if !query.MatchEvidence || len(caseIDs) == 0 {
+		return nil, nil
+	}
+
+	ranked := r.evidenceMatches(ctx, query).
+		Select(
+			"evidences.id, ts_rank(evidences.search_vector, search_query) AS rank, "+
+				"ROW_NUMBER() OVER (PARTITION BY evidences.case_id "+
+				"ORDER BY ts_rank(evidences.search_vector, search_query) DESC, evidences.id) AS position",
+		).
+		Where("evidences.case_id IN ?", caseIDs)
+
+	var hits []EvidenceSearchHit
+	err := r.evidenceMatches(ctx, query).
+		Joins("JOIN (?) AS ranked ON ranked.id = evidences.id", ranked).
+		Select(
+			"evidences.id AS evidence_id, evidences.case_id, evidences.title, ranked.rank, "+
+				headline("evidences.title")+" AS title_snippet, "+
+				headline("coalesce(evidences.description, '')")+" AS description_snippet, "+
+				headline(evidenceMetadataText)+" AS metadata_snippet",
+			headlineArgs(3)...,
+		).
+		Where("ranked.position <= ?", maxEvidenceHitsPerCase).
+		Order("ranked.rank DESC, evidences.id").
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// FindByIDs returns the visible cases among ids, with the creator and
+// closer loaded, in no particular order.
+func (r *caseRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Case, error) {
+	var list []models.Case
+	err := r.visible(ctx).
+		Preload("CreatedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("ClosedBy", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("cases.id IN ?", ids).
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// search starts a query over the visible cases matching query, with the
+// parsed query available as search_query.
+func (r *caseRepository) search(ctx context.Context, query CaseSearchQuery) *gorm.DB {
+	db := r.visible(ctx).
+		Joins("CROSS JOIN websearch_to_tsquery(?, ?) AS search_query", searchConfig, query.Text)
+	if !query.MatchEvidence {
+		return db.Where("cases.search_vector @@ search_query")
+	}
+
+	matched := r.evidenceMatches(ctx, query).
+		Select("evidences.case_id, MAX(ts_rank(evidences.search_vector, search_query)) AS rank").
+		Group("evidences.case_id")
+	return db.
+		Joins("LEFT JOIN (?) AS evidence_matches ON evidence_matches.case_id = cases.id", matched).
+		Where("cases.search_vector @@ search_query OR evidence_matches.case_id IS NOT NULL")
+}
+
+// evidenceMatches starts a query over the evidence matching query, with the
+// parsed query available as search_query.
+func (r *caseRepository) evidenceMatches(ctx context.Context, query CaseSearchQuery) *gorm.DB {
+	db := getDB(ctx, r.db).Model(&models.Evidence{}).
+		Joins("CROSS JOIN websearch_to_tsquery(?, ?) AS search_query", searchConfig, query.Text).
+		Where("evidences.search_vector @@ search_query")
+	if !query.ShowConfidential {
+		db = db.Where("evidences.is_confidential = ?", false)
+	}
+	return db
+}
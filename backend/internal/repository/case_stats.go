@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// CaseStatsSummary totals the visible cases. MeanSecondsToClose is nil
+// while no case is closed.
+type CaseStatsSummary struct {
+	Total              int64
+	Closed             int64
+	MeanSecondsToClose *float64
+}
+
+type DepartmentCaseCount struct {
+	DepartmentID   *uint
+	DepartmentName string
+	Count          int64
+}
+
+type OfficerCaseCount struct {
+	OfficerID   uint
+	FirstName   string
+	LastName    string
+	BadgeNumber string
+	Count       int64
+}
+
+// CasePeriodCount counts the cases in one period, which starts at Period
+// in UTC. MeanSecondsToClose is only set by CountClosedByPeriod.
+type CasePeriodCount struct {
+	Period             time.Time
+	Count              int64
+	MeanSecondsToClose *float64
+}
+
+// Summarize totals the visible cases and averages how long the closed ones
+// took to close.
+func (r *caseRepository) Summarize(ctx context.Context) (*CaseStatsSummary, error) {
+	var summary CaseStatsSummary
+	err := r.visible(ctx).
+		Select("COUNT(*) AS total, COUNT(cases.closed_at) AS closed, " +
+			"AVG(EXTRACT(EPOCH FROM cases.closed_at - cases.created_at)) AS mean_seconds_to_close").
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// CountByStatus counts the visible cases in each status, keyed by status
+// name.
+func (r *caseRepository) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	return r.countBy(ctx, "cases.status")
+}
+
+// CountByPriority counts the visible cases of each priority.
+func (r *caseRepository) CountByPriority(ctx context.Context) (map[string]int64, error) {
+	return r.countBy(ctx, "cases.priority")
+}
+
+// countBy counts the visible cases by column, which must be chosen by the
+// caller.
+func (r *caseRepository) countBy(ctx context.Context, column string) (map[string]int64, error) {
+	var rows []struct {
+		Value string
+		Count int64
+	}
+	err := r.visible(ctx).
+		Select(column + " AS value, COUNT(*) AS count").
+		Group(column).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Value] = row.Count
+	}
+	return counts, nil
+}
+
+// CountByDepartment counts the visible cases by the department of their
+// creator, largest first.
+func (r *caseRepository) CountByDepartment(ctx context.Context) ([]DepartmentCaseCount, error) {
+	var rows []DepartmentCaseCount
+	err := r.visible(ctx).
+		Joins("LEFT JOIN users AS creators ON creators.id = cases.created_by_id").
+		Joins("LEFT JOIN departments ON departments.id = creators.department_id").
+		Select("creators.department_id, departments.name AS department_name, COUNT(*) AS count").
+		Group("creators.department_id, departments.name").
+		Order("count DESC, departments.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountByOfficer counts the visible cases each officer is currently
+// assigned to, largest first. Officers on no visible case are left out.
+func (r *caseRepository) CountByOfficer(ctx context.Context) ([]OfficerCaseCount, error) {
+	var rows []OfficerCaseCount
+	err := r.visible(ctx).
+		Joins("JOIN case_officers ON case_officers.case_id = cases.id AND case_officers.deleted_at IS NULL").
+		Joins("JOIN users AS officers ON officers.id = case_officers.officer_id").
+		Select("case_officers.officer_id, officers.first_name, officers.last_name, officers.badge_number, " +
+			"COUNT(DISTINCT cases.id) AS count").
+		Group("case_officers.officer_id, officers.first_name, officers.last_name, officers.badge_number").
+		Order("count DESC, officers.last_name, officers.first_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountOpenedByPeriod counts the visible cases created in [from, to) by the
+// period they were created in. Unit is a date_trunc unit chosen by the
+// caller; periods without cases are left out.
+func (r *caseRepository) CountOpenedByPeriod(ctx context.Context, unit string, from, to time.Time) ([]CasePeriodCount, error) {
+	var rows []CasePeriodCount
+	err := r.visible(ctx).
+		Select("date_trunc(?, cases.created_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS count", unit).
+		Where("cases.created_at >= ? AND cases.created_at < ?", from, to).
+		Group("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountClosedByPeriod is CountOpenedByPeriod for the cases closed in
+// [from, to), with how long they took to close on average.
+func (r *caseRepository) CountClosedByPeriod(ctx context.Context, unit string, from, to time.Time) ([]CasePeriodCount, error) {
+	var rows []CasePeriodCount
+	err := r.visible(ctx).
+		Select("date_trunc(?, cases.closed_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS count, "+
+			"AVG(EXTRACT(EPOCH FROM cases.closed_at - cases.created_at)) AS mean_seconds_to_close", unit).
+		Where("cases.closed_at >= ? AND cases.closed_at < ?", from, to).
+		Group("period").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// CountOpenAt counts the visible cases that were open at the given time, as
+// far as their current closure tells.
+func (r *caseRepository) CountOpenAt(ctx context.Context, at time.Time) (int64, error) {
+	var count int64
+	err := r.visible(ctx).
+		Where("cases.created_at < ? AND (cases.closed_at IS NULL OR cases.closed_at >= ?)", at, at).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds Postgres statements without connecting to a database.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	return db
+}
+
+func TestCaseOrder(t *testing.T) {
+	db := dryRunDB(t)
+
+	const priorityOrder = `CASE cases.priority WHEN $1 THEN 0 WHEN $2 THEN 1 WHEN $3 THEN 2 WHEN $4 THEN 3 END`
+	tests := []struct {
+		name  string
+		sorts []CaseSort
+		want  string
+	}{
+		{"no sort", nil, `cases.id`},
+		{"ascending", []CaseSort{{Column: "title"}}, `"cases"."title", cases.id`},
+		{"descending", []CaseSort{{Column: "created_at", Desc: true}}, `"cases"."created_at" DESC, cases.id DESC`},
+		{"priority by severity", []CaseSort{{Column: "priority", Desc: true}}, priorityOrder + ` DESC, cases.id DESC`},
+		{"tie-break follows the last sort", []CaseSort{{Column: "priority", Desc: true}, {Column: "title"}}, priorityOrder + ` DESC, "cases"."title", cases.id`},
+		{"tie-break follows the last sort descending", []CaseSort{{Column: "status"}, {Column: "updated_at", Desc: true}}, `"cases"."status", "cases"."updated_at" DESC, cases.id DESC`},
+	}
+	for _, tt := range tests {
+		stmt := db.Model(&models.Case{}).Clauses(caseOrder(tt.sorts)).Find(&[]models.Case{}).Statement
+
+		want := `SELECT * FROM "cases" WHERE "cases"."deleted_at" IS NULL ORDER BY ` + tt.want
+		if got := stmt.SQL.String(); got != want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, want)
+		}
+	}
+
+	stmt := db.Model(&models.Case{}).Clauses(caseOrder([]CaseSort{{Column: "priority"}})).Find(&[]models.Case{}).Statement
+	if len(stmt.Vars) != len(models.CasePriorities) {
+		t.Fatalf("priority order binds %v, want %v", stmt.Vars, models.CasePriorities)
+	}
+	for i, priority := range models.CasePriorities {
+		if stmt.Vars[i] != priority {
+			t.Errorf("priority %d binds %v, want %q", i, stmt.Vars[i], priority)
+		}
+	}
+}
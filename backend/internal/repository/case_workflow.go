@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type CaseWorkflowRepository interface {
+	CreateStatus(ctx context.Context, status *models.CaseStatus) error
+	UpdateStatus(ctx context.Context, status *models.CaseStatus) error
+	DeleteStatus(ctx context.Context, id uint) error
+	FindStatuses(ctx context.Context) ([]models.CaseStatus, error)
+	FindStatusByID(ctx context.Context, id uint) (*models.CaseStatus, error)
+	FindStatusByName(ctx context.Context, name string) (*models.CaseStatus, error)
+	FindInitialStatus(ctx context.Context) (*models.CaseStatus, error)
+	ExistsStatusByName(ctx context.Context, name string, excludeID uint) (bool, error)
+	ClearInitial(ctx context.Context, excludeID uint) error
+	RenameCaseStatus(ctx context.Context, oldName, newName string) error
+	CountCasesWithStatus(ctx context.Context, name string) (int64, error)
+
+	CreateTransition(ctx context.Context, transition *models.CaseTransition) error
+	UpdateTransition(ctx context.Context, transition *models.CaseTransition) error
+	DeleteTransition(ctx context.Context, id uint) error
+	DeleteTransitionsByStatus(ctx context.Context, statusID uint) error
+	FindTransitions(ctx context.Context) ([]models.CaseTransition, error)
+	FindTransitionByID(ctx context.Context, id uint) (*models.CaseTransition, error)
+	FindTransitionsFrom(ctx context.Context, statusID uint) ([]models.CaseTransition, error)
+	FindTransition(ctx context.Context, fromStatusID, toStatusID uint) (*models.CaseTransition, error)
+	ExistsTransition(ctx context.Context, fromStatusID, toStatusID uint, excludeID uint) (bool, error)
+}
+
+type caseWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewCaseWorkflowRepository(db *gorm.DB) CaseWorkflowRepository {
+	return &caseWorkflowRepository{db: db}
+}
+
+func (r *caseWorkflowRepository) CreateStatus(ctx context.Context, status *models.CaseStatus) error {
+	return getDB(ctx, r.db).Create(status).Error
+}
+
+func (r *caseWorkflowRepository) UpdateStatus(ctx context.Context, status *models.CaseStatus) error {
+	return getDB(ctx, r.db).Model(status).
+		Select("name", "description", "is_initial", "is_closed", "sort_order").
+		Updates(status).Error
+}
+
+func (r *caseWorkflowRepository) DeleteStatus(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CaseStatus{}, id).Error
+}
+
+func (r *caseWorkflowRepository) FindStatuses(ctx context.Context) ([]models.CaseStatus, error) {
+	var statuses []models.CaseStatus
+	if err := getDB(ctx, r.db).Order("sort_order, id").Find(&statuses).Error; err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (r *caseWorkflowRepository) FindStatusByID(ctx context.Context, id uint) (*models.CaseStatus, error) {
+	return r.firstStatus(getDB(ctx, r.db).Where("id = ?", id))
+}
+
+// FindStatusByName matches the name ignoring case.
+func (r *caseWorkflowRepository) FindStatusByName(ctx context.Context, name string) (*models.CaseStatus, error) {
+	return r.firstStatus(getDB(ctx, r.db).Where("LOWER(name) = LOWER(?)", name))
+}
+
+func (r *caseWorkflowRepository) FindInitialStatus(ctx context.Context) (*models.CaseStatus, error) {
+	return r.firstStatus(getDB(ctx, r.db).Where("is_initial = ?", true))
+}
+
+func (r *caseWorkflowRepository) firstStatus(db *gorm.DB) (*models.CaseStatus, error) {
+	var status models.CaseStatus
+	if err := db.Order("id").First(&status).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &status, nil
+}
+
+// ExistsStatusByName reports whether another live status than excludeID
+// is called name, ignoring case.
+func (r *caseWorkflowRepository) ExistsStatusByName(ctx context.Context, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.CaseStatus{}).
+		Where("LOWER(name) = LOWER(?)", name).
+		Where("id <> ?", excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ClearInitial unsets IsInitial on every status except excludeID. A unique
+// index allows one initial status, so callers clear it before saving a new
+// one.
+func (r *caseWorkflowRepository) ClearInitial(ctx context.Context, excludeID uint) error {
+	return getDB(ctx, r.db).Model(&models.CaseStatus{}).
+		Where("is_initial = ? AND id <> ?", true, excludeID).
+		Update("is_initial", false).Error
+}
+
+// RenameCaseStatus moves every case, deleted ones included, from oldName to
+// newName, so that restored cases still match a status.
+func (r *caseWorkflowRepository) RenameCaseStatus(ctx context.Context, oldName, newName string) error {
+	return getDB(ctx, r.db).Unscoped().Model(&models.Case{}).
+		Where("status = ?", oldName).
+		Update("status", newName).Error
+}
+
+// CountCasesWithStatus counts the live cases in the status called name.
+func (r *caseWorkflowRepository) CountCasesWithStatus(ctx context.Context, name string) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.Case{}).
+		Where("status = ?", name).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *caseWorkflowRepository) CreateTransition(ctx context.Context, transition *models.CaseTransition) error {
+	return getDB(ctx, r.db).Omit("FromStatus", "ToStatus").Create(transition).Error
+}
+
+func (r *caseWorkflowRepository) UpdateTransition(ctx context.Context, transition *models.CaseTransition) error {
+	return getDB(ctx, r.db).Model(transition).
+		Select("name", "from_status_id", "to_status_id", "required_permission", "requires_reason").
+		Updates(transition).Error
+}
+
+func (r *caseWorkflowRepository) DeleteTransition(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CaseTransition{}, id).Error
+}
+
+// DeleteTransitionsByStatus deletes the transitions into and out of the
+// status.
+func (r *caseWorkflowRepository) DeleteTransitionsByStatus(ctx context.Context, statusID uint) error {
+	return getDB(ctx, r.db).
+		Where("from_status_id = ? OR to_status_id = ?", statusID, statusID).
+		Delete(&models.CaseTransition{}).Error
+}
+
+func (r *caseWorkflowRepository) FindTransitions(ctx context.Context) ([]models.CaseTransition, error) {
+	var transitions []models.CaseTransition
+	err := getDB(ctx, r.db).
+		Preload("FromStatus").
+		Preload("ToStatus").
+		Order("from_status_id, id").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *caseWorkflowRepository) FindTransitionByID(ctx context.Context, id uint) (*models.CaseTransition, error) {
+	var transition models.CaseTransition
+	err := getDB(ctx, r.db).
+		Preload("FromStatus").
+		Preload("ToStatus").
+		First(&transition, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transition, nil
+}
+
+// FindTransitionsFrom returns the transitions leaving the status, with
+// their target status loaded.
+func (r *caseWorkflowRepository) FindTransitionsFrom(ctx context.Context, statusID uint) ([]models.CaseTransition, error) {
+	var transitions []models.CaseTransition
+	err := getDB(ctx, r.db).
+		Preload("ToStatus").
+		Where("from_status_id = ?", statusID).
+		Order("id").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+func (r *caseWorkflowRepository) FindTransition(ctx context.Context, fromStatusID, toStatusID uint) (*models.CaseTransition, error) {
+	var transition models.CaseTransition
+	err := getDB(ctx, r.db).
+		Where("from_status_id = ? AND to_status_id = ?", fromStatusID, toStatusID).
+		Order("id").
+		First(&transition).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transition, nil
+}
+
+func (r *caseWorkflowRepository) ExistsTransition(ctx context.Context, fromStatusID, toStatusID uint, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.CaseTransition{}).
+		Where("from_status_id = ? AND to_status_id = ?", fromStatusID, toStatusID).
+		Where("id <> ?", excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
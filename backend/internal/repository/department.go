@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentRepository interface {
+	Create(ctx context.Context, department *models.Department) error
+	Update(ctx context.Context, department *models.Department) error
+	Delete(ctx context.Context, id uint) error
+	FindAll(ctx context.Context) ([]models.Department, error)
+	FindByID(ctx context.Context, id uint) (*models.Department, error)
+	FindByIDWithHead(ctx context.Context, id uint) (*models.Department, error)
+	ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error)
+	CountMembers(ctx context.Context, id uint) (int64, error)
+	UpdateHead(ctx context.Context, id uint, headID *uint) error
+	ClearHead(ctx context.Context, userID uint) error
+	FindAutoProvisionByEmailDomain(ctx context.Context, domain string) (*models.Department, error)
+}
+
+type departmentRepository struct {
+	db *gorm.DB
+}
+
+func NewDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &departmentRepository{db: db}
+}
+
+func (r *departmentRepository) Create(ctx context.Context, department *models.Department) error {
+	return getDB(ctx, r.db).Omit("Head", "Users").Create(department).Error
+}
+
+func (r *departmentRepository) Update(ctx context.Context, department *models.Department) error {
+	return getDB(ctx, r.db).Model(department).
+		Select("name", "description", "email_domain", "google_auto_provision").
+		Updates(department).Error
+}
+
+func (r *departmentRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.Department{}, id).Error
+}
+
+func (r *departmentRepository) FindAll(ctx context.Context) ([]models.Department, error) {
+	var departments []models.Department
+	if err := getDB(ctx, r.db).Preload("Head").Order("name, id").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+func (r *departmentRepository) FindByID(ctx context.Context, id uint) (*models.Department, error) {
+	var department models.Department
+	if err := getDB(ctx, r.db).First(&department, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &department, nil
+}
+
+func (r *departmentRepository) FindByIDWithHead(ctx context.Context, id uint) (*models.Department, error) {
+	var department models.Department
+	if err := getDB(ctx, r.db).Preload("Head").First(&department, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &department, nil
+}
+
+// ExistsByName reports whether another live department than excludeID uses
+// name, ignoring case.
+func (r *departmentRepository) ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.Department{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ?", name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *departmentRepository) CountMembers(ctx context.Context, id uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.User{}).
+		Where("department_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *departmentRepository) UpdateHead(ctx context.Context, id uint, headID *uint) error {
+	return getDB(ctx, r.db).Model(&models.Department{}).Where("id = ?", id).Update("head_id", headID).Error
+}
+
+// ClearHead removes userID as head of whichever department they lead.
+func (r *departmentRepository) ClearHead(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Model(&models.Department{}).Where("head_id = ?", userID).Update("head_id", nil).Error
+}
+
+// FindAutoProvisionByEmailDomain returns the department that accepts
+// Google sign-up for addresses in domain, if any.
+func (r *departmentRepository) FindAutoProvisionByEmailDomain(ctx context.Context, domain string) (*models.Department, error) {
+	var department models.Department
+	err := getDB(ctx, r.db).
+		Where("LOWER(email_domain) = LOWER(?) AND google_auto_provision = ?", domain, true).
+		Order("id").
+		First(&department).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &department, nil
+}
@@ -0,0 +1,8 @@
+package repository
+
+import "gorm.io/gorm"
+
+// ErrDuplicateKey is returned by writes that violate a unique index. The
+// database connection must be opened with TranslateError for gorm to
+// produce it.
+var ErrDuplicateKey = gorm.ErrDuplicatedKey
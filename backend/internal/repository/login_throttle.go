@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository interface {
+	Find(ctx context.Context, scope, key string) (*models.LoginThrottle, error)
+	FindOrCreateForUpdate(ctx context.Context, scope, key string) (*models.LoginThrottle, error)
+	Save(ctx context.Context, throttle *models.LoginThrottle) error
+	Delete(ctx context.Context, scope, key string) error
+}
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Find(ctx context.Context, scope, key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := getDB(ctx, r.db).
+		Where("scope = ? AND key = ?", scope, key).
+		First(&throttle).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// FindOrCreateForUpdate returns the row for scope and key, creating an empty
+// one first if needed, and locks it until the surrounding transaction ends.
+func (r *loginThrottleRepository) FindOrCreateForUpdate(ctx context.Context, scope, key string) (*models.LoginThrottle, error) {
+	db := getDB(ctx, r.db)
+
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LoginThrottle{Scope: scope, Key: key}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var throttle models.LoginThrottle
+	err = db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("scope = ? AND key = ?", scope, key).
+		First(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *loginThrottleRepository) Save(ctx context.Context, throttle *models.LoginThrottle) error {
+	return getDB(ctx, r.db).Save(throttle).Error
+}
+
+func (r *loginThrottleRepository) Delete(ctx context.Context, scope, key string) error {
+	return getDB(ctx, r.db).
+		Where("scope = ? AND key = ?", scope, key).
+		Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepository interface {
+	CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error
+	FindChallengeByHash(ctx context.Context, hash string) (*models.MFAChallenge, error)
+	FindChallengeByHashForUpdate(ctx context.Context, hash string) (*models.MFAChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, id uint) error
+	MarkChallengeUsed(ctx context.Context, id uint, at time.Time) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error
+	DeleteRecoveryCodes(ctx context.Context, userID uint) error
+	FindUnusedRecoveryCode(ctx context.Context, userID uint, codeHash string) (*models.MFARecoveryCode, error)
+	MarkRecoveryCodeUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) CreateChallenge(ctx context.Context, challenge *models.MFAChallenge) error {
+	return getDB(ctx, r.db).Omit("User").Create(challenge).Error
+}
+
+func (r *mfaRepository) FindChallengeByHash(ctx context.Context, hash string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	err := getDB(ctx, r.db).Where("token_hash = ?", hash).First(&challenge).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// FindChallengeByHashForUpdate locks the challenge row so attempts are
+// counted exactly; callers must run inside a transaction.
+func (r *mfaRepository) FindChallengeByHashForUpdate(ctx context.Context, hash string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).
+		First(&challenge).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *mfaRepository) IncrementChallengeAttempts(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Model(&models.MFAChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+func (r *mfaRepository) MarkChallengeUsed(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.MFAChallenge{}).
+		Where("id = ?", id).
+		Update("used_at", at).Error
+}
+
+// ReplaceRecoveryCodes discards the user's previous recovery codes and
+// stores the new set.
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, codeHashes []string) error {
+	if err := r.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+
+	codes := make([]models.MFARecoveryCode, 0, len(codeHashes))
+	for _, hash := range codeHashes {
+		codes = append(codes, models.MFARecoveryCode{UserID: userID, CodeHash: hash})
+	}
+	return getDB(ctx, r.db).Omit("User").Create(&codes).Error
+}
+
+func (r *mfaRepository) DeleteRecoveryCodes(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&models.MFARecoveryCode{}).Error
+}
+
+func (r *mfaRepository) FindUnusedRecoveryCode(ctx context.Context, userID uint, codeHash string) (*models.MFARecoveryCode, error) {
+	var code models.MFARecoveryCode
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		First(&code).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (r *mfaRepository) MarkRecoveryCodeUsed(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.MFARecoveryCode{}).
+		Where("id = ?", id).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OIDCLoginStateRepository interface {
+	Create(ctx context.Context, state *models.OIDCLoginState) error
+	FindByHashForUpdate(ctx context.Context, hash string) (*models.OIDCLoginState, error)
+	MarkUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type oidcLoginStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCLoginStateRepository(db *gorm.DB) OIDCLoginStateRepository {
+	return &oidcLoginStateRepository{db: db}
+}
+
+func (r *oidcLoginStateRepository) Create(ctx context.Context, state *models.OIDCLoginState) error {
+	return getDB(ctx, r.db).Create(state).Error
+}
+
+// FindByHashForUpdate locks the state row so a callback cannot be replayed
+// concurrently; callers must run inside a transaction.
+func (r *oidcLoginStateRepository) FindByHashForUpdate(ctx context.Context, hash string) (*models.OIDCLoginState, error) {
+	var state models.OIDCLoginState
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("state_hash = ?", hash).
+		First(&state).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *oidcLoginStateRepository) MarkUsed(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.OIDCLoginState{}).
+		Where("id = ?", id).
+		Update("used_at", at).Error
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// KeysetCursor is the sort value and id of the last row of the previous
+// page.
+type KeysetCursor struct {
+	Value interface{}
+	ID    uint
+}
+
+// keysetAfter matches the rows that follow cursor when ordering by column
+// and then id, both ascending or both descending.
+func keysetAfter(column string, desc bool, cursor *KeysetCursor) clause.Expression {
+	after := func(column string, value interface{}) clause.Expression {
+		if desc {
+			return clause.Lt{Column: clause.Column{Name: column}, Value: value}
+		}
+		return clause.Gt{Column: clause.Column{Name: column}, Value: value}
+	}
+
+	return clause.Or(
+		after(column, cursor.Value),
+		clause.And(
+			clause.Eq{Column: clause.Column{Name: column}, Value: cursor.Value},
+			after("id", cursor.ID),
+		),
+	)
+}
+
+// escapeLike escapes the LIKE wildcards in s, for patterns that use
+// ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	FindByHashForUpdate(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id uint, at time.Time) error
+	InvalidateByUserID(ctx context.Context, userID uint) error
+}
+
+type passwordResetTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) PasswordResetTokenRepository {
+	return &passwordResetTokenRepository{db: db}
+}
+
+func (r *passwordResetTokenRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	return getDB(ctx, r.db).Omit("User").Create(token).Error
+}
+
+// FindByHashForUpdate locks the token row so a token cannot be redeemed twice
+// concurrently; callers must run inside a transaction.
+func (r *passwordResetTokenRepository) FindByHashForUpdate(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", hash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *passwordResetTokenRepository) MarkUsed(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.PasswordResetToken{}).
+		Where("id = ?", id).
+		Update("used_at", at).Error
+}
+
+// InvalidateByUserID marks every outstanding token of the user as used.
+func (r *passwordResetTokenRepository) InvalidateByUserID(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
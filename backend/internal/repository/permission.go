@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type PermissionRepository interface {
+	FindByID(ctx context.Context, id uint) (*models.Permission, error)
+	FindByCode(ctx context.Context, code string) (*models.Permission, error)
+	FindCategoriesWithPermissions(ctx context.Context) ([]models.PermissionCategory, error)
+	FindCodesByRoleIDs(ctx context.Context, roleIDs []uint) ([]string, error)
+	FindRolePermission(ctx context.Context, roleID, permissionID uint) (*models.RolePermission, error)
+	FindRolePermissionsByRoleIDs(ctx context.Context, roleIDs []uint) ([]models.RolePermission, error)
+	CreateRolePermission(ctx context.Context, rolePermission *models.RolePermission) error
+	DeleteRolePermission(ctx context.Context, rolePermission *models.RolePermission) error
+}
+
+type permissionRepository struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) FindByID(ctx context.Context, id uint) (*models.Permission, error) {
+	var permission models.Permission
+	if err := getDB(ctx, r.db).First(&permission, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *permissionRepository) FindByCode(ctx context.Context, code string) (*models.Permission, error) {
+	var permission models.Permission
+	if err := getDB(ctx, r.db).Where("code = ?", code).First(&permission).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &permission, nil
+}
+
+func (r *permissionRepository) FindCategoriesWithPermissions(ctx context.Context) ([]models.PermissionCategory, error) {
+	var categories []models.PermissionCategory
+	err := getDB(ctx, r.db).
+		Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("code")
+		}).
+		Order("name").
+		Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// FindCodesByRoleIDs returns the distinct permission codes granted to any of
+// the given roles.
+func (r *permissionRepository) FindCodesByRoleIDs(ctx context.Context, roleIDs []uint) ([]string, error) {
+	codes := []string{}
+	if len(roleIDs) == 0 {
+		return codes, nil
+	}
+
+	err := getDB(ctx, r.db).Model(&models.Permission{}).
+		Distinct("permissions.code").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id AND role_permissions.deleted_at IS NULL").
+		Where("role_permissions.role_id IN ?", roleIDs).
+		Pluck("permissions.code", &codes).Error
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (r *permissionRepository) FindRolePermissionsByRoleIDs(ctx context.Context, roleIDs []uint) ([]models.RolePermission, error) {
+	var rolePermissions []models.RolePermission
+	if len(roleIDs) == 0 {
+		return rolePermissions, nil
+	}
+
+	err := getDB(ctx, r.db).
+		Preload("Permission").
+		Where("role_id IN ?", roleIDs).
+		Find(&rolePermissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return rolePermissions, nil
+}
+
+func (r *permissionRepository) FindRolePermission(ctx context.Context, roleID, permissionID uint) (*models.RolePermission, error) {
+	var rolePermission models.RolePermission
+	err := getDB(ctx, r.db).
+		Preload("Permission").
+		Where("role_id = ? AND permission_id = ?", roleID, permissionID).
+		First(&rolePermission).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rolePermission, nil
+}
+
+func (r *permissionRepository) CreateRolePermission(ctx context.Context, rolePermission *models.RolePermission) error {
+	return getDB(ctx, r.db).Omit("Role", "Permission", "GrantedBy").Create(rolePermission).Error
+}
+
+func (r *permissionRepository) DeleteRolePermission(ctx context.Context, rolePermission *models.RolePermission) error {
+	return getDB(ctx, r.db).Delete(rolePermission).Error
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByID(ctx context.Context, id uint) (*models.RefreshToken, error)
+	FindByHashForUpdate(ctx context.Context, hash string) (*models.RefreshToken, error)
+	MarkRotated(ctx context.Context, id, replacedByID uint) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeByUserID(ctx context.Context, userID uint) error
+}
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return getDB(ctx, r.db).Create(token).Error
+}
+
+func (r *refreshTokenRepository) FindByID(ctx context.Context, id uint) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := getDB(ctx, r.db).First(&token, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByHashForUpdate locks the token row so concurrent refreshes of the same
+// token are serialised; callers must run inside a transaction.
+func (r *refreshTokenRepository) FindByHashForUpdate(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token = ?", hash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *refreshTokenRepository) MarkRotated(ctx context.Context, id, replacedByID uint) error {
+	return getDB(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"is_revoked":     true,
+			"revoked_at":     time.Now(),
+			"replaced_by_id": replacedByID,
+		}).Error
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return getDB(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND is_revoked = ?", familyID, false).
+		Updates(map[string]interface{}{
+			"is_revoked": true,
+			"revoked_at": time.Now(),
+		}).Error
+}
+
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND is_revoked = ?", userID, false).
+		Updates(map[string]interface{}{
+			"is_revoked": true,
+			"revoked_at": time.Now(),
+		}).Error
+}
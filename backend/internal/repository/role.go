@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *models.Role) error
+	Update(ctx context.Context, role *models.Role) error
+	Delete(ctx context.Context, role *models.Role) error
+	FindAll(ctx context.Context) ([]models.Role, error)
+	FindByID(ctx context.Context, id uint) (*models.Role, error)
+	FindByName(ctx context.Context, name string) (*models.Role, error)
+	UpdateParent(ctx context.Context, id uint, parentRoleID *uint, updatedByID uint) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *models.Role) error {
+	return getDB(ctx, r.db).Omit("ParentRole", "CreatedBy", "UpdatedBy").Create(role).Error
+}
+
+// Update saves the editable columns of role.
+func (r *roleRepository) Update(ctx context.Context, role *models.Role) error {
+	return getDB(ctx, r.db).Model(role).
+		Select("name", "description", "level", "mfa_required", "updated_by_id").
+		Updates(role).Error
+}
+
+func (r *roleRepository) Delete(ctx context.Context, role *models.Role) error {
+	return getDB(ctx, r.db).Delete(role).Error
+}
+
+func (r *roleRepository) FindAll(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+	if err := getDB(ctx, r.db).Order("level DESC, id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) FindByID(ctx context.Context, id uint) (*models.Role, error) {
+	var role models.Role
+	if err := getDB(ctx, r.db).First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	var role models.Role
+	if err := getDB(ctx, r.db).Where("LOWER(name) = LOWER(?)", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) UpdateParent(ctx context.Context, id uint, parentRoleID *uint, updatedByID uint) error {
+	return getDB(ctx, r.db).Model(&models.Role{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"parent_role_id": parentRoleID,
+			"updated_by_id":  updatedByID,
+		}).Error
+}
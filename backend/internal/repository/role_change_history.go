@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RoleChangeHistoryRepository interface {
+	Create(ctx context.Context, history *models.RoleChangeHistory) error
+	FindByRoleID(ctx context.Context, roleID uint) ([]models.RoleChangeHistory, error)
+}
+
+type roleChangeHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleChangeHistoryRepository(db *gorm.DB) RoleChangeHistoryRepository {
+	return &roleChangeHistoryRepository{db: db}
+}
+
+func (r *roleChangeHistoryRepository) Create(ctx context.Context, history *models.RoleChangeHistory) error {
+	return getDB(ctx, r.db).Create(history).Error
+}
+
+func (r *roleChangeHistoryRepository) FindByRoleID(ctx context.Context, roleID uint) ([]models.RoleChangeHistory, error) {
+	var history []models.RoleChangeHistory
+	err := getDB(ctx, r.db).
+		Where("role_id = ?", roleID).
+		Order("created_at DESC, id DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type RoleManagementRepository interface {
+	Create(ctx context.Context, rule *models.RoleManagementPermission) error
+	FindManageableRoleIDs(ctx context.Context, managerRoleIDs []uint) ([]uint, error)
+}
+
+type roleManagementRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleManagementRepository(db *gorm.DB) RoleManagementRepository {
+	return &roleManagementRepository{db: db}
+}
+
+func (r *roleManagementRepository) Create(ctx context.Context, rule *models.RoleManagementPermission) error {
+	return getDB(ctx, r.db).Create(rule).Error
+}
+
+// FindManageableRoleIDs returns the roles that any of the manager roles may
+// assign, unassign or edit.
+func (r *roleManagementRepository) FindManageableRoleIDs(ctx context.Context, managerRoleIDs []uint) ([]uint, error) {
+	roleIDs := []uint{}
+	if len(managerRoleIDs) == 0 {
+		return roleIDs, nil
+	}
+
+	err := getDB(ctx, r.db).Model(&models.RoleManagementPermission{}).
+		Distinct("manageable_role_id").
+		Where("manager_role_id IN ?", managerRoleIDs).
+		Pluck("manageable_role_id", &roleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleIDs, nil
+}
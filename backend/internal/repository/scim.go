@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"backend/internal/scim"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ScimRepository answers the SCIM list queries. SCIM filters are compiled
+// to SQL over the attributes listed in scimUserAttrs and scimGroupAttrs;
+// filtering on anything else is rejected as an invalid filter.
+type ScimRepository interface {
+	ListUsers(ctx context.Context, filter scim.Filter, offset, limit int) ([]models.User, int64, error)
+	ListGroups(ctx context.Context, filter scim.Filter, offset, limit int) ([]models.Role, int64, error)
+	FindUserRoles(ctx context.Context, userIDs []uint) ([]models.UserRole, error)
+	FindRoleMembers(ctx context.Context, roleIDs []uint) ([]models.UserRole, error)
+}
+
+type scimRepository struct {
+	db *gorm.DB
+}
+
+func NewScimRepository(db *gorm.DB) ScimRepository {
+	return &scimRepository{db: db}
+}
+
+type scimKind int
+
+const (
+	scimString scimKind = iota
+	scimBool
+	scimID
+	scimTime
+	scimMember // group membership, filtered through user_roles
+)
+
+type scimAttr struct {
+	column string
+	kind   scimKind
+}
+
+// Keys are scim.AttrPath.Key values.
+var scimUserAttrs = map[string]scimAttr{
+	"id":                 {"users.id", scimID},
+	"username":           {"users.email", scimString},
+	"emails":             {"users.email", scimString},
+	"emails.value":       {"users.email", scimString},
+	"name.givenname":     {"users.first_name", scimString},
+	"name.familyname":    {"users.last_name", scimString},
+	"phonenumbers":       {"users.phone_number", scimString},
+	"phonenumbers.value": {"users.phone_number", scimString},
+	"active":             {"users.is_active", scimBool},
+	"meta.created":       {"users.created_at", scimTime},
+	"meta.lastmodified":  {"users.updated_at", scimTime},
+	strings.ToLower(scim.SchemaUserExtension) + ":badgenumber": {"users.badge_number", scimString},
+}
+
+var scimGroupAttrs = map[string]scimAttr{
+	"id":                {"roles.id", scimID},
+	"displayname":       {"roles.name", scimString},
+	"members":           {"roles.id", scimMember},
+	"members.value":     {"roles.id", scimMember},
+	"meta.created":      {"roles.created_at", scimTime},
+	"meta.lastmodified": {"roles.updated_at", scimTime},
+}
+
+func (r *scimRepository) ListUsers(ctx context.Context, filter scim.Filter, offset, limit int) ([]models.User, int64, error) {
+	db := getDB(ctx, r.db).Model(&models.User{})
+	db, err := r.where(ctx, db, filter, scimUserAttrs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if limit > 0 {
+		if err := db.Order("users.id").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	return users, total, nil
+}
+
+func (r *scimRepository) ListGroups(ctx context.Context, filter scim.Filter, offset, limit int) ([]models.Role, int64, error) {
+	db := getDB(ctx, r.db).Model(&models.Role{})
+	db, err := r.where(ctx, db, filter, scimGroupAttrs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var roles []models.Role
+	if limit > 0 {
+		if err := db.Order("roles.id").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	return roles, total, nil
+}
+
+// FindUserRoles returns the role assignments of the users, with Role loaded.
+func (r *scimRepository) FindUserRoles(ctx context.Context, userIDs []uint) ([]models.UserRole, error) {
+	var userRoles []models.UserRole
+	err := getDB(ctx, r.db).
+		Preload("Role").
+		Where("user_id IN ?", userIDs).
+		Order("id").
+		Find(&userRoles).Error
+	return userRoles, err
+}
+
+// FindRoleMembers returns the assignments of the roles to users that still
+// exist, with User loaded.
+func (r *scimRepository) FindRoleMembers(ctx context.Context, roleIDs []uint) ([]models.UserRole, error) {
+	var userRoles []models.UserRole
+	err := getDB(ctx, r.db).
+		InnerJoins("User").
+		Where("user_roles.role_id IN ?", roleIDs).
+		Order("user_roles.id").
+		Find(&userRoles).Error
+	return userRoles, err
+}
+
+func (r *scimRepository) where(ctx context.Context, db *gorm.DB, filter scim.Filter, attrs map[string]scimAttr) (*gorm.DB, error) {
+	if filter == nil {
+		return db, nil
+	}
+	c := &scimCompiler{ctx: ctx, db: r.db, attrs: attrs}
+	sql, args, err := c.compile(filter, nil)
+	if err != nil {
+		return nil, err
+	}
+	return db.Where(sql, args...), nil
+}
+
+type scimCompiler struct {
+	ctx   context.Context
+	db    *gorm.DB
+	attrs map[string]scimAttr
+}
+
+// compile turns the filter into a SQL condition. parent is set inside a
+// value path such as emails[value eq "x"], whose attributes are relative to
+// it.
+func (c *scimCompiler) compile(filter scim.Filter, parent *scim.AttrPath) (string, []interface{}, error) {
+	switch f := filter.(type) {
+	case *scim.Logical:
+		left, leftArgs, err := c.compile(f.Left, parent)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := c.compile(f.Right, parent)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s) %s (%s)", left, strings.ToUpper(f.Op), right), append(leftArgs, rightArgs...), nil
+	case *scim.Not:
+		sql, args, err := c.compile(f.Filter, parent)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + sql + ")", args, nil
+	case *scim.ValuePath:
+		if parent != nil {
+			return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: "nested value paths are not supported"}
+		}
+		return c.compile(f.Filter, &f.Path)
+	case *scim.Comparison:
+		path := f.Path
+		if parent != nil {
+			path = scim.AttrPath{URN: parent.URN, Name: parent.Name, Sub: f.Path.Name}
+		}
+		attr, ok := c.attrs[path.Key()]
+		if !ok {
+			return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: fmt.Sprintf("filtering on %q is not supported", path.Key())}
+		}
+		return c.comparison(attr, f.Op, f.Value)
+	}
+	return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: "invalid filter"}
+}
+
+func (c *scimCompiler) comparison(attr scimAttr, op string, value interface{}) (string, []interface{}, error) {
+	unsupported := &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: fmt.Sprintf("operator %q is not supported for this attribute", op)}
+	col := attr.column
+
+	if op == "pr" {
+		switch attr.kind {
+		case scimString:
+			return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", col, col), nil, nil
+		case scimMember:
+			return fmt.Sprintf("%s IN (?)", col), []interface{}{c.members(nil)}, nil
+		default:
+			return col + " IS NOT NULL", nil, nil
+		}
+	}
+
+	switch attr.kind {
+	case scimString:
+		s, ok := value.(string)
+		if !ok {
+			return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: "expected a string value"}
+		}
+		s = strings.ToLower(s)
+		switch op {
+		case "eq", "ne", "gt", "ge", "lt", "le":
+			return fmt.Sprintf("LOWER(%s) %s ?", col, sqlOperators[op]), []interface{}{s}, nil
+		case "co":
+			return fmt.Sprintf(`LOWER(%s) LIKE ? ESCAPE '\'`, col), []interface{}{"%" + escapeLike(s) + "%"}, nil
+		case "sw":
+			return fmt.Sprintf(`LOWER(%s) LIKE ? ESCAPE '\'`, col), []interface{}{escapeLike(s) + "%"}, nil
+		case "ew":
+			return fmt.Sprintf(`LOWER(%s) LIKE ? ESCAPE '\'`, col), []interface{}{"%" + escapeLike(s)}, nil
+		}
+	case scimBool:
+		b, ok := value.(bool)
+		if !ok {
+			return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: "expected a boolean value"}
+		}
+		if op == "eq" || op == "ne" {
+			return fmt.Sprintf("%s %s ?", col, sqlOperators[op]), []interface{}{b}, nil
+		}
+	case scimID, scimMember:
+		id, ok := parseScimID(value)
+		if !ok {
+			// IDs are numeric, so no row can match.
+			if op == "ne" {
+				return "1 = 1", nil, nil
+			}
+			return "1 = 0", nil, nil
+		}
+		if attr.kind == scimMember {
+			if op == "eq" {
+				return fmt.Sprintf("%s IN (?)", col), []interface{}{c.members(&id)}, nil
+			}
+			return "", nil, unsupported
+		}
+		if sqlOperators[op] != "" {
+			return fmt.Sprintf("%s %s ?", col, sqlOperators[op]), []interface{}{id}, nil
+		}
+	case scimTime:
+		s, _ := value.(string)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", nil, &scim.Error{ScimType: scim.ErrTypeInvalidFilter, Detail: "expected an RFC 3339 date-time value"}
+		}
+		if sqlOperators[op] != "" {
+			return fmt.Sprintf("%s %s ?", col, sqlOperators[op]), []interface{}{t}, nil
+		}
+	}
+	return "", nil, unsupported
+}
+
+var sqlOperators = map[string]string{
+	"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<=",
+}
+
+// members selects the roles assigned to userID, or to anyone.
+func (c *scimCompiler) members(userID *uint) *gorm.DB {
+	db := getDB(c.ctx, c.db).Model(&models.UserRole{}).Select("role_id")
+	if userID != nil {
+		db = db.Where("user_id = ?", *userID)
+	}
+	return db
+}
+
+func parseScimID(value interface{}) (uint, bool) {
+	switch v := value.(type) {
+	case string:
+		id, err := strconv.ParseUint(v, 10, 64)
+		return uint(id), err == nil && id > 0
+	case float64:
+		return uint(v), v > 0 && v == float64(uint(v))
+	}
+	return 0, false
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"backend/internal/scim"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScimCompile(t *testing.T) {
+	c := &scimCompiler{ctx: context.Background(), db: dryRunDB(t), attrs: scimUserAttrs}
+
+	tests := []struct {
+		filter string
+		sql    string
+		args   []interface{}
+	}{
+		{`userName eq "JDoe@Example.gov"`, `LOWER(users.email) = ?`, []interface{}{"jdoe@example.gov"}},
+		{`USERNAME ne "a"`, `LOWER(users.email) <> ?`, []interface{}{"a"}},
+		{`userName co "50%_off"`, `LOWER(users.email) LIKE ? ESCAPE '\'`, []interface{}{`%50\%\_off%`}},
+		{`name.familyName sw "O'B"`, `LOWER(users.last_name) LIKE ? ESCAPE '\'`, []interface{}{`o'b%`}},
+		{`emails ew "@dept.gov"`, `LOWER(users.email) LIKE ? ESCAPE '\'`, []interface{}{`%@dept.gov`}},
+		{`emails[value eq "x@dept.gov"]`, `LOWER(users.email) = ?`, []interface{}{"x@dept.gov"}},
+		{`name.givenName pr`, `(users.first_name IS NOT NULL AND users.first_name <> '')`, nil},
+		{`active eq true`, `users.is_active = ?`, []interface{}{true}},
+		{`id eq "12"`, `users.id = ?`, []interface{}{uint(12)}},
+		{`id gt 12`, `users.id > ?`, []interface{}{uint(12)}},
+		{`id eq "abc"`, `1 = 0`, nil},
+		{`id ne "abc"`, `1 = 1`, nil},
+		{
+			`meta.lastModified ge "2026-10-18T09:30:00Z"`,
+			`users.updated_at >= ?`,
+			[]interface{}{time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)},
+		},
+		{
+			`urn:ietf:params:scim:schemas:extension:trueforce:2.0:User:badgeNumber eq "B-7"`,
+			`LOWER(users.badge_number) = ?`,
+			[]interface{}{"b-7"},
+		},
+		{
+			`active eq true and not (userName sw "svc")`,
+			`(users.is_active = ?) AND (NOT (LOWER(users.email) LIKE ? ESCAPE '\'))`,
+			[]interface{}{true, "svc%"},
+		},
+		{
+			`userName eq "a" or userName eq "b" and active eq false`,
+			`(LOWER(users.email) = ?) OR ((LOWER(users.email) = ?) AND (users.is_active = ?))`,
+			[]interface{}{"a", "b", false},
+		},
+		{
+			`(userName eq "a" or userName eq "b") and active eq false`,
+			`((LOWER(users.email) = ?) OR (LOWER(users.email) = ?)) AND (users.is_active = ?)`,
+			[]interface{}{"a", "b", false},
+		},
+	}
+	for _, tt := range tests {
+		filter, err := scim.ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%s): %v", tt.filter, err)
+		}
+		sql, args, err := c.compile(filter, nil)
+		if err != nil {
+			t.Errorf("compile(%s): %v", tt.filter, err)
+			continue
+		}
+		if sql != tt.sql || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("compile(%s)\n got %s %#v\nwant %s %#v", tt.filter, sql, args, tt.sql, tt.args)
+		}
+	}
+}
+
+func TestScimCompileRejects(t *testing.T) {
+	c := &scimCompiler{ctx: context.Background(), db: dryRunDB(t), attrs: scimUserAttrs}
+
+	filters := []string{
+		`title eq "Detective"`,
+		`password eq "secret"`,
+		`name.middleName eq "Q"`,
+		`members[value eq "7"]`,
+		`userName eq "a" or title eq "b"`,
+		`not (title pr)`,
+		`emails[type eq "work"]`,
+		`emails[value[type eq "work"]]`,
+		`userName eq 5`,
+		`userName eq true`,
+		`active eq "yes"`,
+		`active gt false`,
+		`active co "t"`,
+		`id co "1"`,
+		`meta.created gt "yesterday"`,
+		`meta.created sw "2026"`,
+	}
+	for _, filter := range filters {
+		parsed, err := scim.ParseFilter(filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%s): %v", filter, err)
+		}
+		_, _, err = c.compile(parsed, nil)
+		var scimErr *scim.Error
+		if !errors.As(err, &scimErr) || scimErr.ScimType != scim.ErrTypeInvalidFilter {
+			t.Errorf("compile(%s): got %v, want an %s error", filter, err, scim.ErrTypeInvalidFilter)
+		}
+	}
+}
+
+func TestScimCompileGroupMembers(t *testing.T) {
+	db := dryRunDB(t)
+	c := &scimCompiler{ctx: context.Background(), db: db, attrs: scimGroupAttrs}
+
+	tests := []struct {
+		filter string
+		want   string
+		vars   []interface{}
+	}{
+		{
+			`members[value eq "7"]`,
+			`roles.id IN (SELECT "role_id" FROM "user_roles" WHERE user_id = $1 AND "user_roles"."deleted_at" IS NULL)`,
+			[]interface{}{uint(7)},
+		},
+		{
+			`displayName eq "Detectives" and members pr`,
+			`((LOWER(roles.name) = $1) AND (roles.id IN (SELECT "role_id" FROM "user_roles" WHERE "user_roles"."deleted_at" IS NULL)))`,
+			[]interface{}{"detectives"},
+		},
+		{`members eq "x"`, `1 = 0`, []interface{}{}},
+	}
+	for _, tt := range tests {
+		filter, err := scim.ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%s): %v", tt.filter, err)
+		}
+		sql, args, err := c.compile(filter, nil)
+		if err != nil {
+			t.Errorf("compile(%s): %v", tt.filter, err)
+			continue
+		}
+		stmt := db.Model(&models.Role{}).Where(sql, args...).Find(&[]models.Role{}).Statement
+
+		want := `SELECT * FROM "roles" WHERE ` + tt.want + ` AND "roles"."deleted_at" IS NULL`
+		if got := stmt.SQL.String(); got != want {
+			t.Errorf("compile(%s)\n got %s\nwant %s", tt.filter, got, want)
+		}
+		if !reflect.DeepEqual(stmt.Vars, tt.vars) {
+			t.Errorf("compile(%s) binds %#v, want %#v", tt.filter, stmt.Vars, tt.vars)
+		}
+	}
+
+	filter, _ := scim.ParseFilter(`members ne "7"`)
+	if _, _, err := c.compile(filter, nil); err == nil {
+		t.Error(`compile(members ne "7") succeeded, want an error`)
+	}
+}
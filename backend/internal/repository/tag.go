@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(ctx context.Context, tag *models.Tag) error
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id uint) error
+	FindAll(ctx context.Context) ([]models.Tag, error)
+	FindByID(ctx context.Context, id uint) (*models.Tag, error)
+	ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error)
+
+	CreateCaseTag(ctx context.Context, caseTag *models.CaseTag) error
+	DeleteCaseTag(ctx context.Context, id uint) error
+	DeleteCaseTagsByTag(ctx context.Context, tagID uint) error
+	FindCaseTag(ctx context.Context, caseID, tagID uint) (*models.CaseTag, error)
+	FindCaseTagsByCase(ctx context.Context, caseID uint) ([]models.CaseTag, error)
+	MergeCaseTags(ctx context.Context, sourceID, targetID uint) (int64, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	return getDB(ctx, r.db).Omit("CaseTags").Create(tag).Error
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	return getDB(ctx, r.db).Model(tag).
+		Select("name", "color").
+		Updates(tag).Error
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.Tag{}, id).Error
+}
+
+func (r *tagRepository) FindAll(ctx context.Context) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := getDB(ctx, r.db).Order("name, id").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := getDB(ctx, r.db).First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ExistsByName reports whether another live tag than excludeID is called
+// name, ignoring case.
+func (r *tagRepository) ExistsByName(ctx context.Context, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.Tag{}).
+		Where("LOWER(name) = LOWER(?)", name).
+		Where("id <> ?", excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *tagRepository) CreateCaseTag(ctx context.Context, caseTag *models.CaseTag) error {
+	return getDB(ctx, r.db).Omit("Case", "Tag", "CreatedBy").Create(caseTag).Error
+}
+
+// DeleteCaseTag detaches the tag from the case. The soft-deleted row stays
+// as history.
+func (r *tagRepository) DeleteCaseTag(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.CaseTag{}, id).Error
+}
+
+// DeleteCaseTagsByTag detaches the tag from every case.
+func (r *tagRepository) DeleteCaseTagsByTag(ctx context.Context, tagID uint) error {
+	return getDB(ctx, r.db).Where("tag_id = ?", tagID).Delete(&models.CaseTag{}).Error
+}
+
+// FindCaseTag returns the tag's current attachment to the case, with the
+// tag loaded.
+func (r *tagRepository) FindCaseTag(ctx context.Context, caseID, tagID uint) (*models.CaseTag, error) {
+	var caseTag models.CaseTag
+	err := getDB(ctx, r.db).
+		Preload("Tag").
+		Where("case_id = ? AND tag_id = ?", caseID, tagID).
+		First(&caseTag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &caseTag, nil
+}
+
+// FindCaseTagsByCase lists the tags attached to the case, in the order they
+// were attached.
+func (r *tagRepository) FindCaseTagsByCase(ctx context.Context, caseID uint) ([]models.CaseTag, error) {
+	var caseTags []models.CaseTag
+	err := getDB(ctx, r.db).
+		Joins("Tag").
+		Where("case_tags.case_id = ?", caseID).
+		Order("case_tags.id").
+		Find(&caseTags).Error
+	if err != nil {
+		return nil, err
+	}
+	return caseTags, nil
+}
+
+// MergeCaseTags re-points the source tag's attachments to the target tag
+// and returns how many were moved. Cases that already have the target tag
+// lose the source attachment instead, so no case gets the tag twice.
+func (r *tagRepository) MergeCaseTags(ctx context.Context, sourceID, targetID uint) (int64, error) {
+	db := getDB(ctx, r.db)
+
+	tagged := db.Model(&models.CaseTag{}).
+		Select("case_id").
+		Where("tag_id = ?", targetID)
+	err := db.
+		Where("tag_id = ? AND case_id IN (?)", sourceID, tagged).
+		Delete(&models.CaseTag{}).Error
+	if err != nil {
+		return 0, err
+	}
+
+	res := db.Model(&models.CaseTag{}).
+		Where("tag_id = ?", sourceID).
+		Update("tag_id", targetID)
+	return res.RowsAffected, res.Error
+}
@@ -24,8 +24,10 @@ func NewTransactionManager(db *gorm.DB) TransactionManager {
 	return &GormTransactionManager{db: db}
 }
 
+// WithTransaction runs fn in a transaction. Called inside another
+// transaction it nests through a savepoint instead of opening a second one.
 func (tm *GormTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	return tm.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return getDB(ctx, tm.db).Transaction(func(tx *gorm.DB) error {
 		// Create a new context with tx instead of db
 		txCtx := context.WithValue(ctx, txKey, tx)
 		return fn(txCtx)
@@ -4,12 +4,49 @@ import (
 	"backend/internal/model"
 	"context"
 	"errors"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
+	UpdateLastLogin(ctx context.Context, id uint, at time.Time) error
+	UpdatePasswordHash(ctx context.Context, id uint, hash string) error
+	List(ctx context.Context, query UserListQuery) ([]models.User, error)
+	Count(ctx context.Context, query UserListQuery) (int64, error)
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByIDWithRoles(ctx context.Context, id uint) (*models.User, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByGoogleID(ctx context.Context, googleID string) (*models.User, error)
+	UpdateGoogleID(ctx context.Context, id uint, googleID string) error
+	FindByIDForUpdate(ctx context.Context, id uint) (*models.User, error)
+	UpdateMFA(ctx context.Context, user *models.User) error
+	FindByIDWithDetails(ctx context.Context, id uint) (*models.User, error)
+	FindByIDUnscoped(ctx context.Context, id uint) (*models.User, error)
+	ExistsByEmail(ctx context.Context, email string, excludeID uint) (bool, error)
+	ExistsByBadgeNumber(ctx context.Context, badgeNumber string, excludeID uint) (bool, error)
+	UpdateProfile(ctx context.Context, user *models.User) error
+	UpdateActive(ctx context.Context, id uint, active bool) error
+	UpdateDepartment(ctx context.Context, id uint, departmentID *uint) error
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+}
+
+// UserListQuery filters, orders and bounds a directory listing. SortColumn
+// must be a users column chosen by the caller, never raw client input.
+type UserListQuery struct {
+	DepartmentID *uint
+	RoleID       *uint
+	IsActive     *bool
+	Search       string
+	SortColumn   string
+	SortDesc     bool
+	After        *KeysetCursor
+	Offset       int
+	Limit        int
 }
 
 type userRepository struct {
@@ -24,9 +61,121 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	return getDB(ctx, r.db).Create(user).Error
 }
 
+func (r *userRepository) UpdateLastLogin(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("last_login", at).Error
+}
+
+func (r *userRepository) UpdatePasswordHash(ctx context.Context, id uint, hash string) error {
+	return getDB(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("password_hash", hash).Error
+}
+
+func (r *userRepository) UpdateGoogleID(ctx context.Context, id uint, googleID string) error {
+	return getDB(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("google_id", googleID).Error
+}
+
+// userListColumns are the only columns the directory loads, so secrets such
+// as the password hash never leave the database.
+var userListColumns = []string{
+	"id", "email", "first_name", "last_name", "badge_number",
+	"department_id", "is_active", "last_login", "created_at",
+}
+
+// List returns one page of users. Ordering is by SortColumn and then id,
+// in the same direction, which keeps keyset pages stable.
+func (r *userRepository) List(ctx context.Context, query UserListQuery) ([]models.User, error) {
+	db := r.filter(ctx, getDB(ctx, r.db).Model(&models.User{}), query).
+		Select(userListColumns).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: query.SortColumn}, Desc: query.SortDesc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: query.SortDesc})
+
+	if query.After != nil {
+		db = db.Where(keysetAfter(query.SortColumn, query.SortDesc, query.After))
+	}
+	if query.Offset > 0 {
+		db = db.Offset(query.Offset)
+	}
+
+	var users []models.User
+	if err := db.Limit(query.Limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Count returns how many users match the filters of query.
+func (r *userRepository) Count(ctx context.Context, query UserListQuery) (int64, error) {
+	var count int64
+	err := r.filter(ctx, getDB(ctx, r.db).Model(&models.User{}), query).Count(&count).Error
+	return count, err
+}
+
+func (r *userRepository) filter(ctx context.Context, db *gorm.DB, query UserListQuery) *gorm.DB {
+	if query.DepartmentID != nil {
+		db = db.Where("department_id = ?", *query.DepartmentID)
+	}
+	if query.RoleID != nil {
+		db = db.Where("id IN (?)", getDB(ctx, r.db).Model(&models.UserRole{}).
+			Select("user_id").
+			Where("role_id = ?", *query.RoleID))
+	}
+	if query.IsActive != nil {
+		db = db.Where("is_active = ?", *query.IsActive)
+	}
+
+	// Every search term must match the name, email or badge number.
+	for _, term := range strings.Fields(query.Search) {
+		pattern := "%" + escapeLike(strings.ToLower(term)) + "%"
+		db = db.Where(
+			`LOWER(first_name) LIKE ? ESCAPE '\' OR LOWER(last_name) LIKE ? ESCAPE '\' OR `+
+				`LOWER(email) LIKE ? ESCAPE '\' OR LOWER(badge_number) LIKE ? ESCAPE '\'`,
+			pattern, pattern, pattern, pattern,
+		)
+	}
+	return db
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := getDB(ctx, r.db).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByIDWithRoles loads the user with its assigned roles.
+func (r *userRepository) FindByIDWithRoles(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := getDB(ctx, r.db).
+		Preload("UserRoles.Role").
+		First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmail matches the email case-insensitively, since identity
+// providers and users do not keep the case it was stored with.
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	if err := getDB(ctx, r.db).Where("email = ?", email).First(&user).Error; err != nil {
+	if err := getDB(ctx, r.db).Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByGoogleID(ctx context.Context, googleID string) (*models.User, error) {
+	var user models.User
+	if err := getDB(ctx, r.db).Where("google_id = ?", googleID).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -34,3 +183,102 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models
 	}
 	return &user, nil
 }
+
+// FindByIDForUpdate locks the user row; callers must run inside a
+// transaction.
+func (r *userRepository) FindByIDForUpdate(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := getDB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateMFA persists the user's MFA columns.
+func (r *userRepository) UpdateMFA(ctx context.Context, user *models.User) error {
+	return getDB(ctx, r.db).Model(&models.User{}).
+		Where("id = ?", user.ID).
+		Updates(map[string]interface{}{
+			"mfa_enabled":        user.MFAEnabled,
+			"mfa_enabled_at":     user.MFAEnabledAt,
+			"mfa_secret":         user.MFASecret,
+			"mfa_last_used_step": user.MFALastUsedStep,
+		}).Error
+}
+
+// FindByIDWithDetails loads the user with its department and assigned roles.
+func (r *userRepository) FindByIDWithDetails(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := getDB(ctx, r.db).
+		Preload("Department").
+		Preload("UserRoles.Role").
+		First(&user, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByIDUnscoped also returns soft-deleted users.
+func (r *userRepository) FindByIDUnscoped(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := getDB(ctx, r.db).Unscoped().First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ExistsByEmail reports whether a user other than excludeID, deleted or not,
+// has the email in any case. Soft-deleted users keep their unique values for
+// restore.
+func (r *userRepository) ExistsByEmail(ctx context.Context, email string, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Unscoped().Model(&models.User{}).
+		Where("LOWER(email) = LOWER(?) AND id <> ?", email, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ExistsByBadgeNumber reports whether a user other than excludeID, deleted or
+// not, has the badge number.
+func (r *userRepository) ExistsByBadgeNumber(ctx context.Context, badgeNumber string, excludeID uint) (bool, error) {
+	var count int64
+	err := getDB(ctx, r.db).Unscoped().Model(&models.User{}).
+		Where("badge_number = ? AND id <> ?", badgeNumber, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *models.User) error {
+	return getDB(ctx, r.db).Model(user).
+		Select("first_name", "last_name", "email", "phone_number", "badge_number", "profile_image_url").
+		Updates(user).Error
+}
+
+func (r *userRepository) UpdateActive(ctx context.Context, id uint, active bool) error {
+	return getDB(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("is_active", active).Error
+}
+
+func (r *userRepository) UpdateDepartment(ctx context.Context, id uint, departmentID *uint) error {
+	return getDB(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("department_id", departmentID).Error
+}
+
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Delete(&models.User{}, id).Error
+}
+
+func (r *userRepository) Restore(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Unscoped().Model(&models.User{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+type UserRoleRepository interface {
+	Create(ctx context.Context, userRole *models.UserRole) error
+	Delete(ctx context.Context, userRole *models.UserRole) error
+	FindByUserAndRole(ctx context.Context, userID, roleID uint) (*models.UserRole, error)
+	FindByUserID(ctx context.Context, userID uint) ([]models.UserRole, error)
+	FindRoleIDsByUserID(ctx context.Context, userID uint) ([]uint, error)
+	CountByRoleID(ctx context.Context, roleID uint) (int64, error)
+}
+
+type userRoleRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRoleRepository(db *gorm.DB) UserRoleRepository {
+	return &userRoleRepository{db: db}
+}
+
+func (r *userRoleRepository) Create(ctx context.Context, userRole *models.UserRole) error {
+	return getDB(ctx, r.db).Create(userRole).Error
+}
+
+func (r *userRoleRepository) Delete(ctx context.Context, userRole *models.UserRole) error {
+	return getDB(ctx, r.db).Delete(userRole).Error
+}
+
+func (r *userRoleRepository) FindByUserAndRole(ctx context.Context, userID, roleID uint) (*models.UserRole, error) {
+	var userRole models.UserRole
+	err := getDB(ctx, r.db).
+		Where("user_id = ? AND role_id = ?", userID, roleID).
+		First(&userRole).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &userRole, nil
+}
+
+func (r *userRoleRepository) FindByUserID(ctx context.Context, userID uint) ([]models.UserRole, error) {
+	var userRoles []models.UserRole
+	err := getDB(ctx, r.db).
+		Preload("Role").
+		Preload("AssignedBy").
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&userRoles).Error
+	if err != nil {
+		return nil, err
+	}
+	return userRoles, nil
+}
+
+func (r *userRoleRepository) FindRoleIDsByUserID(ctx context.Context, userID uint) ([]uint, error) {
+	var roleIDs []uint
+	err := getDB(ctx, r.db).Model(&models.UserRole{}).
+		Where("user_id = ?", userID).
+		Pluck("role_id", &roleIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return roleIDs, nil
+}
+
+func (r *userRoleRepository) CountByRoleID(ctx context.Context, roleID uint) (int64, error) {
+	var count int64
+	err := getDB(ctx, r.db).Model(&models.UserRole{}).
+		Where("role_id = ?", roleID).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"backend/internal/model"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserSessionRepository interface {
+	Create(ctx context.Context, session *models.UserSession) error
+	FindByID(ctx context.Context, id uint) (*models.UserSession, error)
+	FindByRefreshTokenID(ctx context.Context, refreshTokenID uint) (*models.UserSession, error)
+	FindActiveByUserID(ctx context.Context, userID uint) ([]models.UserSession, error)
+	UpdateRefreshToken(ctx context.Context, id, refreshTokenID uint, at time.Time) error
+	Touch(ctx context.Context, id uint, at time.Time) error
+	Deactivate(ctx context.Context, id uint) error
+	DeactivateByRefreshTokenFamily(ctx context.Context, familyID string) error
+	DeactivateByUserID(ctx context.Context, userID uint) error
+}
+
+type userSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserSessionRepository(db *gorm.DB) UserSessionRepository {
+	return &userSessionRepository{db: db}
+}
+
+func (r *userSessionRepository) Create(ctx context.Context, session *models.UserSession) error {
+	return getDB(ctx, r.db).Omit("User", "RefreshToken").Create(session).Error
+}
+
+func (r *userSessionRepository) FindByID(ctx context.Context, id uint) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := getDB(ctx, r.db).First(&session, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *userSessionRepository) FindByRefreshTokenID(ctx context.Context, refreshTokenID uint) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := getDB(ctx, r.db).Where("refresh_token_id = ?", refreshTokenID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUserID returns the user's active sessions, most recently used
+// first.
+func (r *userSessionRepository) FindActiveByUserID(ctx context.Context, userID uint) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := getDB(ctx, r.db).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("last_activity DESC, id DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// UpdateRefreshToken points the session at the successor of its rotated
+// refresh token.
+func (r *userSessionRepository) UpdateRefreshToken(ctx context.Context, id, refreshTokenID uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.UserSession{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"refresh_token_id": refreshTokenID,
+			"last_activity":    at,
+		}).Error
+}
+
+func (r *userSessionRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	return getDB(ctx, r.db).Model(&models.UserSession{}).
+		Where("id = ?", id).
+		UpdateColumn("last_activity", at).Error
+}
+
+func (r *userSessionRepository) Deactivate(ctx context.Context, id uint) error {
+	return getDB(ctx, r.db).Model(&models.UserSession{}).
+		Where("id = ?", id).
+		Update("is_active", false).Error
+}
+
+// DeactivateByRefreshTokenFamily ends the session whose refresh token belongs
+// to the family.
+func (r *userSessionRepository) DeactivateByRefreshTokenFamily(ctx context.Context, familyID string) error {
+	return getDB(ctx, r.db).Model(&models.UserSession{}).
+		Where("is_active = ? AND refresh_token_id IN (?)", true,
+			getDB(ctx, r.db).Model(&models.RefreshToken{}).Select("id").Where("family_id = ?", familyID),
+		).
+		Update("is_active", false).Error
+}
+
+func (r *userSessionRepository) DeactivateByUserID(ctx context.Context, userID uint) error {
+	return getDB(ctx, r.db).Model(&models.UserSession{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Update("is_active", false).Error
+}
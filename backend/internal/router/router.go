@@ -3,11 +3,15 @@ package router
 import (
 	"backend/config"
 	"backend/internal/handler"
+	"backend/internal/integration/oidc"
+	"backend/internal/integration/smtp"
 	"backend/internal/middleware"
 	"backend/internal/repository"
 	v1 "backend/internal/router/v1"
+	"backend/internal/security"
 	"backend/internal/service"
-	"backend/internal/integration/smtp"
+	"log"
+	"strings"
 
 	"gorm.io/gorm"
 
@@ -16,8 +20,14 @@ import (
 
 func SetupRouter(cfg config.AppConfig, db *gorm.DB) *gin.Engine {
 	r := gin.Default()
+	// Without trusted proxies the forwarding headers are ignored, so clients
+	// cannot choose the IP address that sign-in throttling sees.
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("TRUSTED_PROXIES: %v", err)
+	}
 	r.Use(middleware.CORS())
 	r.Use(middleware.Logger())
+	r.Use(middleware.ClientInfo())
 
 	// Setup email sender
 	mailer := smtp.NewMailer(smtp.SMTPConfig{
@@ -28,18 +38,150 @@ func SetupRouter(cfg config.AppConfig, db *gorm.DB) *gin.Engine {
 		From:     cfg.SMTPFrom,
 	})
 
+	// Setup token signing
+	jwtSigner := security.NewJWTSigner(cfg.JWTSecret, cfg.JWTIssuer)
+
+	// Setup password hashing
+	passwordHasher := security.NewPasswordHasher(security.PasswordConfig{
+		Algorithm:         cfg.PasswordHashAlgorithm,
+		Argon2Memory:      cfg.PasswordArgon2Memory,
+		Argon2Iterations:  cfg.PasswordArgon2Iterations,
+		Argon2Parallelism: cfg.PasswordArgon2Parallelism,
+		BcryptCost:        cfg.PasswordBcryptCost,
+	})
+
+	// Setup Google sign-in
+	var googleProvider oidc.Provider
+	if cfg.GoogleClientID != "" {
+		googleProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.GoogleOIDCIssuer,
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  cfg.GoogleRedirectURL,
+		})
+	}
+
+	// Setup MFA secret encryption
+	mfaSecretBox := security.NewSecretBox(cfg.MFAEncryptionKey)
+
 	// Init layers
-	userRepo := repository.NewUserRepo(db)
-	userService := service.NewUserService(userRepo, mailer)
+	txManager := repository.NewTransactionManager(db)
+	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	userRoleRepo := repository.NewUserRoleRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	permissionRepo := repository.NewPermissionRepository(db)
+	roleManagementRepo := repository.NewRoleManagementRepository(db)
+	roleHistoryRepo := repository.NewRoleChangeHistoryRepository(db)
+	resetTokenRepo := repository.NewPasswordResetTokenRepository(db)
+	sessionRepo := repository.NewUserSessionRepository(db)
+	oidcStateRepo := repository.NewOIDCLoginStateRepository(db)
+	departmentRepo := repository.NewDepartmentRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	scimRepo := repository.NewScimRepository(db)
+	caseRepo := repository.NewCaseRepository(db)
+	caseNumberFormatRepo := repository.NewCaseNumberFormatRepository(db)
+	caseWorkflowRepo := repository.NewCaseWorkflowRepository(db)
+	caseOfficerRepo := repository.NewCaseOfficerRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+
+	authorizer := service.NewAuthorizer(userRoleRepo, roleRepo, permissionRepo, cfg.PermissionCacheTTL)
+
+	sessionService := service.NewSessionService(sessionRepo, refreshTokenRepo, userRepo, txManager, service.SessionConfig{
+		ActivityInterval: cfg.SessionActivityInterval,
+	})
+	tokenService := service.NewTokenService(jwtSigner, refreshTokenRepo, sessionRepo, userRepo, txManager, service.TokenConfig{
+		AccessTokenTTL:  cfg.AccessTokenTTL,
+		RefreshTokenTTL: cfg.RefreshTokenTTL,
+	})
+	mfaService := service.NewMFAService(userRepo, userRoleRepo, mfaRepo, txManager, mfaSecretBox, service.MFAConfig{
+		Issuer:       cfg.MFAIssuer,
+		ChallengeTTL: cfg.MFAChallengeTTL,
+	})
+	loginThrottleService := service.NewLoginThrottleService(loginThrottleRepo, auditRepo, userRepo, txManager, mailer, service.LoginThrottleConfig{
+		FreeAttempts:       cfg.LoginFreeAttempts,
+		DelayBase:          cfg.LoginDelayBase,
+		DelayMax:           cfg.LoginDelayMax,
+		LockoutThreshold:   cfg.LoginLockoutThreshold,
+		IPLockoutThreshold: cfg.LoginIPLockoutThreshold,
+		LockoutDuration:    cfg.LoginLockoutDuration,
+		FailureWindow:      cfg.LoginFailureWindow,
+	})
+	authService := service.NewAuthService(userRepo, tokenService, authorizer, passwordHasher, mfaService, sessionService, loginThrottleService)
+	googleAuthService := service.NewGoogleAuthService(googleProvider, oidcStateRepo, userRepo, departmentRepo, txManager, authService)
+	passwordResetService := service.NewPasswordResetService(userRepo, resetTokenRepo, refreshTokenRepo, sessionRepo, txManager, passwordHasher, mailer, service.PasswordResetConfig{
+		TokenTTL: cfg.PasswordResetTokenTTL,
+		ResetURL: strings.TrimRight(cfg.FrontendURL, "/") + "/reset-password",
+	})
+	userService := service.NewUserService(userRepo, userRoleRepo, roleRepo, departmentRepo, auditRepo, txManager, sessionService, mailer, passwordHasher)
+	onboardingService := service.NewOnboardingService(userRepo, userRoleRepo, roleRepo, roleManagementRepo, departmentRepo, resetTokenRepo, auditRepo, txManager, mailer, service.OnboardingConfig{
+		InvitationTTL:  cfg.InvitationTokenTTL,
+		SetPasswordURL: strings.TrimRight(cfg.FrontendURL, "/") + "/set-password",
+	})
+	departmentService := service.NewDepartmentService(departmentRepo, userRepo, auditRepo, txManager, userService)
+	roleService := service.NewRoleService(roleRepo, permissionRepo, userRepo, userRoleRepo, roleManagementRepo, roleHistoryRepo, txManager, authorizer)
+	caseNumberService := service.NewCaseNumberService(caseNumberFormatRepo, caseRepo, departmentRepo, auditRepo, txManager)
+	caseService := service.NewCaseService(caseRepo, caseWorkflowRepo, auditRepo, txManager, caseNumberService)
+	caseWorkflowService := service.NewCaseWorkflowService(caseWorkflowRepo, permissionRepo, auditRepo, txManager)
+	caseOfficerService := service.NewCaseOfficerService(caseRepo, caseOfficerRepo, userRepo, auditRepo, txManager, mailer, service.CaseOfficerConfig{
+		CaseURL: strings.TrimRight(cfg.FrontendURL, "/") + "/cases",
+	})
+	tagService := service.NewTagService(tagRepo, caseRepo, auditRepo, txManager)
+	searchService := service.NewSearchService(caseRepo)
+	caseStatsService := service.NewCaseStatsService(caseRepo, caseWorkflowRepo, tagRepo, cfg.CaseStatsCacheTTL)
+	scimService := service.NewScimService(scimRepo, userRepo, userRoleRepo, roleRepo, departmentRepo, auditRepo, txManager, sessionService, authorizer)
+
+	authHandler := handler.NewAuthHandler(authService, passwordResetService)
+	googleAuthHandler := handler.NewGoogleAuthHandler(googleAuthService, strings.HasPrefix(cfg.GoogleRedirectURL, "https://"))
+	mfaHandler := handler.NewMFAHandler(mfaService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	loginThrottleHandler := handler.NewLoginThrottleHandler(loginThrottleService)
 	userHandler := handler.NewUserHandler(userService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	departmentHandler := handler.NewDepartmentHandler(departmentService)
+	onboardingHandler := handler.NewOnboardingHandler(onboardingService)
+	caseHandler := handler.NewCaseHandler(caseService)
+	caseNumberHandler := handler.NewCaseNumberHandler(caseNumberService)
+	caseWorkflowHandler := handler.NewCaseWorkflowHandler(caseWorkflowService)
+	caseOfficerHandler := handler.NewCaseOfficerHandler(caseOfficerService)
+	tagHandler := handler.NewTagHandler(tagService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	caseStatsHandler := handler.NewCaseStatsHandler(caseStatsService)
+	scimHandler := handler.NewSCIMHandler(scimService)
 
 	// Group: /api
 	api := r.Group("/api")
 
+	authenticate := middleware.Authenticate(authService)
+
 	// Setup v1 routes
 	v1Router := api.Group("/v1")
-	v1.SetupAuthRoutes(v1Router)
-	v1.SetupUserRoutes(v1Router, userHandler)
+	v1.SetupAuthRoutes(v1Router, authHandler, authenticate)
+	v1.SetupGoogleAuthRoutes(v1Router, googleAuthHandler)
+	v1.SetupMFARoutes(v1Router, authHandler, mfaHandler, authenticate)
+
+	// Everything below requires a valid access token
+	protected := v1Router.Group("", authenticate)
+	v1.SetupUserRoutes(protected, userHandler)
+	v1.SetupOnboardingRoutes(protected, onboardingHandler)
+	v1.SetupRoleRoutes(protected, roleHandler)
+	v1.SetupDepartmentRoutes(protected, departmentHandler)
+	v1.SetupCaseRoutes(protected, caseHandler)
+	v1.SetupCaseNumberRoutes(protected, caseNumberHandler)
+	v1.SetupCaseWorkflowRoutes(protected, caseWorkflowHandler)
+	v1.SetupCaseOfficerRoutes(protected, caseOfficerHandler)
+	v1.SetupTagRoutes(protected, tagHandler)
+	v1.SetupSearchRoutes(protected, searchHandler)
+	v1.SetupCaseStatsRoutes(protected, caseStatsHandler)
+	v1.SetupSessionRoutes(protected, sessionHandler)
+	v1.SetupLoginThrottleRoutes(protected, loginThrottleHandler)
+
+	// Group: /scim/v2, only when a provisioning token is configured
+	if cfg.SCIMBearerToken != "" {
+		setupSCIMRoutes(r, cfg.SCIMBearerToken, scimHandler)
+	}
 
 	return r
 }
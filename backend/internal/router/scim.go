@@ -0,0 +1,31 @@
+package router
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupSCIMRoutes registers the SCIM 2.0 provisioning API, authenticated
+// with the shared SCIM bearer token instead of user access tokens.
+func setupSCIMRoutes(r *gin.Engine, token string, scimHandler *handler.SCIMHandler) {
+	router := r.Group("/scim/v2", middleware.SCIMAuth(token))
+
+	router.GET("/ServiceProviderConfig", scimHandler.ServiceProviderConfig)
+	router.GET("/ResourceTypes", scimHandler.ResourceTypes)
+
+	router.GET("/Users", scimHandler.ListUsers)
+	router.POST("/Users", scimHandler.CreateUser)
+	router.GET("/Users/:id", scimHandler.GetUser)
+	router.PUT("/Users/:id", scimHandler.ReplaceUser)
+	router.PATCH("/Users/:id", scimHandler.PatchUser)
+	router.DELETE("/Users/:id", scimHandler.DeleteUser)
+
+	router.GET("/Groups", scimHandler.ListGroups)
+	router.POST("/Groups", scimHandler.CreateGroup)
+	router.GET("/Groups/:id", scimHandler.GetGroup)
+	router.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+	router.PATCH("/Groups/:id", scimHandler.PatchGroup)
+	router.DELETE("/Groups/:id", scimHandler.DeleteGroup)
+}
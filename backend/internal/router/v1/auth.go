@@ -6,9 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupAuthRoutes(router *gin.RouterGroup) {
+func SetupAuthRoutes(router *gin.RouterGroup, authHandler *handler.AuthHandler, authenticate gin.HandlerFunc) {
 	auth := router.Group("/auth")
 	{
-		auth.POST("/login", handler.Login)
+		auth.POST("/login", authHandler.Login)
+		auth.POST("/refresh", authHandler.Refresh)
+		auth.POST("/logout", authHandler.Logout)
+		auth.POST("/forgot-password", authHandler.ForgotPassword)
+		auth.POST("/reset-password", authHandler.ResetPassword)
+		auth.GET("/me", authenticate, authHandler.Me)
 	}
 }
@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCaseRoutes registers all case-related routes. Which cases a caller
+// can reach is decided by the case repository, not here.
+func SetupCaseRoutes(router *gin.RouterGroup, caseHandler *handler.CaseHandler) {
+	cases := router.Group("/cases")
+	{
+		cases.GET("", middleware.RequirePermission("case.view"), caseHandler.ListCases)
+		cases.POST("", middleware.RequirePermission("case.create"), caseHandler.CreateCase)
+		cases.GET("/:id", middleware.RequirePermission("case.view"), caseHandler.GetCase)
+		cases.PATCH("/:id", middleware.RequirePermission("case.edit"), caseHandler.UpdateCase)
+		cases.DELETE("/:id", middleware.RequirePermission("case.delete"), caseHandler.DeleteCase)
+		cases.GET("/:id/transitions", middleware.RequirePermission("case.view"), caseHandler.AvailableTransitions)
+		cases.POST("/:id/status", middleware.RequirePermission("case.edit"), caseHandler.ChangeStatus)
+	}
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCaseNumberRoutes registers the case number format routes. Anyone who
+// can create cases may list the formats to pick a case type.
+func SetupCaseNumberRoutes(router *gin.RouterGroup, caseNumberHandler *handler.CaseNumberHandler) {
+	formats := router.Group("/case-number-formats")
+	{
+		formats.GET("", middleware.RequirePermission("case.create"), caseNumberHandler.ListFormats)
+		formats.POST("", middleware.RequirePermission("system.settings"), caseNumberHandler.CreateFormat)
+		formats.PUT("/:id", middleware.RequirePermission("system.settings"), caseNumberHandler.UpdateFormat)
+		formats.DELETE("/:id", middleware.RequirePermission("system.settings"), caseNumberHandler.DeleteFormat)
+	}
+}
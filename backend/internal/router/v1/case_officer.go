@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCaseOfficerRoutes registers the routes that assign officers to
+// cases.
+func SetupCaseOfficerRoutes(router *gin.RouterGroup, caseOfficerHandler *handler.CaseOfficerHandler) {
+	officers := router.Group("/cases/:id/officers")
+	{
+		officers.GET("", middleware.RequirePermission("case.view"), caseOfficerHandler.ListAssignments)
+		officers.POST("", middleware.RequirePermission("case.assign"), caseOfficerHandler.AssignOfficer)
+		officers.PATCH("/:officerId", middleware.RequirePermission("case.assign"), caseOfficerHandler.UpdateAssignment)
+		officers.DELETE("/:officerId", middleware.RequirePermission("case.assign"), caseOfficerHandler.UnassignOfficer)
+	}
+}
@@ -0,0 +1,14 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCaseStatsRoutes registers the dashboard statistics route. The
+// numbers cover only the cases the caller may see.
+func SetupCaseStatsRoutes(router *gin.RouterGroup, caseStatsHandler *handler.CaseStatsHandler) {
+	router.GET("/cases/stats", middleware.RequirePermission("case.view"), caseStatsHandler.GetStats)
+}
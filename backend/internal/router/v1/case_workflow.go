@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupCaseWorkflowRoutes registers the routes that configure the case
+// status workflow. Anyone who can view cases may read it.
+func SetupCaseWorkflowRoutes(router *gin.RouterGroup, workflowHandler *handler.CaseWorkflowHandler) {
+	workflow := router.Group("/case-workflow")
+	{
+		workflow.GET("", middleware.RequirePermission("case.view"), workflowHandler.GetWorkflow)
+
+		workflow.POST("/statuses", middleware.RequirePermission("system.settings"), workflowHandler.CreateStatus)
+		workflow.PUT("/statuses/:id", middleware.RequirePermission("system.settings"), workflowHandler.UpdateStatus)
+		workflow.DELETE("/statuses/:id", middleware.RequirePermission("system.settings"), workflowHandler.DeleteStatus)
+
+		workflow.POST("/transitions", middleware.RequirePermission("system.settings"), workflowHandler.CreateTransition)
+		workflow.PUT("/transitions/:id", middleware.RequirePermission("system.settings"), workflowHandler.UpdateTransition)
+		workflow.DELETE("/transitions/:id", middleware.RequirePermission("system.settings"), workflowHandler.DeleteTransition)
+	}
+}
@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDepartmentRoutes registers all department-related routes
+func SetupDepartmentRoutes(router *gin.RouterGroup, departmentHandler *handler.DepartmentHandler) {
+	departments := router.Group("/departments")
+	{
+		departments.GET("", middleware.RequirePermission("department.view"), departmentHandler.ListDepartments)
+		departments.POST("", middleware.RequirePermission("department.create"), departmentHandler.CreateDepartment)
+		departments.GET("/:id", middleware.RequirePermission("department.view"), departmentHandler.GetDepartment)
+		departments.PATCH("/:id", middleware.RequirePermission("department.edit"), departmentHandler.UpdateDepartment)
+		departments.DELETE("/:id", middleware.RequirePermission("department.delete"), departmentHandler.DeleteDepartment)
+		departments.GET("/:id/members", middleware.RequirePermission("department.view"), middleware.RequirePermission("user.view"), departmentHandler.ListMembers)
+		departments.PUT("/:id/head", middleware.RequirePermission("department.edit"), departmentHandler.SetHead)
+	}
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"backend/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupGoogleAuthRoutes registers the Google OIDC sign-in routes. The client
+// redirects the user to the returned authorization URL and posts the code and
+// state it receives back to the callback, sending along the cookie set by the
+// authorize route.
+func SetupGoogleAuthRoutes(router *gin.RouterGroup, googleAuthHandler *handler.GoogleAuthHandler) {
+	google := router.Group("/auth/google")
+	{
+		google.GET("/authorize", googleAuthHandler.Authorize)
+		google.POST("/callback", googleAuthHandler.Callback)
+	}
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLoginThrottleRoutes registers the admin routes for sign-in lockouts
+func SetupLoginThrottleRoutes(router *gin.RouterGroup, loginThrottleHandler *handler.LoginThrottleHandler) {
+	router.POST("/users/:id/unlock", middleware.RequirePermission("user.edit"), loginThrottleHandler.Unlock)
+}
@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"backend/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMFARoutes registers MFA routes. Verification and challenge enrollment
+// happen mid sign-in and are authenticated by the challenge token; the rest
+// manage the signed-in user's own MFA settings.
+func SetupMFARoutes(router *gin.RouterGroup, authHandler *handler.AuthHandler, mfaHandler *handler.MFAHandler, authenticate gin.HandlerFunc) {
+	mfa := router.Group("/auth/mfa")
+	{
+		mfa.POST("/verify", authHandler.VerifyMFA)
+		mfa.POST("/challenge/enroll", mfaHandler.BeginChallengeEnrollment)
+
+		mfa.POST("/enroll", authenticate, mfaHandler.BeginEnrollment)
+		mfa.POST("/enroll/confirm", authenticate, mfaHandler.ConfirmEnrollment)
+		mfa.POST("/recovery-codes", authenticate, mfaHandler.RegenerateRecoveryCodes)
+		mfa.POST("/disable", authenticate, mfaHandler.Disable)
+	}
+}
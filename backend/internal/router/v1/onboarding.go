@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupOnboardingRoutes registers the bulk user onboarding routes
+func SetupOnboardingRoutes(router *gin.RouterGroup, onboardingHandler *handler.OnboardingHandler) {
+	router.POST("/users/import", middleware.RequirePermission("user.create"), onboardingHandler.ImportUsers)
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRoleRoutes registers role administration routes
+func SetupRoleRoutes(router *gin.RouterGroup, roleHandler *handler.RoleHandler) {
+	roles := router.Group("/roles")
+	{
+		roles.GET("", middleware.RequirePermission("role.view"), roleHandler.ListRoles)
+		roles.POST("", middleware.RequirePermission("role.create"), roleHandler.CreateRole)
+		roles.GET("/:id", middleware.RequirePermission("role.view"), roleHandler.GetRole)
+		roles.PATCH("/:id", middleware.RequirePermission("role.edit"), roleHandler.UpdateRole)
+		roles.DELETE("/:id", middleware.RequirePermission("role.delete"), roleHandler.DeleteRole)
+		roles.GET("/:id/history", middleware.RequirePermission("role.view"), roleHandler.GetRoleHistory)
+		roles.POST("/:id/permissions", middleware.RequirePermission("role.edit"), roleHandler.GrantPermission)
+		roles.DELETE("/:id/permissions/:permissionId", middleware.RequirePermission("role.edit"), roleHandler.RevokePermission)
+		roles.GET("/:id/permissions/effective", middleware.RequirePermission("role.view"), roleHandler.GetEffectivePermissions)
+		roles.PUT("/:id/parent", middleware.RequirePermission("role.edit"), roleHandler.SetParent)
+	}
+
+	router.GET("/permissions", middleware.RequirePermission("role.view"), roleHandler.ListPermissions)
+
+	userRoles := router.Group("/users/:id/roles")
+	{
+		userRoles.GET("", middleware.RequirePermission("role.view"), roleHandler.ListUserRoles)
+		userRoles.POST("", middleware.RequirePermission("role.assign"), roleHandler.AssignRole)
+		userRoles.DELETE("/:roleId", middleware.RequirePermission("role.assign"), roleHandler.UnassignRole)
+	}
+}
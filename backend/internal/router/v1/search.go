@@ -0,0 +1,14 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSearchRoutes registers the full-text search route. Results are
+// limited to the cases, and the evidence, the caller may see.
+func SetupSearchRoutes(router *gin.RouterGroup, searchHandler *handler.SearchHandler) {
+	router.GET("/search", middleware.RequirePermission("case.view"), searchHandler.Search)
+}
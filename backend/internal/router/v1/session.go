@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSessionRoutes registers routes for the caller's own sessions and the
+// admin routes for other users' sessions
+func SetupSessionRoutes(router *gin.RouterGroup, sessionHandler *handler.SessionHandler) {
+	sessions := router.Group("/sessions")
+	{
+		sessions.GET("", sessionHandler.ListSessions)
+		sessions.POST("/revoke-others", sessionHandler.RevokeOtherSessions)
+		sessions.DELETE("/:id", sessionHandler.RevokeSession)
+	}
+
+	userSessions := router.Group("/users/:id/sessions")
+	{
+		userSessions.GET("", middleware.RequirePermission("user.view"), sessionHandler.ListUserSessions)
+		userSessions.POST("/revoke", middleware.RequirePermission("user.edit"), sessionHandler.ForceLogout)
+	}
+}
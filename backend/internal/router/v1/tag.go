@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"backend/internal/handler"
+	"backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTagRoutes registers the tag routes. Officers who edit cases may
+// create tags while tagging; renaming, deleting and merging them changes
+// every case and is kept to administrators.
+func SetupTagRoutes(router *gin.RouterGroup, tagHandler *handler.TagHandler) {
+	tags := router.Group("/tags")
+	{
+		tags.GET("", middleware.RequirePermission("case.view"), tagHandler.ListTags)
+		tags.POST("", middleware.RequirePermission("case.edit"), tagHandler.CreateTag)
+		tags.PATCH("/:id", middleware.RequirePermission("system.settings"), tagHandler.UpdateTag)
+		tags.DELETE("/:id", middleware.RequirePermission("system.settings"), tagHandler.DeleteTag)
+		tags.POST("/:id/merge", middleware.RequirePermission("system.settings"), tagHandler.MergeTag)
+	}
+
+	caseTags := router.Group("/cases/:id/tags")
+	{
+		caseTags.GET("", middleware.RequirePermission("case.view"), tagHandler.ListCaseTags)
+		caseTags.POST("", middleware.RequirePermission("case.edit"), tagHandler.AttachTag)
+		caseTags.DELETE("/:tagId", middleware.RequirePermission("case.edit"), tagHandler.DetachTag)
+	}
+}
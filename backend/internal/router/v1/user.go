@@ -2,6 +2,7 @@ package v1
 
 import (
 	"backend/internal/handler"
+	"backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,7 +11,14 @@ import (
 func SetupUserRoutes(router *gin.RouterGroup, userHandler *handler.UserHandler) {
 	users := router.Group("/users")
 	{
-		users.POST("/register", userHandler.Register)
-		users.GET("/", userHandler.ListUsers)
+		users.POST("/register", middleware.RequirePermission("user.create"), userHandler.Register)
+		users.GET("", middleware.RequirePermission("user.view"), userHandler.ListUsers)
+		users.GET("/:id", middleware.RequirePermission("user.view"), userHandler.GetUser)
+		users.PATCH("/:id", middleware.RequirePermission("user.edit"), userHandler.UpdateUser)
+		users.PUT("/:id/department", middleware.RequirePermission("user.edit"), userHandler.TransferDepartment)
+		users.POST("/:id/deactivate", middleware.RequirePermission("user.edit"), userHandler.DeactivateUser)
+		users.POST("/:id/reactivate", middleware.RequirePermission("user.edit"), userHandler.ReactivateUser)
+		users.DELETE("/:id", middleware.RequirePermission("user.delete"), userHandler.DeleteUser)
+		users.POST("/:id/restore", middleware.RequirePermission("user.delete"), userHandler.RestoreUser)
 	}
 }
@@ -0,0 +1,25 @@
+package scim
+
+// scimType values of 400 Bad Request errors, from RFC 7644 section 3.12.
+const (
+	ErrTypeInvalidFilter = "invalidFilter"
+	ErrTypeInvalidSyntax = "invalidSyntax"
+	ErrTypeInvalidPath   = "invalidPath"
+	ErrTypeNoTarget      = "noTarget"
+	ErrTypeInvalidValue  = "invalidValue"
+	ErrTypeMutability    = "mutability"
+	ErrTypeUniqueness    = "uniqueness"
+)
+
+// Error is a request the client must fix, reported as 400 Bad Request with
+// ScimType set.
+type Error struct {
+	ScimType string
+	Detail   string
+}
+
+func (e *Error) Error() string { return e.Detail }
+
+func newError(scimType, detail string) *Error {
+	return &Error{ScimType: scimType, Detail: detail}
+}
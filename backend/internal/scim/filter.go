@@ -0,0 +1,480 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AttrPath names an attribute, optionally inside an extension schema (URN)
+// and optionally a sub-attribute of a complex attribute (Sub).
+type AttrPath struct {
+	URN  string
+	Name string
+	Sub  string
+}
+
+// ParseAttrPath splits "name.givenName" or
+// "urn:...:User:badgeNumber" into its parts.
+func ParseAttrPath(s string) (AttrPath, error) {
+	var p AttrPath
+	rest := s
+	if strings.HasPrefix(strings.ToLower(s), "urn:") {
+		i := strings.LastIndex(s, ":")
+		p.URN, rest = s[:i], s[i+1:]
+	}
+	p.Name, p.Sub, _ = strings.Cut(rest, ".")
+	if !isAttrName(p.Name) || (p.Sub != "" && !isAttrName(p.Sub)) {
+		return AttrPath{}, newError(ErrTypeInvalidPath, fmt.Sprintf("invalid attribute path %q", s))
+	}
+	return p, nil
+}
+
+// Key is the lower-cased full path, for case-insensitive lookups.
+func (p AttrPath) Key() string {
+	key := strings.ToLower(p.Name)
+	if p.Sub != "" {
+		key += "." + strings.ToLower(p.Sub)
+	}
+	if p.URN != "" {
+		key = strings.ToLower(p.URN) + ":" + key
+	}
+	return key
+}
+
+func isAttrName(s string) bool {
+	if s == "" || !unicode.IsLetter(rune(s[0])) && s[0] != '$' {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter is a parsed filter expression: a *Comparison, *Logical, *Not or
+// *ValuePath.
+type Filter interface {
+	filter()
+}
+
+// Comparison is "attrPath op value", or "attrPath pr" with a nil Value. Op
+// is lower case; Value is a string, float64, bool or nil.
+type Comparison struct {
+	Path  AttrPath
+	Op    string
+	Value interface{}
+}
+
+// Logical joins two filters with Op "and" or "or".
+type Logical struct {
+	Op          string
+	Left, Right Filter
+}
+
+type Not struct {
+	Filter Filter
+}
+
+// ValuePath filters the entries of a multi-valued attribute, as in
+// emails[type eq "work"]. Paths inside Filter are relative to the entry.
+type ValuePath struct {
+	Path   AttrPath
+	Filter Filter
+}
+
+func (*Comparison) filter() {}
+func (*Logical) filter()    {}
+func (*Not) filter()        {}
+func (*ValuePath) filter()  {}
+
+var compareOps = map[string]bool{
+	"eq": true, "ne": true, "co": true, "sw": true, "ew": true,
+	"gt": true, "ge": true, "lt": true, "le": true,
+}
+
+// ParseFilter parses a filter such as
+// `userName eq "jdoe@example.gov" and not (active eq false)`.
+func ParseFilter(s string) (Filter, error) {
+	p := &filterParser{tokens: tokenize(s)}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.peek().text)
+	}
+	return f, nil
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenString
+	tokenOpen
+	tokenClose
+	tokenOpenBracket
+	tokenCloseBracket
+	tokenInvalid
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+func tokenize(s string) []token {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokenOpen, "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenClose, ")"})
+			i++
+		case c == '[':
+			tokens = append(tokens, token{tokenOpenBracket, "["})
+			i++
+		case c == ']':
+			tokens = append(tokens, token{tokenCloseBracket, "]"})
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return append(tokens, token{tokenInvalid, s[i:]})
+			}
+			tokens = append(tokens, token{tokenString, s[i : j+1]})
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\n\r()[]\"", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, s[i:j]})
+			i = j
+		}
+	}
+	return tokens
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *filterParser) peek() token {
+	if p.done() {
+		return token{kind: tokenInvalid}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *filterParser) peekKeyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokenWord && strings.EqualFold(t.text, word)
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return newError(ErrTypeInvalidFilter, "invalid filter: "+fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "or", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Logical{Op: "and", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (Filter, error) {
+	if p.peekKeyword("not") {
+		p.next()
+		if p.peek().kind != tokenOpen {
+			return nil, p.errorf(`"not" must be followed by "("`)
+		}
+		f, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Filter: f}, nil
+	}
+
+	if p.peek().kind == tokenOpen {
+		p.next()
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenClose {
+			return nil, p.errorf(`missing ")"`)
+		}
+		return f, nil
+	}
+
+	return p.parseAttrExpr()
+}
+
+func (p *filterParser) parseAttrExpr() (Filter, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		return nil, p.errorf("expected an attribute, got %q", t.text)
+	}
+	path, err := ParseAttrPath(t.text)
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+
+	if p.peek().kind == tokenOpenBracket {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenCloseBracket {
+			return nil, p.errorf(`missing "]"`)
+		}
+		return &ValuePath{Path: path, Filter: inner}, nil
+	}
+
+	op := strings.ToLower(p.next().text)
+	if op == "pr" {
+		return &Comparison{Path: path, Op: op}, nil
+	}
+	if !compareOps[op] {
+		return nil, p.errorf("unknown operator %q", op)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &Comparison{Path: path, Op: op, Value: value}, nil
+}
+
+func (p *filterParser) parseValue() (interface{}, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		var s string
+		if err := json.Unmarshal([]byte(t.text), &s); err != nil {
+			return nil, p.errorf("invalid string %s", t.text)
+		}
+		return s, nil
+	case tokenWord:
+		switch strings.ToLower(t.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		if n, err := strconv.ParseFloat(t.text, 64); err == nil {
+			return n, nil
+		}
+	}
+	return nil, p.errorf("invalid value %q", t.text)
+}
+
+// Match evaluates f against a resource decoded from JSON. Attribute names
+// and string values compare case-insensitively.
+func Match(f Filter, resource map[string]interface{}) bool {
+	switch f := f.(type) {
+	case *Logical:
+		if f.Op == "and" {
+			return Match(f.Left, resource) && Match(f.Right, resource)
+		}
+		return Match(f.Left, resource) || Match(f.Right, resource)
+	case *Not:
+		return !Match(f.Filter, resource)
+	case *ValuePath:
+		for _, entry := range entries(lookup(resource, f.Path.URN, f.Path.Name)) {
+			if Match(f.Filter, entry) {
+				return true
+			}
+		}
+		return false
+	case *Comparison:
+		values := values(resource, f.Path)
+		if f.Op == "pr" {
+			for _, v := range values {
+				if v != nil && v != "" {
+					return true
+				}
+			}
+			return false
+		}
+		if f.Op == "ne" {
+			return !Match(&Comparison{Path: f.Path, Op: "eq", Value: f.Value}, resource)
+		}
+		for _, v := range values {
+			if compare(v, f.Op, f.Value) {
+				return true
+			}
+		}
+		return f.Op == "eq" && f.Value == nil && len(values) == 0
+	}
+	return false
+}
+
+// values returns what path refers to, flattening multi-valued attributes.
+// A multi-valued attribute without a sub-attribute compares by "value".
+func values(resource map[string]interface{}, path AttrPath) []interface{} {
+	v := lookup(resource, path.URN, path.Name)
+	sub := path.Sub
+	list, multi := v.([]interface{})
+	if !multi {
+		list = []interface{}{v}
+	} else if sub == "" {
+		sub = "value"
+	}
+
+	var out []interface{}
+	for _, item := range list {
+		if sub != "" {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			item = lookup(m, "", sub)
+		}
+		if item != nil {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func entries(v interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	list, _ := v.([]interface{})
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// lookup returns resource[name], or resource[urn][name] for extension
+// attributes, matching keys case-insensitively.
+func lookup(resource map[string]interface{}, urn, name string) interface{} {
+	if urn != "" {
+		ext, _ := lookup(resource, "", urn).(map[string]interface{})
+		if ext == nil {
+			return nil
+		}
+		resource = ext
+	}
+	if key, ok := findKey(resource, name); ok {
+		return resource[key]
+	}
+	return nil
+}
+
+func findKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for key := range m {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+func compare(actual interface{}, op string, expected interface{}) bool {
+	switch e := expected.(type) {
+	case string:
+		a, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		a, e = strings.ToLower(a), strings.ToLower(e)
+		switch op {
+		case "eq":
+			return a == e
+		case "co":
+			return strings.Contains(a, e)
+		case "sw":
+			return strings.HasPrefix(a, e)
+		case "ew":
+			return strings.HasSuffix(a, e)
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case float64:
+		a, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return a == e
+		case "gt":
+			return a > e
+		case "ge":
+			return a >= e
+		case "lt":
+			return a < e
+		case "le":
+			return a <= e
+		}
+	case bool:
+		return op == "eq" && actual == e
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package scim
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// render prints a parsed filter in prefix form, with attribute paths as
+// their lower-cased keys.
+func render(f Filter) string {
+	switch f := f.(type) {
+	case *Logical:
+		return fmt.Sprintf("(%s %s %s)", f.Op, render(f.Left), render(f.Right))
+	case *Not:
+		return fmt.Sprintf("(not %s)", render(f.Filter))
+	case *ValuePath:
+		return fmt.Sprintf("%s[%s]", f.Path.Key(), render(f.Filter))
+	case *Comparison:
+		if f.Op == "pr" {
+			return fmt.Sprintf("(pr %s)", f.Path.Key())
+		}
+		return fmt.Sprintf("(%s %s %#v)", f.Op, f.Path.Key(), f.Value)
+	}
+	return fmt.Sprintf("%T", f)
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{`userName eq "jdoe@example.gov"`, `(eq username "jdoe@example.gov")`},
+		{`userName Eq "A\"B"`, `(eq username "A\"B")`},
+		{`title pr`, `(pr title)`},
+		{`meta.lastModified gt "2026-01-01T00:00:00Z"`, `(gt meta.lastmodified "2026-01-01T00:00:00Z")`},
+		{`active eq true`, `(eq active true)`},
+		{`id eq 7`, `(eq id 7)`},
+		{`manager eq null`, `(eq manager <nil>)`},
+		{
+			`urn:ietf:params:scim:schemas:extension:trueforce:2.0:User:badgeNumber sw "B-"`,
+			`(sw urn:ietf:params:scim:schemas:extension:trueforce:2.0:user:badgenumber "B-")`,
+		},
+		{`a eq "1" or b eq "2" and c eq "3"`, `(or (eq a "1") (and (eq b "2") (eq c "3")))`},
+		{`a eq "1" and b eq "2" or c eq "3"`, `(or (and (eq a "1") (eq b "2")) (eq c "3"))`},
+		{`a eq "1" AND b eq "2" And c eq "3"`, `(and (and (eq a "1") (eq b "2")) (eq c "3"))`},
+		{`(a eq "1" or b eq "2") and c eq "3"`, `(and (or (eq a "1") (eq b "2")) (eq c "3"))`},
+		{`not (active eq false) and userName pr`, `(and (not (eq active false)) (pr username))`},
+		{`not (a eq "1" or b eq "2")`, `(not (or (eq a "1") (eq b "2")))`},
+		{`emails[value eq "x"]`, `emails[(eq value "x")]`},
+		{
+			`emails[type eq "work" and value co "@dept.gov"] or userName sw "j"`,
+			`(or emails[(and (eq type "work") (co value "@dept.gov"))] (sw username "j"))`,
+		},
+		{`members[value eq "7"] and not (displayName ew "admins")`, `(and members[(eq value "7")] (not (ew displayname "admins")))`},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Errorf("ParseFilter(%s): %v", tt.filter, err)
+			continue
+		}
+		if got := render(f); got != tt.want {
+			t.Errorf("ParseFilter(%s)\n got %s\nwant %s", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterRejects(t *testing.T) {
+	filters := []string{
+		``,
+		`userName`,
+		`userName eq`,
+		`userName xx "a"`,
+		`userName eq bogus`,
+		`userName eq "unterminated`,
+		`userName eq "a" userName eq "b"`,
+		`userName eq "a" and`,
+		`1userName eq "a"`,
+		`name.given.family eq "a"`,
+		`not active eq true`,
+		`(userName eq "a"`,
+		`userName eq "a")`,
+		`emails[value eq "a"`,
+		`emails[value eq "a")`,
+	}
+	for _, filter := range filters {
+		_, err := ParseFilter(filter)
+		var scimErr *Error
+		if !errors.As(err, &scimErr) || scimErr.ScimType != ErrTypeInvalidFilter {
+			t.Errorf("ParseFilter(%s): got %v, want an %s error", filter, err, ErrTypeInvalidFilter)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	user := map[string]interface{}{
+		"userName": "JDoe@Example.gov",
+		"active":   true,
+		"name":     map[string]interface{}{"givenName": "John"},
+		"emails": []interface{}{
+			map[string]interface{}{"type": "work", "value": "jdoe@example.gov"},
+			map[string]interface{}{"type": "home", "value": "john@mail.test"},
+		},
+		"urn:ietf:params:scim:schemas:extension:trueforce:2.0:User": map[string]interface{}{
+			"badgeNumber": "B-100",
+		},
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`username eq "jdoe@example.gov"`, true},
+		{`userName ne "jdoe@example.gov"`, false},
+		{`name.givenName sw "jo"`, true},
+		{`name.familyName pr`, false},
+		{`name.familyName eq null`, true},
+		{`emails co "mail.test"`, true},
+		{`emails[type eq "work" and value ew "@example.gov"]`, true},
+		{`emails[type eq "home" and value ew "@example.gov"]`, false},
+		{`active eq true and not (userName co "admin")`, true},
+		{`active eq false or emails.value eq "nobody@example.gov"`, false},
+		{`urn:ietf:params:scim:schemas:extension:trueforce:2.0:User:badgeNumber eq "b-100"`, true},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseFilter(%s): %v", tt.filter, err)
+		}
+		if got := Match(f, user); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplyPatch applies PATCH operations, in order, to a resource decoded from
+// JSON. Callers decode the result back into the resource type and save it
+// like a PUT, so validation happens in one place.
+func ApplyPatch(resource map[string]interface{}, ops []PatchOperation) error {
+	for _, op := range ops {
+		if err := applyOperation(resource, strings.ToLower(op.Op), op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// patchPath is a PATCH target: an attribute, optionally narrowed to the
+// entries of a multi-valued attribute matching Filter, and optionally a
+// sub-attribute of those entries.
+type patchPath struct {
+	attr   AttrPath
+	filter Filter
+	sub    string
+}
+
+func parsePatchPath(s string) (patchPath, error) {
+	open := strings.Index(s, "[")
+	if open < 0 {
+		attr, err := ParseAttrPath(s)
+		return patchPath{attr: attr}, err
+	}
+
+	closing := strings.LastIndex(s, "]")
+	if closing < open {
+		return patchPath{}, newError(ErrTypeInvalidPath, fmt.Sprintf("invalid path %q", s))
+	}
+	attr, err := ParseAttrPath(s[:open])
+	if err != nil || attr.Sub != "" {
+		return patchPath{}, newError(ErrTypeInvalidPath, fmt.Sprintf("invalid path %q", s))
+	}
+	filter, err := ParseFilter(s[open+1 : closing])
+	if err != nil {
+		return patchPath{}, newError(ErrTypeInvalidPath, fmt.Sprintf("invalid path %q: %v", s, err))
+	}
+
+	p := patchPath{attr: attr, filter: filter}
+	if rest := s[closing+1:]; rest != "" {
+		if !strings.HasPrefix(rest, ".") || !isAttrName(rest[1:]) {
+			return patchPath{}, newError(ErrTypeInvalidPath, fmt.Sprintf("invalid path %q", s))
+		}
+		p.sub = rest[1:]
+	}
+	return p, nil
+}
+
+func applyOperation(resource map[string]interface{}, op, path string, value interface{}) error {
+	if op != "add" && op != "replace" && op != "remove" {
+		return newError(ErrTypeInvalidSyntax, fmt.Sprintf("unsupported patch operation %q", op))
+	}
+
+	if path == "" {
+		if op == "remove" {
+			return newError(ErrTypeNoTarget, "remove requires a path")
+		}
+		attrs, ok := value.(map[string]interface{})
+		if !ok {
+			return newError(ErrTypeInvalidValue, "value must be an object when path is omitted")
+		}
+		for key, v := range attrs {
+			// Extension attributes may be nested under their schema URN.
+			if ext, ok := v.(map[string]interface{}); ok && strings.HasPrefix(strings.ToLower(key), "urn:") {
+				for name, extValue := range ext {
+					if err := applyOperation(resource, op, key+":"+name, extValue); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := applyOperation(resource, op, key, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	target, err := parsePatchPath(path)
+	if err != nil {
+		return err
+	}
+
+	container := resource
+	if target.attr.URN != "" {
+		key, ok := findKey(resource, target.attr.URN)
+		if !ok {
+			if op == "remove" {
+				return nil
+			}
+			key = target.attr.URN
+			resource[key] = map[string]interface{}{}
+		}
+		ext, ok := resource[key].(map[string]interface{})
+		if !ok {
+			return newError(ErrTypeInvalidPath, fmt.Sprintf("invalid path %q", path))
+		}
+		container = ext
+	}
+
+	key, ok := findKey(container, target.attr.Name)
+	if !ok {
+		key = target.attr.Name
+	}
+
+	if target.filter != nil {
+		return applyToEntries(container, key, op, target, value)
+	}
+	if target.attr.Sub != "" {
+		return applyToSubAttr(container, key, op, target.attr.Sub, value)
+	}
+
+	existing := container[key]
+	switch op {
+	case "remove":
+		list, isList := existing.([]interface{})
+		if isList && value != nil {
+			// Some clients remove multi-valued entries by listing them in
+			// value instead of filtering the path.
+			container[key] = without(list, asList(value))
+		} else {
+			delete(container, key)
+		}
+	case "add":
+		list, isList := existing.([]interface{})
+		if _, valueIsList := value.([]interface{}); isList || valueIsList {
+			container[key] = union(list, asList(value))
+		} else {
+			container[key] = merge(existing, value)
+		}
+	case "replace":
+		container[key] = merge(existing, value)
+	}
+	return nil
+}
+
+func applyToSubAttr(container map[string]interface{}, key, op, sub string, value interface{}) error {
+	if list, ok := container[key].([]interface{}); ok {
+		for _, entry := range list {
+			if m, ok := entry.(map[string]interface{}); ok {
+				setSubAttr(m, op, sub, value)
+			}
+		}
+		return nil
+	}
+
+	obj, ok := container[key].(map[string]interface{})
+	if !ok {
+		if op == "remove" {
+			return nil
+		}
+		obj = map[string]interface{}{}
+		container[key] = obj
+	}
+	setSubAttr(obj, op, sub, value)
+	return nil
+}
+
+func setSubAttr(obj map[string]interface{}, op, sub string, value interface{}) {
+	key, ok := findKey(obj, sub)
+	if !ok {
+		key = sub
+	}
+	if op == "remove" {
+		delete(obj, key)
+		return
+	}
+	obj[key] = value
+}
+
+// applyToEntries handles paths such as members[value eq "2"] and
+// emails[type eq "work"].value.
+func applyToEntries(container map[string]interface{}, key, op string, target patchPath, value interface{}) error {
+	list, _ := container[key].([]interface{})
+
+	var kept []interface{}
+	matched := 0
+	for _, entry := range list {
+		m, ok := entry.(map[string]interface{})
+		if !ok || !Match(target.filter, m) {
+			kept = append(kept, entry)
+			continue
+		}
+		matched++
+		switch {
+		case op == "remove" && target.sub == "":
+			continue
+		case target.sub != "":
+			setSubAttr(m, op, target.sub, value)
+		default:
+			entry = merge(m, value)
+		}
+		kept = append(kept, entry)
+	}
+
+	if matched == 0 && op != "remove" {
+		// A path like emails[type eq "work"].value creates the entry it
+		// names when none exists yet.
+		c, ok := target.filter.(*Comparison)
+		if !ok || c.Op != "eq" || c.Path.Sub != "" || c.Value == nil {
+			return newError(ErrTypeNoTarget, "no entries match the path filter")
+		}
+		entry := map[string]interface{}{c.Path.Name: c.Value}
+		if target.sub != "" {
+			entry[target.sub] = value
+		} else if m, ok := value.(map[string]interface{}); ok {
+			entry = merge(entry, m).(map[string]interface{})
+		}
+		kept = append(kept, entry)
+	}
+
+	container[key] = kept
+	return nil
+}
+
+// merge updates a complex attribute with the sub-attributes in value,
+// leaving the others unchanged. Any other value replaces existing.
+func merge(existing, value interface{}) interface{} {
+	current, ok := existing.(map[string]interface{})
+	update, isMap := value.(map[string]interface{})
+	if !ok || !isMap {
+		return value
+	}
+	for k, v := range update {
+		if key, found := findKey(current, k); found {
+			k = key
+		}
+		current[k] = v
+	}
+	return current
+}
+
+func asList(value interface{}) []interface{} {
+	if list, ok := value.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{value}
+}
+
+// union appends the entries of add that list does not contain yet.
+func union(list, add []interface{}) []interface{} {
+	for _, entry := range add {
+		if indexOf(list, entry) < 0 {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// without drops the entries of list that appear in remove.
+func without(list, remove []interface{}) []interface{} {
+	var kept []interface{}
+	for _, entry := range list {
+		if indexOf(remove, entry) < 0 {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// indexOf finds entry in list, comparing multi-valued entries by "value".
+func indexOf(list []interface{}, entry interface{}) int {
+	want := entryValue(entry)
+	for i, item := range list {
+		if strings.EqualFold(fmt.Sprint(entryValue(item)), fmt.Sprint(want)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func entryValue(entry interface{}) interface{} {
+	if m, ok := entry.(map[string]interface{}); ok {
+		return lookup(m, "", "value")
+	}
+	return entry
+}
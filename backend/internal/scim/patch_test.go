@@ -0,0 +1,156 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+func TestApplyPatch(t *testing.T) {
+	const group = `{"displayName": "Detectives", "members": [{"value": "3"}, {"value": "7"}]}`
+	const user = `{
+		"userName": "jdoe@example.gov",
+		"active": true,
+		"name": {"givenName": "John", "familyName": "Doe"},
+		"emails": [{"type": "work", "value": "jdoe@example.gov"}]
+	}`
+
+	tests := []struct {
+		name     string
+		resource string
+		op       string
+		path     string
+		value    string
+		want     string
+	}{
+		{
+			"replace without a path",
+			user, "replace", "", `{"active": false, "name": {"givenName": "Jon"}}`,
+			`{"userName": "jdoe@example.gov", "active": false, "name": {"givenName": "Jon", "familyName": "Doe"},
+				"emails": [{"type": "work", "value": "jdoe@example.gov"}]}`,
+		},
+		{
+			"add without a path sets extension attributes",
+			`{"userName": "jdoe@example.gov"}`, "add", "",
+			`{"urn:ietf:params:scim:schemas:extension:trueforce:2.0:User": {"badgeNumber": "B-100"}}`,
+			`{"userName": "jdoe@example.gov", "urn:ietf:params:scim:schemas:extension:trueforce:2.0:User": {"badgeNumber": "B-100"}}`,
+		},
+		{
+			"replace a sub-attribute",
+			`{"name": {"givenName": "John", "familyName": "Doe"}}`, "replace", "name.givenName", `"Jon"`,
+			`{"name": {"givenName": "Jon", "familyName": "Doe"}}`,
+		},
+		{
+			"attribute names are case-insensitive",
+			`{"active": true}`, "replace", "ACTIVE", `false`,
+			`{"active": false}`,
+		},
+		{
+			"replace an extension attribute by its full path",
+			`{}`, "replace", "urn:ietf:params:scim:schemas:extension:trueforce:2.0:User:badgeNumber", `"B-7"`,
+			`{"urn:ietf:params:scim:schemas:extension:trueforce:2.0:User": {"badgeNumber": "B-7"}}`,
+		},
+		{
+			"add members skips existing ones",
+			group, "add", "members", `[{"value": "7"}, {"value": "9"}]`,
+			`{"displayName": "Detectives", "members": [{"value": "3"}, {"value": "7"}, {"value": "9"}]}`,
+		},
+		{
+			"remove a member by filter",
+			group, "remove", `members[value eq "7"]`, ``,
+			`{"displayName": "Detectives", "members": [{"value": "3"}]}`,
+		},
+		{
+			"remove a member that is not there",
+			group, "remove", `members[value eq "8"]`, ``,
+			group,
+		},
+		{
+			"remove members listed in the value",
+			group, "remove", "members", `[{"value": "3"}]`,
+			`{"displayName": "Detectives", "members": [{"value": "7"}]}`,
+		},
+		{
+			"remove all members",
+			group, "remove", "members", ``,
+			`{"displayName": "Detectives"}`,
+		},
+		{
+			"replace all members",
+			group, "replace", "members", `[{"value": "9"}]`,
+			`{"displayName": "Detectives", "members": [{"value": "9"}]}`,
+		},
+		{
+			"replace the sub-attribute of a filtered entry",
+			user, "replace", `emails[type eq "work"].value`, `"john.doe@example.gov"`,
+			`{"userName": "jdoe@example.gov", "active": true, "name": {"givenName": "John", "familyName": "Doe"},
+				"emails": [{"type": "work", "value": "john.doe@example.gov"}]}`,
+		},
+		{
+			"a filtered path creates the entry it names",
+			`{"emails": []}`, "add", `emails[type eq "home"].value`, `"john@mail.test"`,
+			`{"emails": [{"type": "home", "value": "john@mail.test"}]}`,
+		},
+		{
+			"replace an entry that is not there creates it",
+			group, "replace", `members[value eq "9"]`, `{"display": "Jane Roe"}`,
+			`{"displayName": "Detectives", "members": [{"value": "3"}, {"value": "7"}, {"display": "Jane Roe", "value": "9"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		resource := decodeJSON(t, tt.resource).(map[string]interface{})
+		var value interface{}
+		if tt.value != "" {
+			value = decodeJSON(t, tt.value)
+		}
+
+		if err := ApplyPatch(resource, []PatchOperation{{Op: tt.op, Path: tt.path, Value: value}}); err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got, _ := json.Marshal(resource)
+		want, _ := json.Marshal(decodeJSON(t, tt.want))
+		if string(got) != string(want) {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, want)
+		}
+	}
+}
+
+func TestApplyPatchRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    string
+		path  string
+		value interface{}
+		want  string
+	}{
+		{"unknown operation", "move", "active", false, ErrTypeInvalidSyntax},
+		{"remove without a path", "remove", "", nil, ErrTypeNoTarget},
+		{"no path and no object", "replace", "", "Detectives", ErrTypeInvalidValue},
+		{"invalid attribute", "replace", "1name", "x", ErrTypeInvalidPath},
+		{"unclosed filter", "remove", `members[value eq "7"`, nil, ErrTypeInvalidPath},
+		{"invalid filter", "remove", `members[value xx "7"]`, nil, ErrTypeInvalidPath},
+		{"sub-attribute before the filter", "remove", `members.value[value eq "7"]`, nil, ErrTypeInvalidPath},
+		{"text after the filter", "replace", `members[value eq "7"]display`, "x", ErrTypeInvalidPath},
+		{"cannot create an entry from a complex filter", "add", `members[value eq "8" or value eq "9"].display`, "x", ErrTypeNoTarget},
+	}
+	for _, tt := range tests {
+		resource := map[string]interface{}{
+			"members": []interface{}{map[string]interface{}{"value": "7"}},
+		}
+		err := ApplyPatch(resource, []PatchOperation{{Op: tt.op, Path: tt.path, Value: tt.value}})
+		var scimErr *Error
+		if !errors.As(err, &scimErr) || scimErr.ScimType != tt.want {
+			t.Errorf("%s: got %v, want a %s error", tt.name, err, tt.want)
+		}
+	}
+}
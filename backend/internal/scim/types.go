@@ -0,0 +1,203 @@
+// Package scim implements the parts of the SCIM 2.0 protocol (RFC 7643 and
+// RFC 7644) used to provision users and groups from an external identity
+// system: resource types, filters and PATCH operations.
+package scim
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaUserExtension         = "urn:ietf:params:scim:schemas:extension:trueforce:2.0:User"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+// ContentType is the media type of SCIM requests and responses.
+const ContentType = "application/scim+json"
+
+// MaxResults caps the page size of list requests.
+const MaxResults = 200
+
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+// MultiValue is an entry of a multi-valued attribute such as emails or
+// members.
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// UserExtension holds the attributes of SchemaUserExtension.
+type UserExtension struct {
+	BadgeNumber string `json:"badgeNumber"`
+}
+
+// User is the SCIM User resource. UserName is the account's email address.
+// Groups is read-only and ignored on writes.
+type User struct {
+	Schemas      []string       `json:"schemas"`
+	ID           string         `json:"id,omitempty"`
+	UserName     string         `json:"userName"`
+	Name         *Name          `json:"name,omitempty"`
+	DisplayName  string         `json:"displayName,omitempty"`
+	Emails       []MultiValue   `json:"emails,omitempty"`
+	PhoneNumbers []MultiValue   `json:"phoneNumbers,omitempty"`
+	Active       *bool          `json:"active,omitempty"`
+	Groups       []MultiValue   `json:"groups,omitempty"`
+	Extension    *UserExtension `json:"urn:ietf:params:scim:schemas:extension:trueforce:2.0:User,omitempty"`
+	Meta         *Meta          `json:"meta,omitempty"`
+}
+
+// Group is the SCIM Group resource; member values are user IDs.
+type Group struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	DisplayName string       `json:"displayName"`
+	Members     []MultiValue `json:"members,omitempty"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// ListQuery selects a page of resources. StartIndex is 1-based.
+type ListQuery struct {
+	Filter     string `form:"filter"`
+	StartIndex int    `form:"startIndex"`
+	Count      *int   `form:"count"`
+}
+
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int64         `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations" binding:"required,min=1"`
+}
+
+type PatchOperation struct {
+	Op    string      `json:"op" binding:"required"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ErrorResponse is the body of every SCIM error response.
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func NewErrorResponse(status int, scimType, detail string) ErrorResponse {
+	return ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type filterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type bulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type authenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+type ServiceProviderConfigResponse struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 supported              `json:"patch"`
+	Bulk                  bulkSupport            `json:"bulk"`
+	Filter                filterSupport          `json:"filter"`
+	ChangePassword        supported              `json:"changePassword"`
+	Sort                  supported              `json:"sort"`
+	ETag                  supported              `json:"etag"`
+	AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+}
+
+// ServiceProviderConfig describes the features this server supports.
+func ServiceProviderConfig() ServiceProviderConfigResponse {
+	return ServiceProviderConfigResponse{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   supported{Supported: true},
+		Filter:  filterSupport{Supported: true, MaxResults: MaxResults},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "Authentication with the SCIM bearer token configured on the server",
+			Primary:     true,
+		}},
+	}
+}
+
+type schemaExtension struct {
+	Schema   string `json:"schema"`
+	Required bool   `json:"required"`
+}
+
+type ResourceTypeResponse struct {
+	Schemas          []string          `json:"schemas"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Endpoint         string            `json:"endpoint"`
+	Schema           string            `json:"schema"`
+	SchemaExtensions []schemaExtension `json:"schemaExtensions,omitempty"`
+}
+
+// ResourceTypes lists the resources served under the SCIM base URL.
+func ResourceTypes() []ResourceTypeResponse {
+	return []ResourceTypeResponse{
+		{
+			Schemas:          []string{SchemaResourceType},
+			ID:               "User",
+			Name:             "User",
+			Endpoint:         "/Users",
+			Schema:           SchemaUser,
+			SchemaExtensions: []schemaExtension{{Schema: SchemaUserExtension}},
+		},
+		{
+			Schemas:  []string{SchemaResourceType},
+			ID:       "Group",
+			Name:     "Group",
+			Endpoint: "/Groups",
+			Schema:   SchemaGroup,
+		},
+	}
+}
@@ -0,0 +1,188 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var errMalformedHash = errors.New("malformed password hash")
+
+// PasswordConfig selects the algorithm used for new hashes and its cost
+// parameters. Zero values fall back to DefaultPasswordConfig.
+type PasswordConfig struct {
+	Algorithm         string
+	Argon2Memory      uint32 // KiB
+	Argon2Iterations  uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+func DefaultPasswordConfig() PasswordConfig {
+	return PasswordConfig{
+		Algorithm:         PasswordAlgorithmArgon2id,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 2,
+		BcryptCost:        12,
+	}
+}
+
+// PasswordHasher hashes passwords into self-describing strings and verifies
+// any hash this package has ever produced, including legacy unsalted SHA-256
+// hex digests. Verify reports needsRehash when the stored hash does not match
+// the configured algorithm and parameters, so callers can upgrade it after a
+// successful sign-in.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (ok, needsRehash bool)
+}
+
+type passwordHasher struct {
+	config PasswordConfig
+}
+
+func NewPasswordHasher(config PasswordConfig) PasswordHasher {
+	defaults := DefaultPasswordConfig()
+	if config.Algorithm != PasswordAlgorithmBcrypt {
+		config.Algorithm = PasswordAlgorithmArgon2id
+	}
+	if config.Argon2Memory == 0 {
+		config.Argon2Memory = defaults.Argon2Memory
+	}
+	if config.Argon2Iterations == 0 {
+		config.Argon2Iterations = defaults.Argon2Iterations
+	}
+	if config.Argon2Parallelism == 0 {
+		config.Argon2Parallelism = defaults.Argon2Parallelism
+	}
+	if config.BcryptCost == 0 {
+		config.BcryptCost = defaults.BcryptCost
+	}
+	return &passwordHasher{config: config}
+}
+
+func (h *passwordHasher) Hash(password string) (string, error) {
+	if h.config.Algorithm == PasswordAlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	params := argon2Params{
+		memory:      h.config.Argon2Memory,
+		iterations:  h.config.Argon2Iterations,
+		parallelism: h.config.Argon2Parallelism,
+	}
+	key := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.iterations, params.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *passwordHasher) Verify(encoded, password string) (bool, bool) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, false
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(key, candidate) != 1 {
+			return false, false
+		}
+		return true, h.config.Algorithm != PasswordAlgorithmArgon2id ||
+			params.memory != h.config.Argon2Memory ||
+			params.iterations != h.config.Argon2Iterations ||
+			params.parallelism != h.config.Argon2Parallelism ||
+			len(key) != argon2KeyLength
+
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		if bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) != nil {
+			return false, false
+		}
+		cost, err := bcrypt.Cost([]byte(encoded))
+		return true, err != nil || h.config.Algorithm != PasswordAlgorithmBcrypt || cost != h.config.BcryptCost
+
+	case isLegacySHA256(encoded):
+		// Seeded accounts were stored as unsalted SHA-256 hex digests.
+		sum := sha256.Sum256([]byte(password))
+		candidate := hex.EncodeToString(sum[:])
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(encoded)), []byte(candidate)) != 1 {
+			return false, false
+		}
+		return true, true
+	}
+
+	return false, false
+}
+
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+func decodeArgon2id(encoded string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errMalformedHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return params, nil, nil, errMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, errMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, errMalformedHash
+	}
+
+	return params, salt, key, nil
+}
+
+func isLegacySHA256(encoded string) bool {
+	if len(encoded) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(encoded)
+	return err == nil
+}
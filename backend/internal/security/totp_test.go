@@ -0,0 +1,92 @@
+package security
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 appendix B test vectors.
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	key := []byte("12345678901234567890")
+	for _, tt := range tests {
+		if got := totpCode(key, tt.unix/totpPeriod); got != tt.want {
+			t.Errorf("code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+
+		step, ok := ValidateTOTP(rfc6238Secret, tt.want, time.Unix(tt.unix, 0))
+		if !ok || step != tt.unix/totpPeriod {
+			t.Errorf("ValidateTOTP at %d = %d, %v, want step %d", tt.unix, step, ok, tt.unix/totpPeriod)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name string
+		step int64
+		ok   bool
+	}{
+		{"two steps behind", current - 2, false},
+		{"one step behind", current - 1, true},
+		{"current step", current, true},
+		{"one step ahead", current + 1, true},
+		{"two steps ahead", current + 2, false},
+	}
+	for _, tt := range tests {
+		step, ok := ValidateTOTP(rfc6238Secret, totpCode(key, tt.step), now)
+		if ok != tt.ok || (ok && step != tt.step) {
+			t.Errorf("%s: got step %d, %v; want step %d, %v", tt.name, step, ok, tt.step, tt.ok)
+		}
+	}
+
+	// The window moves with the step boundary, not with now.
+	next := time.Unix((current+1)*totpPeriod, 0)
+	if _, ok := ValidateTOTP(rfc6238Secret, totpCode(key, current-1), next); ok {
+		t.Error("code from two steps back accepted just after the step boundary")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, totpCode(key, current), next.Add(-time.Second)); !ok {
+		t.Error("current code rejected in the last second of the step")
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{"valid", rfc6238Secret, "287082", true},
+		{"secret in lower case with spaces", " " + "gezdgnbvgy3tqojqgezdgnbvgy3tqojq" + " ", "287082", true},
+		{"wrong code", rfc6238Secret, "287083", false},
+		{"short code", rfc6238Secret, "28708", false},
+		{"long code", rfc6238Secret, "2870820", false},
+		{"8-digit RFC code", rfc6238Secret, "94287082", false},
+		{"secret is not base32", "not base32!", "287082", false},
+		{"empty code", rfc6238Secret, "", false},
+	}
+	for _, tt := range tests {
+		if _, ok := ValidateTOTP(tt.secret, tt.code, now); ok != tt.ok {
+			t.Errorf("%s: got %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"backend/internal/clientinfo"
+	"backend/internal/model"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// auditChange is the Details payload of audit entries that record an edit.
+type auditChange struct {
+	Previous interface{} `json:"previous,omitempty"`
+	New      interface{} `json:"new,omitempty"`
+}
+
+// newAuditLog builds an audit entry stamped with the client of the current
+// request. details is stored as JSON and may be nil.
+func newAuditLog(ctx context.Context, userID *uint, action, entityType string, entityID *uint, details interface{}) (*models.AuditLog, error) {
+	client := clientinfo.FromContext(ctx)
+	entry := &models.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IPAddress:  client.IPAddress,
+		UserAgent:  client.UserAgent,
+		CreatedAt:  time.Now(),
+	}
+
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return nil, err
+		}
+		entry.Details = datatypes.JSON(data)
+	}
+
+	return entry, nil
+}
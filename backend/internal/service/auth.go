@@ -0,0 +1,233 @@
+package service
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+type AuthService interface {
+	SignIn(ctx context.Context, payload auth.SignInRequest) (*auth.SignInResponse, error)
+	CompleteSignIn(ctx context.Context, user *models.User) (*auth.SignInResponse, error)
+	VerifyMFA(ctx context.Context, payload auth.MFAVerifyRequest) (*auth.SignInResponse, error)
+	Refresh(ctx context.Context, payload auth.RefreshTokenRequest) (*auth.TokenPair, error)
+	Logout(ctx context.Context, payload auth.LogoutRequest) error
+	Authenticate(ctx context.Context, accessToken string) (*principal.Principal, error)
+}
+
+type authService struct {
+	userRepo       repository.UserRepository
+	tokenService   TokenService
+	authorizer     Authorizer
+	passwordHasher security.PasswordHasher
+	mfaService     MFAService
+	sessionService SessionService
+	loginThrottle  LoginThrottleService
+}
+
+func NewAuthService(
+	userRepo repository.UserRepository,
+	tokenService TokenService,
+	authorizer Authorizer,
+	passwordHasher security.PasswordHasher,
+	mfaService MFAService,
+	sessionService SessionService,
+	loginThrottle LoginThrottleService,
+) AuthService {
+	return &authService{
+		userRepo:       userRepo,
+		tokenService:   tokenService,
+		authorizer:     authorizer,
+		passwordHasher: passwordHasher,
+		mfaService:     mfaService,
+		sessionService: sessionService,
+		loginThrottle:  loginThrottle,
+	}
+}
+
+func (s *authService) SignIn(ctx context.Context, payload auth.SignInRequest) (*auth.SignInResponse, error) {
+	if err := s.loginThrottle.Check(ctx, payload.Email); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.PasswordHash == "" {
+		return nil, s.failSignIn(ctx, payload.Email, user)
+	}
+	ok, needsRehash := s.passwordHasher.Verify(user.PasswordHash, payload.Password)
+	if !ok {
+		return nil, s.failSignIn(ctx, payload.Email, user)
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if needsRehash {
+		s.upgradePasswordHash(ctx, user.ID, payload.Password)
+	}
+
+	res, err := s.CompleteSignIn(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	// A correct password alone does not clear the account's failures while
+	// a second factor is pending; VerifyMFA clears them once it passes.
+	if !res.MFARequired {
+		if err := s.loginThrottle.RecordSuccess(ctx, payload.Email); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// failSignIn counts a wrong email or password towards the lockout limits
+// and returns the error for the attempt.
+func (s *authService) failSignIn(ctx context.Context, email string, user *models.User) error {
+	if err := s.loginThrottle.RecordFailure(ctx, email, user); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// CompleteSignIn finishes the first factor for a user whose identity the
+// caller has already verified, by password or an external identity provider.
+// Users who need a second factor get an MFA challenge instead of tokens.
+func (s *authService) CompleteSignIn(ctx context.Context, user *models.User) (*auth.SignInResponse, error) {
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	required, err := s.mfaService.Required(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return s.mfaService.CreateChallenge(ctx, user)
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// VerifyMFA redeems an MFA challenge and issues tokens. Wrong codes count
+// towards the same lockout limits as wrong passwords, so the attempt limit
+// of each challenge cannot be multiplied by signing in again.
+func (s *authService) VerifyMFA(ctx context.Context, payload auth.MFAVerifyRequest) (*auth.SignInResponse, error) {
+	challenged, err := s.mfaService.ChallengeUser(ctx, payload.ChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginThrottle.Check(ctx, challenged.Email); err != nil {
+		return nil, err
+	}
+
+	user, recoveryCodes, err := s.mfaService.RedeemChallenge(ctx, payload)
+	if errors.Is(err, ErrInvalidMFACode) {
+		if err := s.loginThrottle.RecordFailure(ctx, challenged.Email, challenged); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.loginThrottle.RecordSuccess(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	res, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	res.RecoveryCodes = recoveryCodes
+	return res, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *models.User) (*auth.SignInResponse, error) {
+	pair, err := s.tokenService.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateLastLogin(ctx, user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return &auth.SignInResponse{TokenPair: pair}, nil
+}
+
+// upgradePasswordHash re-hashes a verified password with the current
+// algorithm and parameters. Failures only delay the upgrade to the next
+// sign-in, so they are logged rather than returned.
+func (s *authService) upgradePasswordHash(ctx context.Context, userID uint, password string) {
+	hash, err := s.passwordHasher.Hash(password)
+	if err == nil {
+		err = s.userRepo.UpdatePasswordHash(ctx, userID, hash)
+	}
+	if err != nil {
+		log.Printf("failed to upgrade password hash for user %d: %v", userID, err)
+	}
+}
+
+func (s *authService) Refresh(ctx context.Context, payload auth.RefreshTokenRequest) (*auth.TokenPair, error) {
+	return s.tokenService.RotateRefreshToken(ctx, payload.RefreshToken)
+}
+
+func (s *authService) Logout(ctx context.Context, payload auth.LogoutRequest) error {
+	return s.tokenService.RevokeRefreshToken(ctx, payload.RefreshToken)
+}
+
+// Authenticate resolves an access token to an active user, their session and
+// their effective permission codes.
+func (s *authService) Authenticate(ctx context.Context, accessToken string) (*principal.Principal, error) {
+	claims, err := s.tokenService.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+	sessionID, err := strconv.ParseUint(claims.SessionID, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	user, err := s.userRepo.FindByIDWithRoles(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidAccessToken
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	if err := s.sessionService.Check(ctx, uint(sessionID), user.ID); err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.authorizer.Permissions(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &principal.Principal{
+		User:        user,
+		SessionID:   uint(sessionID),
+		Permissions: permissions,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/model"
+	"backend/internal/security"
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeLoginThrottle struct {
+	LoginThrottleService
+	blocked   error
+	failures  []string
+	successes []string
+}
+
+func (t *fakeLoginThrottle) Check(context.Context, string) error {
+	return t.blocked
+}
+
+func (t *fakeLoginThrottle) RecordFailure(_ context.Context, email string, _ *models.User) error {
+	t.failures = append(t.failures, email)
+	return nil
+}
+
+func (t *fakeLoginThrottle) RecordSuccess(_ context.Context, email string) error {
+	t.successes = append(t.successes, email)
+	return nil
+}
+
+// fakeMFAService requires a second factor from every user and accepts only
+// code.
+type fakeMFAService struct {
+	MFAService
+	user     *models.User
+	code     string
+	redeemed int
+}
+
+func (s *fakeMFAService) Required(context.Context, *models.User) (bool, error) {
+	return true, nil
+}
+
+func (s *fakeMFAService) CreateChallenge(context.Context, *models.User) (*auth.SignInResponse, error) {
+	return &auth.SignInResponse{MFARequired: true, MFAChallengeToken: "challenge"}, nil
+}
+
+func (s *fakeMFAService) ChallengeUser(context.Context, string) (*models.User, error) {
+	return s.user, nil
+}
+
+func (s *fakeMFAService) RedeemChallenge(_ context.Context, payload auth.MFAVerifyRequest) (*models.User, []string, error) {
+	s.redeemed++
+	if payload.Code != s.code {
+		return nil, nil, ErrInvalidMFACode
+	}
+	return s.user, nil, nil
+}
+
+type fakePasswordHasher struct {
+	security.PasswordHasher
+}
+
+func (fakePasswordHasher) Verify(encoded, password string) (bool, bool) {
+	return encoded == "hash:"+password, false
+}
+
+func (r *fakeUserRepo) FindByEmail(_ context.Context, email string) (*models.User, error) {
+	if r.user.Email != email {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+func newMFAAuthService(user *models.User) (*authService, *fakeMFAService, *fakeLoginThrottle) {
+	mfa := &fakeMFAService{user: user, code: "123456"}
+	throttle := &fakeLoginThrottle{}
+	s := &authService{
+		userRepo:       &fakeUserRepo{user: user},
+		passwordHasher: fakePasswordHasher{},
+		mfaService:     mfa,
+		loginThrottle:  throttle,
+	}
+	return s, mfa, throttle
+}
+
+func TestSignInKeepsFailuresUntilSecondFactor(t *testing.T) {
+	user := &models.User{Email: "officer@dept.gov", PasswordHash: "hash:secret", IsActive: true}
+	s, _, throttle := newMFAAuthService(user)
+
+	res, err := s.SignIn(context.Background(), auth.SignInRequest{Email: user.Email, Password: "secret"})
+	if err != nil {
+		t.Fatalf("SignIn: %v", err)
+	}
+	if !res.MFARequired {
+		t.Fatal("SignIn did not ask for a second factor")
+	}
+	if len(throttle.successes) != 0 {
+		t.Error("a correct password cleared the failures before the second factor passed")
+	}
+}
+
+func TestVerifyMFACountsWrongCodes(t *testing.T) {
+	user := &models.User{Email: "officer@dept.gov", IsActive: true}
+	s, mfa, throttle := newMFAAuthService(user)
+	ctx := context.Background()
+
+	_, err := s.VerifyMFA(ctx, auth.MFAVerifyRequest{ChallengeToken: "challenge", Code: "000000"})
+	if !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("wrong code: got %v, want %v", err, ErrInvalidMFACode)
+	}
+	if len(throttle.failures) != 1 || throttle.failures[0] != user.Email {
+		t.Errorf("wrong code recorded failures %v, want one for %s", throttle.failures, user.Email)
+	}
+
+	throttle.blocked = &LoginThrottleError{Err: ErrAccountLocked}
+	_, err = s.VerifyMFA(ctx, auth.MFAVerifyRequest{ChallengeToken: "challenge", Code: mfa.code})
+	if !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("locked account: got %v, want %v", err, ErrAccountLocked)
+	}
+	if mfa.redeemed != 1 {
+		t.Errorf("challenge was redeemed %d times, want 1: a locked account must not get to try a code", mfa.redeemed)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"sync"
+	"time"
+)
+
+// Authorizer resolves the permission codes a user holds through their roles,
+// including permissions inherited through the role hierarchy.
+//
+// Results are cached in-process for a short TTL. Anything that changes role
+// grants or role assignments must call InvalidateAll or InvalidateUser so the
+// change takes effect on the next request.
+type Authorizer interface {
+	Permissions(ctx context.Context, userID uint) (map[string]struct{}, error)
+	HasPermission(ctx context.Context, userID uint, code string) (bool, error)
+	Require(ctx context.Context, code string) error
+	InvalidateUser(userID uint)
+	InvalidateAll()
+}
+
+type permissionCacheEntry struct {
+	codes     map[string]struct{}
+	expiresAt time.Time
+}
+
+type authorizer struct {
+	userRoleRepo   repository.UserRoleRepository
+	roleRepo       repository.RoleRepository
+	permissionRepo repository.PermissionRepository
+	ttl            time.Duration
+
+	mu         sync.RWMutex
+	entries    map[uint]permissionCacheEntry
+	generation uint64
+}
+
+func NewAuthorizer(
+	userRoleRepo repository.UserRoleRepository,
+	roleRepo repository.RoleRepository,
+	permissionRepo repository.PermissionRepository,
+	ttl time.Duration,
+) Authorizer {
+	return &authorizer{
+		userRoleRepo:   userRoleRepo,
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		ttl:            ttl,
+		entries:        make(map[uint]permissionCacheEntry),
+	}
+}
+
+func (a *authorizer) Permissions(ctx context.Context, userID uint) (map[string]struct{}, error) {
+	a.mu.RLock()
+	entry, ok := a.entries[userID]
+	generation := a.generation
+	a.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.codes, nil
+	}
+
+	list, err := a.resolve(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	codes := make(map[string]struct{}, len(list))
+	for _, code := range list {
+		codes[code] = struct{}{}
+	}
+
+	a.mu.Lock()
+	// Skip caching if an invalidation ran while we were loading; the result
+	// may already be stale.
+	if a.generation == generation {
+		a.entries[userID] = permissionCacheEntry{codes: codes, expiresAt: time.Now().Add(a.ttl)}
+	}
+	a.mu.Unlock()
+
+	return codes, nil
+}
+
+func (a *authorizer) resolve(ctx context.Context, userID uint) ([]string, error) {
+	roleIDs, err := a.userRoleRepo.FindRoleIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+
+	roles, err := a.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.permissionRepo.FindCodesByRoleIDs(ctx, newRoleHierarchy(roles).expand(roleIDs))
+}
+
+func (a *authorizer) HasPermission(ctx context.Context, userID uint, code string) (bool, error) {
+	codes, err := a.Permissions(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	_, ok := codes[code]
+	return ok, nil
+}
+
+// Require checks that the principal on ctx holds the permission code.
+func (a *authorizer) Require(ctx context.Context, code string) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	allowed, err := a.HasPermission(ctx, p.UserID(), code)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (a *authorizer) InvalidateUser(userID uint) {
+	a.mu.Lock()
+	delete(a.entries, userID)
+	a.generation++
+	a.mu.Unlock()
+}
+
+func (a *authorizer) InvalidateAll() {
+	a.mu.Lock()
+	a.entries = make(map[uint]permissionCacheEntry)
+	a.generation++
+	a.mu.Unlock()
+}
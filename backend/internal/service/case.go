@@ -0,0 +1,545 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// caseStatusAuditDetails is the Details payload of
+// models.AuditCaseStatusChanged entries.
+type caseStatusAuditDetails struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Transition string `json:"transition"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// caseSortColumns maps the sort fields of the case search to columns.
+var caseSortColumns = map[string]string{
+	"case_number":   "case_number",
+	"title":         "title",
+	"status":        "status",
+	"priority":      "priority",
+	"incident_date": "incident_date",
+	"created_at":    "created_at",
+	"updated_at":    "updated_at",
+}
+
+type CaseService interface {
+	ListCases(ctx context.Context, query cases.ListCasesQuery) (*cases.CaseListResponse, error)
+	GetCase(ctx context.Context, caseID uint) (*cases.CaseDetailResponse, error)
+	CreateCase(ctx context.Context, payload cases.CreateCaseRequest) (*cases.CaseDetailResponse, error)
+	UpdateCase(ctx context.Context, caseID uint, payload cases.UpdateCaseRequest) (*cases.CaseDetailResponse, error)
+	DeleteCase(ctx context.Context, caseID uint) error
+	ChangeStatus(ctx context.Context, caseID uint, payload cases.ChangeCaseStatusRequest) (*cases.CaseDetailResponse, error)
+	AvailableTransitions(ctx context.Context, caseID uint) ([]cases.AvailableTransitionResponse, error)
+}
+
+type caseService struct {
+	caseRepo          repository.CaseRepository
+	workflowRepo      repository.CaseWorkflowRepository
+	auditRepo         repository.AuditLogRepository
+	txManager         repository.TransactionManager
+	caseNumberService CaseNumberService
+}
+
+func NewCaseService(
+	caseRepo repository.CaseRepository,
+	workflowRepo repository.CaseWorkflowRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	caseNumberService CaseNumberService,
+) CaseService {
+	return &caseService{
+		caseRepo:          caseRepo,
+		workflowRepo:      workflowRepo,
+		auditRepo:         auditRepo,
+		txManager:         txManager,
+		caseNumberService: caseNumberService,
+	}
+}
+
+// ListCases searches the cases the caller may see and returns one page of
+// them with totals.
+func (s *caseService) ListCases(ctx context.Context, query cases.ListCasesQuery) (*cases.CaseListResponse, error) {
+	sort, err := parseCaseSort(query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+
+	listQuery := repository.CaseListQuery{
+		Search:       query.Search,
+		TagIDs:       uniqueIDs(query.TagIDs),
+		MatchAllTags: query.TagMatch == "all",
+		OfficerID:    query.OfficerID,
+		DepartmentID: query.DepartmentID,
+		IncidentFrom: query.IncidentFrom,
+		Sort:         sort,
+		Offset:       (page - 1) * limit,
+		Limit:        limit,
+	}
+	for _, status := range query.Status {
+		if status = strings.TrimSpace(status); status != "" {
+			listQuery.Statuses = append(listQuery.Statuses, status)
+		}
+	}
+	for _, value := range query.Priority {
+		priority, err := normalizeCasePriority(value, "")
+		if err != nil {
+			return nil, err
+		}
+		listQuery.Priorities = append(listQuery.Priorities, priority)
+	}
+	if query.IncidentTo != nil {
+		if query.IncidentFrom != nil && query.IncidentTo.Before(*query.IncidentFrom) {
+			return nil, ErrInvalidDateRange
+		}
+		before := query.IncidentTo.AddDate(0, 0, 1)
+		listQuery.IncidentBefore = &before
+	}
+
+	list, err := s.caseRepo.List(ctx, listQuery)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.caseRepo.Count(ctx, listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &cases.CaseListResponse{
+		Items:      make([]cases.CaseResponse, 0, len(list)),
+		Pagination: offsetMeta(limit, page, total),
+	}
+	for i := range list {
+		res.Items = append(res.Items, toCaseResponse(&list[i]))
+	}
+	return res, nil
+}
+
+// GetCase returns the case with its officers, tags and the evidence the
+// caller may view. Cases the caller may not see are reported as not found.
+func (s *caseService) GetCase(ctx context.Context, caseID uint) (*cases.CaseDetailResponse, error) {
+	return s.loadDetail(ctx, caseID)
+}
+
+func (s *caseService) CreateCase(ctx context.Context, payload cases.CreateCaseRequest) (*cases.CaseDetailResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	priority, err := normalizeCasePriority(payload.Priority, models.CasePriorityMedium)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &models.Case{
+		Title:        strings.TrimSpace(payload.Title),
+		Description:  payload.Description,
+		Location:     payload.Location,
+		IncidentDate: payload.IncidentDate,
+		Priority:     priority,
+		CreatedByID:  p.UserIDRef(),
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		initial, err := s.workflowRepo.FindInitialStatus(ctx)
+		if err != nil {
+			return err
+		}
+		if initial == nil {
+			return ErrNoInitialStatus
+		}
+		created.Status = initial.Name
+
+		number, err := s.caseNumberService.Generate(ctx, payload.CaseType, p.User.DepartmentID)
+		if err != nil {
+			return err
+		}
+		created.CaseNumber = number
+
+		if err := s.caseRepo.Create(ctx, created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return ErrCaseNumberTaken
+			}
+			return err
+		}
+		return s.recordChange(ctx, created.ID, models.AuditCaseCreated, nil, toCaseResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, created.ID)
+}
+
+func (s *caseService) UpdateCase(ctx context.Context, caseID uint, payload cases.UpdateCaseRequest) (*cases.CaseDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.caseRepo.FindByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseNotFound
+		}
+		previous := toCaseResponse(target)
+
+		if payload.Title != nil {
+			target.Title = strings.TrimSpace(*payload.Title)
+		}
+		if payload.Description != nil {
+			target.Description = *payload.Description
+		}
+		if payload.Location != nil {
+			target.Location = *payload.Location
+		}
+		if payload.IncidentDate != nil {
+			target.IncidentDate = payload.IncidentDate
+		}
+		if payload.Priority != nil {
+			if target.Priority, err = normalizeCasePriority(*payload.Priority, ""); err != nil {
+				return err
+			}
+		}
+
+		if err := s.caseRepo.Update(ctx, target); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, target.ID, models.AuditCaseUpdated, previous, toCaseResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, caseID)
+}
+
+// DeleteCase soft-deletes the case. Its case number stays reserved.
+func (s *caseService) DeleteCase(ctx context.Context, caseID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.caseRepo.FindByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseNotFound
+		}
+
+		if err := s.caseRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, target.ID, models.AuditCaseDeleted, toCaseResponse(target), nil)
+	})
+}
+
+// ChangeStatus moves the case along a transition of the workflow. The
+// transition's permission and reason requirements apply, and entering or
+// leaving a closing status sets or clears the closure fields.
+func (s *caseService) ChangeStatus(ctx context.Context, caseID uint, payload cases.ChangeCaseStatusRequest) (*cases.CaseDetailResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.caseRepo.FindByIDForUpdate(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseNotFound
+		}
+
+		from, err := s.workflowRepo.FindStatusByName(ctx, target.Status)
+		if err != nil {
+			return err
+		}
+		to, err := s.workflowRepo.FindStatusByName(ctx, strings.TrimSpace(payload.Status))
+		if err != nil {
+			return err
+		}
+		if to == nil {
+			return fmt.Errorf("%w: %q is not a status of the workflow", ErrInvalidCaseStatus, payload.Status)
+		}
+		if from == nil {
+			return fmt.Errorf("%w: the current status %q is no longer part of the workflow", ErrTransitionNotAllowed, target.Status)
+		}
+
+		transition, err := s.workflowRepo.FindTransition(ctx, from.ID, to.ID)
+		if err != nil {
+			return err
+		}
+		if transition == nil {
+			return fmt.Errorf("%w: %s to %s", ErrTransitionNotAllowed, from.Name, to.Name)
+		}
+		if transition.RequiredPermission != "" && !p.HasPermission(transition.RequiredPermission) {
+			return ErrForbidden
+		}
+		reason := strings.TrimSpace(payload.Reason)
+		if transition.RequiresReason && reason == "" {
+			return ErrReasonRequired
+		}
+
+		target.Status = to.Name
+		switch {
+		case to.IsClosed:
+			now := time.Now()
+			target.ClosedAt = &now
+			target.ClosedByID = p.UserIDRef()
+			target.ClosureReason = reason
+		case from.IsClosed:
+			target.ClosedAt = nil
+			target.ClosedByID = nil
+			target.ClosureReason = ""
+		}
+
+		if err := s.caseRepo.UpdateStatus(ctx, target); err != nil {
+			return err
+		}
+		return s.recordStatusChange(ctx, target.ID, caseStatusAuditDetails{
+			From:       from.Name,
+			To:         to.Name,
+			Transition: transition.Name,
+			Reason:     reason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, caseID)
+}
+
+// AvailableTransitions lists the transitions out of the case's current
+// status that the caller holds the permission for.
+func (s *caseService) AvailableTransitions(ctx context.Context, caseID uint) ([]cases.AvailableTransitionResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	target, err := s.caseRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	res := []cases.AvailableTransitionResponse{}
+	from, err := s.workflowRepo.FindStatusByName(ctx, target.Status)
+	if err != nil || from == nil {
+		return res, err
+	}
+	transitions, err := s.workflowRepo.FindTransitionsFrom(ctx, from.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range transitions {
+		if t.ToStatus == nil {
+			continue
+		}
+		if t.RequiredPermission != "" && !p.HasPermission(t.RequiredPermission) {
+			continue
+		}
+		res = append(res, cases.AvailableTransitionResponse{
+			ID:             t.ID,
+			Name:           t.Name,
+			ToStatus:       cases.StatusSummary{ID: t.ToStatus.ID, Name: t.ToStatus.Name},
+			RequiresReason: t.RequiresReason,
+		})
+	}
+	return res, nil
+}
+
+func (s *caseService) recordChange(ctx context.Context, caseID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, "case", &caseID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *caseService) recordStatusChange(ctx context.Context, caseID uint, details caseStatusAuditDetails) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	entry, err := newAuditLog(ctx, &actorID, models.AuditCaseStatusChanged, "case", &caseID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *caseService) loadDetail(ctx context.Context, caseID uint) (*cases.CaseDetailResponse, error) {
+	c, err := s.caseRepo.FindByIDWithDetails(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	var showEvidence, showConfidential bool
+	if p, ok := principal.FromContext(ctx); ok {
+		showEvidence = p.HasPermission(PermissionViewEvidence)
+		showConfidential = showEvidence && p.HasPermission(PermissionViewConfidentialEvidence)
+	}
+	return toCaseDetailResponse(c, showEvidence, showConfidential), nil
+}
+
+// parseCaseSort reads a comma-separated list of sort fields, newest cases
+// first when empty.
+func parseCaseSort(value string) ([]repository.CaseSort, error) {
+	if strings.TrimSpace(value) == "" {
+		value = "-created_at"
+	}
+
+	var sort []repository.CaseSort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		field, desc := parseSort(strings.TrimSpace(part), "")
+		column, ok := caseSortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidSort, field)
+		}
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+		sort = append(sort, repository.CaseSort{Column: column, Desc: desc})
+	}
+	return sort, nil
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each.
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// normalizeCasePriority returns the canonical spelling of value, or
+// fallback when value is empty.
+func normalizeCasePriority(value, fallback string) (string, error) {
+	return normalizeCaseValue(value, fallback, models.CasePriorities, ErrInvalidCasePriority)
+}
+
+func normalizeCaseValue(value, fallback string, allowed []string, invalid error) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" && fallback != "" {
+		return fallback, nil
+	}
+	for _, v := range allowed {
+		if strings.EqualFold(v, value) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("%w: must be one of %s", invalid, strings.Join(allowed, ", "))
+}
+
+func toCaseResponse(c *models.Case) cases.CaseResponse {
+	return cases.CaseResponse{
+		ID:            c.ID,
+		CaseNumber:    c.CaseNumber,
+		Title:         c.Title,
+		Description:   c.Description,
+		Location:      c.Location,
+		IncidentDate:  c.IncidentDate,
+		Status:        c.Status,
+		Priority:      c.Priority,
+		CreatedBy:     toCaseUserSummary(c.CreatedBy),
+		ClosedAt:      c.ClosedAt,
+		ClosedBy:      toCaseUserSummary(c.ClosedBy),
+		ClosureReason: c.ClosureReason,
+		CreatedAt:     c.CreatedAt,
+		UpdatedAt:     c.UpdatedAt,
+	}
+}
+
+// toCaseDetailResponse lists the case's evidence only when showEvidence is
+// set, and its confidential evidence only when showConfidential is set too.
+func toCaseDetailResponse(c *models.Case, showEvidence, showConfidential bool) *cases.CaseDetailResponse {
+	res := &cases.CaseDetailResponse{
+		CaseResponse: toCaseResponse(c),
+		Officers:     make([]cases.OfficerSummary, 0, len(c.Officers)),
+		Tags:         make([]cases.TagSummary, 0, len(c.Tags)),
+		Evidence:     make([]cases.EvidenceSummary, 0, len(c.Evidences)),
+	}
+	for _, o := range c.Officers {
+		if o.Officer == nil {
+			continue
+		}
+		res.Officers = append(res.Officers, cases.OfficerSummary{
+			UserSummary: *toCaseUserSummary(o.Officer),
+			Role:        o.Role,
+			Notes:       o.Notes,
+			AssignedAt:  o.CreatedAt,
+		})
+	}
+	for _, t := range c.Tags {
+		if t.Tag == nil {
+			continue
+		}
+		res.Tags = append(res.Tags, cases.TagSummary{ID: t.Tag.ID, Name: t.Tag.Name, Color: t.Tag.Color})
+	}
+	for _, e := range c.Evidences {
+		if !showEvidence || (e.IsConfidential && !showConfidential) {
+			continue
+		}
+		res.Evidence = append(res.Evidence, cases.EvidenceSummary{
+			ID:             e.ID,
+			Title:          e.Title,
+			FileType:       e.FileType,
+			FileSize:       e.FileSize,
+			IsConfidential: e.IsConfidential,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+	return res
+}
+
+func toCaseUserSummary(u *models.User) *cases.UserSummary {
+	if u == nil {
+		return nil
+	}
+	return &cases.UserSummary{
+		ID:          u.ID,
+		FirstName:   u.FirstName,
+		LastName:    u.LastName,
+		BadgeNumber: u.BadgeNumber,
+	}
+}
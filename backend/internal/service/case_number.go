@@ -0,0 +1,326 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxCaseNumberAttempts bounds how many taken numbers Generate skips, such
+// as numbers entered by hand before the format existed.
+const maxCaseNumberAttempts = 100
+
+// CaseNumberService manages the case number formats and issues numbers
+// from them.
+type CaseNumberService interface {
+	ListFormats(ctx context.Context) ([]cases.CaseNumberFormatResponse, error)
+	CreateFormat(ctx context.Context, payload cases.CaseNumberFormatRequest) (*cases.CaseNumberFormatResponse, error)
+	UpdateFormat(ctx context.Context, formatID uint, payload cases.CaseNumberFormatRequest) (*cases.CaseNumberFormatResponse, error)
+	DeleteFormat(ctx context.Context, formatID uint) error
+	Generate(ctx context.Context, caseType string, departmentID *uint) (string, error)
+}
+
+type caseNumberService struct {
+	formatRepo     repository.CaseNumberFormatRepository
+	caseRepo       repository.CaseRepository
+	departmentRepo repository.DepartmentRepository
+	auditRepo      repository.AuditLogRepository
+	txManager      repository.TransactionManager
+}
+
+func NewCaseNumberService(
+	formatRepo repository.CaseNumberFormatRepository,
+	caseRepo repository.CaseRepository,
+	departmentRepo repository.DepartmentRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+) CaseNumberService {
+	return &caseNumberService{
+		formatRepo:     formatRepo,
+		caseRepo:       caseRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+	}
+}
+
+func (s *caseNumberService) ListFormats(ctx context.Context) ([]cases.CaseNumberFormatResponse, error) {
+	formats, err := s.formatRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.CaseNumberFormatResponse, 0, len(formats))
+	for i := range formats {
+		res = append(res, toCaseNumberFormatResponse(&formats[i]))
+	}
+	return res, nil
+}
+
+func (s *caseNumberService) CreateFormat(ctx context.Context, payload cases.CaseNumberFormatRequest) (*cases.CaseNumberFormatResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	created := &models.CaseNumberFormat{CreatedByID: p.UserIDRef()}
+	applyCaseNumberFormat(created, payload)
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.validateFormat(ctx, created); err != nil {
+			return err
+		}
+		if err := s.formatRepo.Create(ctx, created); err != nil {
+			return err
+		}
+		if created.IsDefault {
+			if err := s.formatRepo.ClearDefault(ctx, created.ID); err != nil {
+				return err
+			}
+		}
+		return s.recordChange(ctx, created.ID, models.AuditCaseNumberFormatCreated, nil, toCaseNumberFormatResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, created.ID)
+}
+
+func (s *caseNumberService) UpdateFormat(ctx context.Context, formatID uint, payload cases.CaseNumberFormatRequest) (*cases.CaseNumberFormatResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.formatRepo.FindByID(ctx, formatID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseNumberFormatNotFound
+		}
+		previous := toCaseNumberFormatResponse(target)
+
+		applyCaseNumberFormat(target, payload)
+		target.Department = nil
+		if err := s.validateFormat(ctx, target); err != nil {
+			return err
+		}
+		if err := s.formatRepo.Update(ctx, target); err != nil {
+			return err
+		}
+		if target.IsDefault {
+			if err := s.formatRepo.ClearDefault(ctx, target.ID); err != nil {
+				return err
+			}
+		}
+		return s.recordChange(ctx, target.ID, models.AuditCaseNumberFormatUpdated, previous, toCaseNumberFormatResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, formatID)
+}
+
+// DeleteFormat soft-deletes the format. Cases keep the numbers they were
+// given.
+func (s *caseNumberService) DeleteFormat(ctx context.Context, formatID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.formatRepo.FindByID(ctx, formatID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseNumberFormatNotFound
+		}
+
+		if err := s.formatRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, target.ID, models.AuditCaseNumberFormatDeleted, toCaseNumberFormatResponse(target), nil)
+	})
+}
+
+// Generate issues the next case number. The format is the one for
+// caseType when given, otherwise the one of departmentID, otherwise the
+// default format. Generate must run inside the transaction that creates
+// the case: the sequence stays locked until it commits, and a rollback
+// returns the number.
+//
+// Numbers already used by a case are skipped, so sequences may have gaps
+// but never collide with numbers entered by hand. Generate gives up with
+// ErrCaseNumberExhausted after maxCaseNumberAttempts taken numbers in a row.
+func (s *caseNumberService) Generate(ctx context.Context, caseType string, departmentID *uint) (string, error) {
+	format, err := s.resolve(ctx, caseType, departmentID)
+	if err != nil {
+		return "", err
+	}
+
+	return s.next(ctx, format, time.Now().Year())
+}
+
+// next issues the next free number of format for year.
+func (s *caseNumberService) next(ctx context.Context, format *models.CaseNumberFormat, year int) (string, error) {
+	for range maxCaseNumberAttempts {
+		n, err := s.formatRepo.NextSequence(ctx, format.ID, sequenceYear(format, year))
+		if err != nil {
+			return "", err
+		}
+		number := formatCaseNumber(format, year, n)
+
+		taken, err := s.caseRepo.ExistsByCaseNumber(ctx, number)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return number, nil
+		}
+	}
+	return "", fmt.Errorf("%w: prefix %s, %d numbers in a row are taken", ErrCaseNumberExhausted, format.Prefix, maxCaseNumberAttempts)
+}
+
+func (s *caseNumberService) resolve(ctx context.Context, caseType string, departmentID *uint) (*models.CaseNumberFormat, error) {
+	if caseType = strings.TrimSpace(caseType); caseType != "" {
+		format, err := s.formatRepo.FindByCaseType(ctx, caseType)
+		if err != nil {
+			return nil, err
+		}
+		if format == nil {
+			return nil, ErrUnknownCaseType
+		}
+		return format, nil
+	}
+
+	if departmentID != nil {
+		format, err := s.formatRepo.FindByDepartmentID(ctx, *departmentID)
+		if err != nil || format != nil {
+			return format, err
+		}
+	}
+
+	format, err := s.formatRepo.FindDefault(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if format == nil {
+		return nil, ErrNoCaseNumberFormat
+	}
+	return format, nil
+}
+
+func (s *caseNumberService) validateFormat(ctx context.Context, format *models.CaseNumberFormat) error {
+	if format.CaseType == "" {
+		return ErrCaseTypeRequired
+	}
+
+	taken, err := s.formatRepo.ExistsByCaseType(ctx, format.CaseType, format.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCaseTypeTaken
+	}
+
+	taken, err = s.formatRepo.ExistsByPrefix(ctx, format.Prefix, format.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCasePrefixTaken
+	}
+
+	if format.DepartmentID == nil {
+		return nil
+	}
+	department, err := s.departmentRepo.FindByID(ctx, *format.DepartmentID)
+	if err != nil {
+		return err
+	}
+	if department == nil {
+		return ErrDepartmentNotFound
+	}
+	taken, err = s.formatRepo.ExistsByDepartmentID(ctx, *format.DepartmentID, format.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrDepartmentHasFormat
+	}
+	return nil
+}
+
+func (s *caseNumberService) recordChange(ctx context.Context, formatID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, "case_number_format", &formatID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *caseNumberService) load(ctx context.Context, formatID uint) (*cases.CaseNumberFormatResponse, error) {
+	format, err := s.formatRepo.FindByID(ctx, formatID)
+	if err != nil {
+		return nil, err
+	}
+	if format == nil {
+		return nil, ErrCaseNumberFormatNotFound
+	}
+	res := toCaseNumberFormatResponse(format)
+	return &res, nil
+}
+
+func applyCaseNumberFormat(format *models.CaseNumberFormat, payload cases.CaseNumberFormatRequest) {
+	format.CaseType = strings.TrimSpace(payload.CaseType)
+	format.Prefix = strings.ToUpper(payload.Prefix)
+	format.Padding = payload.Padding
+	format.IncludeYear = payload.IncludeYear
+	format.DepartmentID = payload.DepartmentID
+	format.IsDefault = payload.IsDefault
+}
+
+// sequenceYear is the sequence a number for year is drawn from. Formats
+// without the year share one sequence, since restarting it would reissue
+// numbers.
+func sequenceYear(format *models.CaseNumberFormat, year int) int {
+	if !format.IncludeYear {
+		return 0
+	}
+	return year
+}
+
+// formatCaseNumber renders PREFIX-YYYY-NNN, or PREFIX-NNN without the year.
+// Sequences that outgrow the padding simply get longer.
+func formatCaseNumber(format *models.CaseNumberFormat, year, n int) string {
+	if format.IncludeYear {
+		return fmt.Sprintf("%s-%d-%0*d", format.Prefix, year, format.Padding, n)
+	}
+	return fmt.Sprintf("%s-%0*d", format.Prefix, format.Padding, n)
+}
+
+func toCaseNumberFormatResponse(format *models.CaseNumberFormat) cases.CaseNumberFormatResponse {
+	res := cases.CaseNumberFormatResponse{
+		ID:          format.ID,
+		CaseType:    format.CaseType,
+		Prefix:      format.Prefix,
+		Padding:     format.Padding,
+		IncludeYear: format.IncludeYear,
+		IsDefault:   format.IsDefault,
+		Example:     formatCaseNumber(format, time.Now().Year(), 1),
+		CreatedAt:   format.CreatedAt,
+		UpdatedAt:   format.UpdatedAt,
+	}
+	if format.Department != nil {
+		res.Department = &cases.DepartmentSummary{ID: format.Department.ID, Name: format.Department.Name}
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFormatCaseNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		format models.CaseNumberFormat
+		year   int
+		n      int
+		want   string
+	}{
+		{"with year", models.CaseNumberFormat{Prefix: "HOM", Padding: 3, IncludeYear: true}, 2025, 7, "HOM-2025-007"},
+		{"without year", models.CaseNumberFormat{Prefix: "CASE", Padding: 4}, 2025, 42, "CASE-0042"},
+		{"no padding", models.CaseNumberFormat{Prefix: "T", IncludeYear: true}, 2026, 5, "T-2026-5"},
+		{"outgrows padding", models.CaseNumberFormat{Prefix: "HOM", Padding: 3, IncludeYear: true}, 2025, 1234, "HOM-2025-1234"},
+	}
+	for _, tt := range tests {
+		if got := formatCaseNumber(&tt.format, tt.year, tt.n); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+type sequenceKey struct {
+	formatID uint
+	year     int
+}
+
+type fakeCaseNumberFormatRepo struct {
+	repository.CaseNumberFormatRepository
+	sequences map[sequenceKey]int
+}
+
+func (r *fakeCaseNumberFormatRepo) NextSequence(_ context.Context, formatID uint, year int) (int, error) {
+	key := sequenceKey{formatID, year}
+	r.sequences[key]++
+	return r.sequences[key], nil
+}
+
+type fakeCaseRepo struct {
+	repository.CaseRepository
+	numbers map[string]bool
+}
+
+func (r *fakeCaseRepo) ExistsByCaseNumber(_ context.Context, caseNumber string) (bool, error) {
+	return r.numbers[caseNumber], nil
+}
+
+func TestCaseNumberSequence(t *testing.T) {
+	yearly := &models.CaseNumberFormat{Prefix: "HOM", Padding: 3, IncludeYear: true}
+	yearly.ID = 1
+	running := &models.CaseNumberFormat{Prefix: "CASE", Padding: 3}
+	running.ID = 2
+
+	s := &caseNumberService{
+		formatRepo: &fakeCaseNumberFormatRepo{sequences: make(map[sequenceKey]int)},
+		caseRepo:   &fakeCaseRepo{numbers: map[string]bool{"HOM-2025-002": true}},
+	}
+	ctx := context.Background()
+
+	steps := []struct {
+		name   string
+		format *models.CaseNumberFormat
+		year   int
+		want   string
+	}{
+		{"first of the year", yearly, 2025, "HOM-2025-001"},
+		{"skips a number entered by hand", yearly, 2025, "HOM-2025-003"},
+		{"restarts in a new year", yearly, 2026, "HOM-2026-001"},
+		{"continues in the new year", yearly, 2026, "HOM-2026-002"},
+		{"format without year", running, 2025, "CASE-001"},
+		{"does not restart without year", running, 2026, "CASE-002"},
+	}
+	for _, step := range steps {
+		got, err := s.next(ctx, step.format, step.year)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got != step.want {
+			t.Errorf("%s: got %q, want %q", step.name, got, step.want)
+		}
+	}
+}
+
+func TestCaseNumberExhausted(t *testing.T) {
+	format := &models.CaseNumberFormat{Prefix: "HOM", Padding: 3}
+	taken := make(map[string]bool)
+	for n := 1; n <= maxCaseNumberAttempts; n++ {
+		taken[formatCaseNumber(format, 2025, n)] = true
+	}
+
+	s := &caseNumberService{
+		formatRepo: &fakeCaseNumberFormatRepo{sequences: make(map[sequenceKey]int)},
+		caseRepo:   &fakeCaseRepo{numbers: taken},
+	}
+	if _, err := s.next(context.Background(), format, 2025); !errors.Is(err, ErrCaseNumberExhausted) {
+		t.Errorf("got %v, want ErrCaseNumberExhausted", err)
+	}
+}
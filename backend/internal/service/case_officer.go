@@ -0,0 +1,291 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/integration/smtp"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+type CaseOfficerConfig struct {
+	CaseURL string // the emailed link is CaseURL/{case id}
+}
+
+// CaseOfficerService assigns officers to cases.
+type CaseOfficerService interface {
+	ListAssignments(ctx context.Context, caseID uint, includeHistory bool) ([]cases.AssignmentResponse, error)
+	AssignOfficer(ctx context.Context, caseID uint, payload cases.AssignOfficerRequest) (*cases.AssignmentResponse, error)
+	UpdateAssignment(ctx context.Context, caseID, officerID uint, payload cases.UpdateAssignmentRequest) (*cases.AssignmentResponse, error)
+	UnassignOfficer(ctx context.Context, caseID, officerID uint) error
+}
+
+type caseOfficerService struct {
+	caseRepo    repository.CaseRepository
+	officerRepo repository.CaseOfficerRepository
+	userRepo    repository.UserRepository
+	auditRepo   repository.AuditLogRepository
+	txManager   repository.TransactionManager
+	mailer      smtp.Mailer
+	config      CaseOfficerConfig
+}
+
+func NewCaseOfficerService(
+	caseRepo repository.CaseRepository,
+	officerRepo repository.CaseOfficerRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	mailer smtp.Mailer,
+	config CaseOfficerConfig,
+) CaseOfficerService {
+	return &caseOfficerService{
+		caseRepo:    caseRepo,
+		officerRepo: officerRepo,
+		userRepo:    userRepo,
+		auditRepo:   auditRepo,
+		txManager:   txManager,
+		mailer:      mailer,
+		config:      config,
+	}
+}
+
+// ListAssignments lists the officers on the case, and with includeHistory
+// also the assignments that have ended.
+func (s *caseOfficerService) ListAssignments(ctx context.Context, caseID uint, includeHistory bool) ([]cases.AssignmentResponse, error) {
+	c, err := s.caseRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	assignments, err := s.officerRepo.FindByCaseID(ctx, caseID, includeHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.AssignmentResponse, 0, len(assignments))
+	for i := range assignments {
+		res = append(res, toAssignmentResponse(&assignments[i]))
+	}
+	return res, nil
+}
+
+// AssignOfficer assigns an active user to the case and emails them. A
+// failed email does not undo the assignment.
+func (s *caseOfficerService) AssignOfficer(ctx context.Context, caseID uint, payload cases.AssignOfficerRequest) (*cases.AssignmentResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var (
+		c       *models.Case
+		officer *models.User
+	)
+	created := &models.CaseOfficer{
+		CaseID:      caseID,
+		OfficerID:   payload.OfficerID,
+		Role:        normalizeOfficerRole(payload.Role),
+		Notes:       payload.Notes,
+		CreatedByID: p.UserIDRef(),
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		c, err = s.caseRepo.FindByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return ErrCaseNotFound
+		}
+
+		officer, err = s.userRepo.FindByID(ctx, payload.OfficerID)
+		if err != nil {
+			return err
+		}
+		if officer == nil {
+			return ErrUserNotFound
+		}
+		if !officer.IsActive {
+			return ErrOfficerInactive
+		}
+
+		existing, err := s.officerRepo.FindActive(ctx, caseID, officer.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrOfficerAlreadyAssigned
+		}
+		if err := s.checkLead(ctx, created); err != nil {
+			return err
+		}
+
+		if err := s.officerRepo.Create(ctx, created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return s.conflict(created)
+			}
+			return err
+		}
+		created.Officer = officer
+		created.CreatedBy = p.User
+		return s.recordChange(ctx, caseID, models.AuditCaseOfficerAssigned, nil, toAssignmentResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	caseURL := fmt.Sprintf("%s/%d", s.config.CaseURL, c.ID)
+	if err := s.mailer.SendCaseAssignmentEmail(officer.Email, officer.FirstName, c.CaseNumber, c.Title, created.Role, caseURL); err != nil {
+		log.Printf("failed to send case assignment email to %s: %v", officer.Email, err)
+	}
+
+	res := toAssignmentResponse(created)
+	return &res, nil
+}
+
+// UpdateAssignment changes the officer's role or notes on the case.
+func (s *caseOfficerService) UpdateAssignment(ctx context.Context, caseID, officerID uint, payload cases.UpdateAssignmentRequest) (*cases.AssignmentResponse, error) {
+	var updated *models.CaseOfficer
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.findAssignment(ctx, caseID, officerID)
+		if err != nil {
+			return err
+		}
+		previous := toAssignmentResponse(target)
+
+		if payload.Role != nil {
+			target.Role = normalizeOfficerRole(*payload.Role)
+		}
+		if payload.Notes != nil {
+			target.Notes = *payload.Notes
+		}
+		if err := s.checkLead(ctx, target); err != nil {
+			return err
+		}
+
+		if err := s.officerRepo.Update(ctx, target); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return s.conflict(target)
+			}
+			return err
+		}
+		updated = target
+		return s.recordChange(ctx, caseID, models.AuditCaseOfficerUpdated, previous, toAssignmentResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toAssignmentResponse(updated)
+	return &res, nil
+}
+
+// UnassignOfficer ends the officer's assignment. The assignment stays in
+// the case's history.
+func (s *caseOfficerService) UnassignOfficer(ctx context.Context, caseID, officerID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.findAssignment(ctx, caseID, officerID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.officerRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, caseID, models.AuditCaseOfficerUnassigned, toAssignmentResponse(target), nil)
+	})
+}
+
+// findAssignment returns the officer's active assignment to a case the
+// caller may see.
+func (s *caseOfficerService) findAssignment(ctx context.Context, caseID, officerID uint) (*models.CaseOfficer, error) {
+	c, err := s.caseRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaseNotFound
+	}
+
+	assignment, err := s.officerRepo.FindActive(ctx, caseID, officerID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil {
+		return nil, ErrOfficerNotAssigned
+	}
+	return assignment, nil
+}
+
+// checkLead rejects a second lead investigator on the case.
+func (s *caseOfficerService) checkLead(ctx context.Context, assignment *models.CaseOfficer) error {
+	if assignment.Role != models.CaseOfficerRoleLead {
+		return nil
+	}
+	lead, err := s.officerRepo.FindLead(ctx, assignment.CaseID)
+	if err != nil {
+		return err
+	}
+	if lead != nil && lead.ID != assignment.ID {
+		return ErrCaseHasLead
+	}
+	return nil
+}
+
+// conflict names the unique index a concurrent assignment violated.
+func (s *caseOfficerService) conflict(assignment *models.CaseOfficer) error {
+	if assignment.Role == models.CaseOfficerRoleLead {
+		return ErrCaseHasLead
+	}
+	return ErrOfficerAlreadyAssigned
+}
+
+func (s *caseOfficerService) recordChange(ctx context.Context, caseID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, "case", &caseID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// normalizeOfficerRole trims role and spells the lead role canonically,
+// which the unique index on lead assignments relies on.
+func normalizeOfficerRole(role string) string {
+	role = strings.TrimSpace(role)
+	if strings.EqualFold(role, models.CaseOfficerRoleLead) {
+		return models.CaseOfficerRoleLead
+	}
+	return role
+}
+
+func toAssignmentResponse(assignment *models.CaseOfficer) cases.AssignmentResponse {
+	res := cases.AssignmentResponse{
+		ID:         assignment.ID,
+		Officer:    toCaseUserSummary(assignment.Officer),
+		Role:       assignment.Role,
+		Notes:      assignment.Notes,
+		AssignedBy: toCaseUserSummary(assignment.CreatedBy),
+		AssignedAt: assignment.CreatedAt,
+	}
+	if assignment.DeletedAt.Valid {
+		res.UnassignedAt = &assignment.DeletedAt.Time
+	}
+	return res
+}
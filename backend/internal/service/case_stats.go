@@ -0,0 +1,333 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStatsInterval = "week"
+	defaultStatsPeriods  = 12
+	maxStatsPeriods      = 366
+	// maxStatsCacheEntries bounds the cache, since every scope and custom
+	// date range gets its own entry.
+	maxStatsCacheEntries = 256
+)
+
+// CaseStatsService aggregates cases for the dashboard.
+//
+// Statistics are cached in-process for a short TTL, per visibility scope
+// and trend window, so changes to cases show up once the entry expires.
+type CaseStatsService interface {
+	GetStats(ctx context.Context, query cases.CaseStatsQuery) (*cases.CaseStatsResponse, error)
+}
+
+type caseStatsCacheEntry struct {
+	stats     *cases.CaseStatsResponse
+	expiresAt time.Time
+}
+
+type caseStatsService struct {
+	caseRepo     repository.CaseRepository
+	workflowRepo repository.CaseWorkflowRepository
+	tagRepo      repository.TagRepository
+	ttl          time.Duration
+
+	mu      sync.Mutex
+	entries map[string]caseStatsCacheEntry
+}
+
+func NewCaseStatsService(
+	caseRepo repository.CaseRepository,
+	workflowRepo repository.CaseWorkflowRepository,
+	tagRepo repository.TagRepository,
+	ttl time.Duration,
+) CaseStatsService {
+	return &caseStatsService{
+		caseRepo:     caseRepo,
+		workflowRepo: workflowRepo,
+		tagRepo:      tagRepo,
+		ttl:          ttl,
+		entries:      make(map[string]caseStatsCacheEntry),
+	}
+}
+
+// GetStats returns the statistics of the cases the caller may see.
+func (s *caseStatsService) GetStats(ctx context.Context, query cases.CaseStatsQuery) (*cases.CaseStatsResponse, error) {
+	interval := query.Interval
+	if interval == "" {
+		interval = defaultStatsInterval
+	}
+
+	to := time.Now().UTC()
+	if query.To != nil {
+		to = *query.To
+	}
+	last := truncatePeriod(to, interval)
+	first := addPeriods(last, interval, 1-defaultStatsPeriods)
+	if query.From != nil {
+		if query.From.After(to) {
+			return nil, ErrInvalidDateRange
+		}
+		first = truncatePeriod(*query.From, interval)
+	}
+	end := addPeriods(last, interval, 1)
+	if addPeriods(first, interval, maxStatsPeriods).Before(end) {
+		return nil, fmt.Errorf("%w: at most %d", ErrStatsRangeTooLong, maxStatsPeriods)
+	}
+
+	key := fmt.Sprintf("%s|%s|%d|%d", repository.CaseVisibilityScope(ctx), interval, first.Unix(), end.Unix())
+	now := time.Now()
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.stats, nil
+	}
+
+	stats, err := s.compute(ctx, interval, first, end)
+	if err != nil {
+		return nil, err
+	}
+	stats.GeneratedAt = now
+
+	if s.ttl > 0 {
+		s.store(key, stats, now)
+	}
+	return stats, nil
+}
+
+// store caches stats under key. Expired entries are dropped first; when the
+// cache is still full, the oldest entry makes room.
+func (s *caseStatsService) store(key string, stats *cases.CaseStatsResponse, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldest string
+	for k, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, k)
+			continue
+		}
+		if oldest == "" || e.expiresAt.Before(s.entries[oldest].expiresAt) {
+			oldest = k
+		}
+	}
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= maxStatsCacheEntries {
+		delete(s.entries, oldest)
+	}
+	s.entries[key] = caseStatsCacheEntry{stats: stats, expiresAt: now.Add(s.ttl)}
+}
+
+func (s *caseStatsService) compute(ctx context.Context, interval string, first, end time.Time) (*cases.CaseStatsResponse, error) {
+	summary, err := s.caseRepo.Summarize(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := &cases.CaseStatsResponse{
+		Total:                  summary.Total,
+		Open:                   summary.Total - summary.Closed,
+		Closed:                 summary.Closed,
+		MeanTimeToCloseSeconds: summary.MeanSecondsToClose,
+	}
+
+	if res.ByStatus, err = s.countByStatus(ctx); err != nil {
+		return nil, err
+	}
+	if res.ByPriority, err = s.countByPriority(ctx); err != nil {
+		return nil, err
+	}
+	if res.ByTag, err = s.countByTag(ctx); err != nil {
+		return nil, err
+	}
+	if res.ByDepartment, err = s.countByDepartment(ctx); err != nil {
+		return nil, err
+	}
+	if res.ByOfficer, err = s.countByOfficer(ctx); err != nil {
+		return nil, err
+	}
+	if res.Trend, err = s.trend(ctx, interval, first, end); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// countByStatus lists every workflow status in workflow order, followed by
+// any status cases still carry that the workflow no longer has.
+func (s *caseStatsService) countByStatus(ctx context.Context) ([]cases.StatusCount, error) {
+	counts, err := s.caseRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := s.workflowRepo.FindStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.StatusCount, 0, len(statuses))
+	for _, status := range statuses {
+		res = append(res, cases.StatusCount{Status: status.Name, IsClosed: status.IsClosed, Count: counts[status.Name]})
+		delete(counts, status.Name)
+	}
+	for _, name := range sortedKeys(counts) {
+		res = append(res, cases.StatusCount{Status: name, Count: counts[name]})
+	}
+	return res, nil
+}
+
+func (s *caseStatsService) countByPriority(ctx context.Context) ([]cases.PriorityCount, error) {
+	counts, err := s.caseRepo.CountByPriority(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.PriorityCount, 0, len(models.CasePriorities))
+	for _, priority := range models.CasePriorities {
+		res = append(res, cases.PriorityCount{Priority: priority, Count: counts[priority]})
+		delete(counts, priority)
+	}
+	for _, priority := range sortedKeys(counts) {
+		res = append(res, cases.PriorityCount{Priority: priority, Count: counts[priority]})
+	}
+	return res, nil
+}
+
+// countByTag lists the tags on at least one visible case, most used first.
+func (s *caseStatsService) countByTag(ctx context.Context) ([]cases.TagCount, error) {
+	counts, err := s.caseRepo.CountByTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.TagCount, 0, len(counts))
+	for i := range tags {
+		if count := counts[tags[i].ID]; count > 0 {
+			res = append(res, cases.TagCount{Tag: toTagSummary(&tags[i]), Count: count})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Count > res[j].Count })
+	return res, nil
+}
+
+func (s *caseStatsService) countByDepartment(ctx context.Context) ([]cases.DepartmentCount, error) {
+	rows, err := s.caseRepo.CountByDepartment(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.DepartmentCount, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, cases.DepartmentCount{DepartmentID: row.DepartmentID, Name: row.DepartmentName, Count: row.Count})
+	}
+	return res, nil
+}
+
+func (s *caseStatsService) countByOfficer(ctx context.Context) ([]cases.OfficerCount, error) {
+	rows, err := s.caseRepo.CountByOfficer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]cases.OfficerCount, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, cases.OfficerCount{
+			Officer: cases.UserSummary{
+				ID:          row.OfficerID,
+				FirstName:   row.FirstName,
+				LastName:    row.LastName,
+				BadgeNumber: row.BadgeNumber,
+			},
+			Count: row.Count,
+		})
+	}
+	return res, nil
+}
+
+// trend counts the cases opened and closed in each period from first up to
+// end, carrying the number of open cases forward from the count at first.
+func (s *caseStatsService) trend(ctx context.Context, interval string, first, end time.Time) (cases.CaseTrend, error) {
+	trend := cases.CaseTrend{Interval: interval, Periods: []cases.CaseTrendPeriod{}}
+
+	opened, err := s.caseRepo.CountOpenedByPeriod(ctx, interval, first, end)
+	if err != nil {
+		return trend, err
+	}
+	closed, err := s.caseRepo.CountClosedByPeriod(ctx, interval, first, end)
+	if err != nil {
+		return trend, err
+	}
+	open, err := s.caseRepo.CountOpenAt(ctx, first)
+	if err != nil {
+		return trend, err
+	}
+
+	openedByPeriod := periodCounts(opened)
+	closedByPeriod := periodCounts(closed)
+	for start := first; start.Before(end); start = addPeriods(start, interval, 1) {
+		o := openedByPeriod[start.Unix()]
+		c := closedByPeriod[start.Unix()]
+		open += o.Count - c.Count
+		trend.Periods = append(trend.Periods, cases.CaseTrendPeriod{
+			Start:                  start,
+			End:                    addPeriods(start, interval, 1),
+			Opened:                 o.Count,
+			Closed:                 c.Count,
+			OpenAtEnd:              open,
+			MeanTimeToCloseSeconds: c.MeanSecondsToClose,
+		})
+	}
+	return trend, nil
+}
+
+func periodCounts(rows []repository.CasePeriodCount) map[int64]repository.CasePeriodCount {
+	counts := make(map[int64]repository.CasePeriodCount, len(rows))
+	for _, row := range rows {
+		counts[row.Period.Unix()] = row
+	}
+	return counts
+}
+
+// truncatePeriod returns the start of the UTC day, ISO week or month that
+// contains t, matching PostgreSQL's date_trunc.
+func truncatePeriod(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case "week":
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case "month":
+		return day.AddDate(0, 0, 1-day.Day())
+	default:
+		return day
+	}
+}
+
+func addPeriods(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case "week":
+		return t.AddDate(0, 0, 7*n)
+	case "month":
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+func sortedKeys(m map[string]int64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
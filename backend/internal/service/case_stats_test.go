@@ -0,0 +1,79 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTruncatePeriod(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+
+	// The expected values are what Postgres date_trunc returns for the same
+	// instant in UTC; weeks start on Monday.
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval string
+		want     time.Time
+	}{
+		{"day", time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC), "day", time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"day in UTC", time.Date(2026, 3, 4, 0, 30, 0, 0, berlin), "day", time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"week from Wednesday", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), "week", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"week from Monday", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "week", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"week from Sunday", time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), "week", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"week across a year", time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC), "week", time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2026, 2, 28, 23, 0, 0, 0, time.UTC), "month", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"month in UTC", time.Date(2026, 3, 1, 0, 30, 0, 0, berlin), "month", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := truncatePeriod(tt.t, tt.interval); !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddPeriods(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval string
+		n        int
+		want     time.Time
+	}{
+		{"days", time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC), "day", 3, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{"weeks", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "week", 2, time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"weeks back", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), "week", -11, time.Date(2025, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{"months", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "month", 1, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"months back across a year", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), "month", -11, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := addPeriods(tt.t, tt.interval, tt.n); !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCaseStatsCacheIsBounded(t *testing.T) {
+	s := &caseStatsService{ttl: time.Minute, entries: make(map[string]caseStatsCacheEntry)}
+	start := time.Now()
+
+	for i := 0; i <= maxStatsCacheEntries; i++ {
+		s.store(strconv.Itoa(i), &cases.CaseStatsResponse{}, start.Add(time.Duration(i)*time.Millisecond))
+	}
+	if len(s.entries) != maxStatsCacheEntries {
+		t.Fatalf("cache holds %d entries, want %d", len(s.entries), maxStatsCacheEntries)
+	}
+	if _, ok := s.entries["0"]; ok {
+		t.Error("the oldest entry was not evicted")
+	}
+	if _, ok := s.entries[strconv.Itoa(maxStatsCacheEntries)]; !ok {
+		t.Error("the newest entry is missing")
+	}
+
+	s.store("fresh", &cases.CaseStatsResponse{}, start.Add(2*time.Minute))
+	if len(s.entries) != 1 {
+		t.Errorf("cache holds %d entries after they expired, want 1", len(s.entries))
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestToCaseDetailResponseFiltersEvidence(t *testing.T) {
+	c := &models.Case{Evidences: []*models.Evidence{
+		{Title: "photo"},
+		{Title: "informant statement", IsConfidential: true},
+	}}
+
+	tests := []struct {
+		name             string
+		showEvidence     bool
+		showConfidential bool
+		want             []string
+	}{
+		{"no evidence permission", false, false, nil},
+		{"confidential without evidence permission", false, true, nil},
+		{"evidence only", true, false, []string{"photo"}},
+		{"evidence and confidential", true, true, []string{"photo", "informant statement"}},
+	}
+	for _, tt := range tests {
+		res := toCaseDetailResponse(c, tt.showEvidence, tt.showConfidential)
+
+		var got []string
+		for _, e := range res.Evidence {
+			got = append(got, e.Title)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestParseCaseSort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []repository.CaseSort
+		wantErr error
+	}{
+		{"", []repository.CaseSort{{Column: "created_at", Desc: true}}, nil},
+		{"  ", []repository.CaseSort{{Column: "created_at", Desc: true}}, nil},
+		{"title", []repository.CaseSort{{Column: "title"}}, nil},
+		{"-priority,created_at", []repository.CaseSort{{Column: "priority", Desc: true}, {Column: "created_at"}}, nil},
+		{" -priority , title ", []repository.CaseSort{{Column: "priority", Desc: true}, {Column: "title"}}, nil},
+		{"status,-status,title", []repository.CaseSort{{Column: "status"}, {Column: "title"}}, nil},
+		{"password", nil, ErrInvalidSort},
+		{"title,", nil, ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		got, err := parseCaseSort(tt.value)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("parseCaseSort(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCaseSort(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
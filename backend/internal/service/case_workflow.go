@@ -0,0 +1,386 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"strings"
+)
+
+// CaseWorkflowService manages the case statuses and the transitions
+// allowed between them. The workflow lives in the database, so it can be
+// changed without a release.
+type CaseWorkflowService interface {
+	GetWorkflow(ctx context.Context) (*cases.WorkflowResponse, error)
+	CreateStatus(ctx context.Context, payload cases.CaseStatusRequest) (*cases.CaseStatusResponse, error)
+	UpdateStatus(ctx context.Context, statusID uint, payload cases.CaseStatusRequest) (*cases.CaseStatusResponse, error)
+	DeleteStatus(ctx context.Context, statusID uint) error
+	CreateTransition(ctx context.Context, payload cases.CaseTransitionRequest) (*cases.CaseTransitionResponse, error)
+	UpdateTransition(ctx context.Context, transitionID uint, payload cases.CaseTransitionRequest) (*cases.CaseTransitionResponse, error)
+	DeleteTransition(ctx context.Context, transitionID uint) error
+}
+
+type caseWorkflowService struct {
+	workflowRepo   repository.CaseWorkflowRepository
+	permissionRepo repository.PermissionRepository
+	auditRepo      repository.AuditLogRepository
+	txManager      repository.TransactionManager
+}
+
+func NewCaseWorkflowService(
+	workflowRepo repository.CaseWorkflowRepository,
+	permissionRepo repository.PermissionRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+) CaseWorkflowService {
+	return &caseWorkflowService{
+		workflowRepo:   workflowRepo,
+		permissionRepo: permissionRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+	}
+}
+
+func (s *caseWorkflowService) GetWorkflow(ctx context.Context) (*cases.WorkflowResponse, error) {
+	statuses, err := s.workflowRepo.FindStatuses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.workflowRepo.FindTransitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &cases.WorkflowResponse{
+		Statuses:    make([]cases.CaseStatusResponse, 0, len(statuses)),
+		Transitions: make([]cases.CaseTransitionResponse, 0, len(transitions)),
+	}
+	for i := range statuses {
+		res.Statuses = append(res.Statuses, toCaseStatusResponse(&statuses[i]))
+	}
+	for i := range transitions {
+		res.Transitions = append(res.Transitions, toCaseTransitionResponse(&transitions[i]))
+	}
+	return res, nil
+}
+
+func (s *caseWorkflowService) CreateStatus(ctx context.Context, payload cases.CaseStatusRequest) (*cases.CaseStatusResponse, error) {
+	created := &models.CaseStatus{}
+	applyCaseStatus(created, payload)
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.validateStatus(ctx, created); err != nil {
+			return err
+		}
+		if created.IsInitial {
+			if err := s.workflowRepo.ClearInitial(ctx, 0); err != nil {
+				return err
+			}
+		}
+		if err := s.workflowRepo.CreateStatus(ctx, created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return statusConflict(created)
+			}
+			return err
+		}
+		return s.recordChange(ctx, "case_status", created.ID, models.AuditCaseStatusCreated, nil, toCaseStatusResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toCaseStatusResponse(created)
+	return &res, nil
+}
+
+// UpdateStatus replaces the status settings. A new name is carried over to
+// the cases in the status. Whether the status closes cases cannot change
+// while cases are in it, since their closed_at would no longer match.
+func (s *caseWorkflowService) UpdateStatus(ctx context.Context, statusID uint, payload cases.CaseStatusRequest) (*cases.CaseStatusResponse, error) {
+	var updated *models.CaseStatus
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.workflowRepo.FindStatusByID(ctx, statusID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseStatusNotFound
+		}
+		previous := toCaseStatusResponse(target)
+		previousName := target.Name
+
+		applyCaseStatus(target, payload)
+		if err := s.validateStatus(ctx, target); err != nil {
+			return err
+		}
+		if target.IsClosed != previous.IsClosed {
+			count, err := s.workflowRepo.CountCasesWithStatus(ctx, previousName)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrClosingStatusInUse
+			}
+		}
+		if target.IsInitial {
+			if err := s.workflowRepo.ClearInitial(ctx, target.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.workflowRepo.UpdateStatus(ctx, target); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return statusConflict(target)
+			}
+			return err
+		}
+		if target.Name != previousName {
+			if err := s.workflowRepo.RenameCaseStatus(ctx, previousName, target.Name); err != nil {
+				return err
+			}
+		}
+		updated = target
+		return s.recordChange(ctx, "case_status", target.ID, models.AuditCaseStatusUpdated, previous, toCaseStatusResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toCaseStatusResponse(updated)
+	return &res, nil
+}
+
+// DeleteStatus soft-deletes the status and the transitions into and out of
+// it. Statuses that cases are in cannot be deleted.
+func (s *caseWorkflowService) DeleteStatus(ctx context.Context, statusID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.workflowRepo.FindStatusByID(ctx, statusID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseStatusNotFound
+		}
+
+		count, err := s.workflowRepo.CountCasesWithStatus(ctx, target.Name)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCaseStatusInUse
+		}
+
+		if err := s.workflowRepo.DeleteTransitionsByStatus(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.workflowRepo.DeleteStatus(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "case_status", target.ID, models.AuditCaseStatusDeleted, toCaseStatusResponse(target), nil)
+	})
+}
+
+func (s *caseWorkflowService) CreateTransition(ctx context.Context, payload cases.CaseTransitionRequest) (*cases.CaseTransitionResponse, error) {
+	created := &models.CaseTransition{}
+	applyCaseTransition(created, payload)
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.validateTransition(ctx, created); err != nil {
+			return err
+		}
+		if err := s.workflowRepo.CreateTransition(ctx, created); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "case_transition", created.ID, models.AuditCaseTransitionCreated, nil, toCaseTransitionResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadTransition(ctx, created.ID)
+}
+
+func (s *caseWorkflowService) UpdateTransition(ctx context.Context, transitionID uint, payload cases.CaseTransitionRequest) (*cases.CaseTransitionResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.workflowRepo.FindTransitionByID(ctx, transitionID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseTransitionNotFound
+		}
+		previous := toCaseTransitionResponse(target)
+
+		applyCaseTransition(target, payload)
+		if err := s.validateTransition(ctx, target); err != nil {
+			return err
+		}
+		if err := s.workflowRepo.UpdateTransition(ctx, target); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "case_transition", target.ID, models.AuditCaseTransitionUpdated, previous, toCaseTransitionResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadTransition(ctx, transitionID)
+}
+
+func (s *caseWorkflowService) DeleteTransition(ctx context.Context, transitionID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.workflowRepo.FindTransitionByID(ctx, transitionID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrCaseTransitionNotFound
+		}
+
+		if err := s.workflowRepo.DeleteTransition(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "case_transition", target.ID, models.AuditCaseTransitionDeleted, toCaseTransitionResponse(target), nil)
+	})
+}
+
+func (s *caseWorkflowService) validateStatus(ctx context.Context, status *models.CaseStatus) error {
+	taken, err := s.workflowRepo.ExistsStatusByName(ctx, status.Name, status.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCaseStatusNameTaken
+	}
+	return nil
+}
+
+// statusConflict explains a unique index violation on status. The name was
+// checked beforehand, so it comes from a concurrent change; for an initial
+// status that is most likely another status becoming initial.
+func statusConflict(status *models.CaseStatus) error {
+	if status.IsInitial {
+		return ErrInitialStatusConflict
+	}
+	return ErrCaseStatusNameTaken
+}
+
+// validateTransition checks that both statuses exist and differ, that no
+// other transition joins them and that the required permission exists. It
+// loads the statuses into transition for the audit entry.
+func (s *caseWorkflowService) validateTransition(ctx context.Context, transition *models.CaseTransition) error {
+	if transition.FromStatusID == transition.ToStatusID {
+		return ErrInvalidTransition
+	}
+	from, err := s.workflowRepo.FindStatusByID(ctx, transition.FromStatusID)
+	if err != nil {
+		return err
+	}
+	to, err := s.workflowRepo.FindStatusByID(ctx, transition.ToStatusID)
+	if err != nil {
+		return err
+	}
+	if from == nil || to == nil {
+		return ErrCaseStatusNotFound
+	}
+	transition.FromStatus = from
+	transition.ToStatus = to
+
+	taken, err := s.workflowRepo.ExistsTransition(ctx, transition.FromStatusID, transition.ToStatusID, transition.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCaseTransitionExists
+	}
+
+	if transition.RequiredPermission == "" {
+		return nil
+	}
+	permission, err := s.permissionRepo.FindByCode(ctx, transition.RequiredPermission)
+	if err != nil {
+		return err
+	}
+	if permission == nil {
+		return ErrPermissionNotFound
+	}
+	return nil
+}
+
+func (s *caseWorkflowService) recordChange(ctx context.Context, entityType string, entityID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, entityType, &entityID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *caseWorkflowService) loadTransition(ctx context.Context, transitionID uint) (*cases.CaseTransitionResponse, error) {
+	transition, err := s.workflowRepo.FindTransitionByID(ctx, transitionID)
+	if err != nil {
+		return nil, err
+	}
+	if transition == nil {
+		return nil, ErrCaseTransitionNotFound
+	}
+	res := toCaseTransitionResponse(transition)
+	return &res, nil
+}
+
+func applyCaseStatus(status *models.CaseStatus, payload cases.CaseStatusRequest) {
+	status.Name = strings.TrimSpace(payload.Name)
+	status.Description = payload.Description
+	status.IsInitial = payload.IsInitial
+	status.IsClosed = payload.IsClosed
+	status.SortOrder = payload.SortOrder
+}
+
+func applyCaseTransition(transition *models.CaseTransition, payload cases.CaseTransitionRequest) {
+	transition.Name = strings.TrimSpace(payload.Name)
+	transition.FromStatusID = payload.FromStatusID
+	transition.ToStatusID = payload.ToStatusID
+	transition.RequiredPermission = strings.TrimSpace(payload.RequiredPermission)
+	transition.RequiresReason = payload.RequiresReason
+}
+
+func toCaseStatusResponse(status *models.CaseStatus) cases.CaseStatusResponse {
+	return cases.CaseStatusResponse{
+		ID:          status.ID,
+		Name:        status.Name,
+		Description: status.Description,
+		IsInitial:   status.IsInitial,
+		IsClosed:    status.IsClosed,
+		SortOrder:   status.SortOrder,
+		CreatedAt:   status.CreatedAt,
+		UpdatedAt:   status.UpdatedAt,
+	}
+}
+
+func toCaseTransitionResponse(transition *models.CaseTransition) cases.CaseTransitionResponse {
+	return cases.CaseTransitionResponse{
+		ID:                 transition.ID,
+		Name:               transition.Name,
+		FromStatus:         toStatusSummary(transition.FromStatus),
+		ToStatus:           toStatusSummary(transition.ToStatus),
+		RequiredPermission: transition.RequiredPermission,
+		RequiresReason:     transition.RequiresReason,
+		CreatedAt:          transition.CreatedAt,
+		UpdatedAt:          transition.UpdatedAt,
+	}
+}
+
+func toStatusSummary(status *models.CaseStatus) *cases.StatusSummary {
+	if status == nil {
+		return nil
+	}
+	return &cases.StatusSummary{ID: status.ID, Name: status.Name}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeCaseWorkflowRepo struct {
+	repository.CaseWorkflowRepository
+	status *models.CaseStatus
+	cases  int64
+}
+
+func (r *fakeCaseWorkflowRepo) FindStatusByID(_ context.Context, id uint) (*models.CaseStatus, error) {
+	if r.status.ID != id {
+		return nil, nil
+	}
+	copied := *r.status
+	return &copied, nil
+}
+
+func (r *fakeCaseWorkflowRepo) ExistsStatusByName(context.Context, string, uint) (bool, error) {
+	return false, nil
+}
+
+func (r *fakeCaseWorkflowRepo) CountCasesWithStatus(context.Context, string) (int64, error) {
+	return r.cases, nil
+}
+
+func (r *fakeCaseWorkflowRepo) UpdateStatus(_ context.Context, status *models.CaseStatus) error {
+	r.status = status
+	return nil
+}
+
+func (r *fakeCaseWorkflowRepo) RenameCaseStatus(context.Context, string, string) error {
+	return nil
+}
+
+func TestUpdateStatusKeepsClosingWhileInUse(t *testing.T) {
+	actor := &models.User{}
+	actor.ID = 1
+	ctx := principal.NewContext(context.Background(), &principal.Principal{User: actor})
+
+	tests := []struct {
+		name     string
+		isClosed bool
+		cases    int64
+		change   bool
+		want     error
+	}{
+		{"close a status in use", false, 3, true, ErrClosingStatusInUse},
+		{"reopen a status in use", true, 3, true, ErrClosingStatusInUse},
+		{"close an unused status", false, 0, true, nil},
+		{"rename a status in use", true, 3, false, nil},
+	}
+	for _, tt := range tests {
+		status := &models.CaseStatus{Name: "Closed", IsClosed: tt.isClosed}
+		status.ID = 4
+		repo := &fakeCaseWorkflowRepo{status: status, cases: tt.cases}
+		s := &caseWorkflowService{workflowRepo: repo, auditRepo: &fakeAuditLogRepo{}, txManager: fakeTxManager{}}
+
+		payload := cases.CaseStatusRequest{Name: "Resolved", IsClosed: tt.isClosed != tt.change}
+		_, err := s.UpdateStatus(ctx, status.ID, payload)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+		if tt.want != nil && repo.status.IsClosed != tt.isClosed {
+			t.Errorf("%s: rejected update still changed is_closed", tt.name)
+		}
+	}
+}
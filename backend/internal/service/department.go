@@ -0,0 +1,281 @@
+package service
+
+import (
+	"backend/internal/dto/department"
+	"backend/internal/dto/user"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+)
+
+type DepartmentService interface {
+	ListDepartments(ctx context.Context) ([]department.DepartmentResponse, error)
+	GetDepartment(ctx context.Context, departmentID uint) (*department.DepartmentDetailResponse, error)
+	CreateDepartment(ctx context.Context, payload department.CreateDepartmentRequest) (*department.DepartmentDetailResponse, error)
+	UpdateDepartment(ctx context.Context, departmentID uint, payload department.UpdateDepartmentRequest) (*department.DepartmentDetailResponse, error)
+	DeleteDepartment(ctx context.Context, departmentID uint) error
+	ListMembers(ctx context.Context, departmentID uint, query user.ListUsersQuery) (*user.UserListResponse, error)
+	SetHead(ctx context.Context, departmentID uint, payload department.SetHeadRequest) (*department.DepartmentDetailResponse, error)
+}
+
+type departmentService struct {
+	departmentRepo repository.DepartmentRepository
+	userRepo       repository.UserRepository
+	auditRepo      repository.AuditLogRepository
+	txManager      repository.TransactionManager
+	userService    UserService
+}
+
+func NewDepartmentService(
+	departmentRepo repository.DepartmentRepository,
+	userRepo repository.UserRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	userService UserService,
+) DepartmentService {
+	return &departmentService{
+		departmentRepo: departmentRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+		userService:    userService,
+	}
+}
+
+func (s *departmentService) ListDepartments(ctx context.Context) ([]department.DepartmentResponse, error) {
+	departments, err := s.departmentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]department.DepartmentResponse, 0, len(departments))
+	for i := range departments {
+		res = append(res, toDepartmentResponse(&departments[i]))
+	}
+	return res, nil
+}
+
+func (s *departmentService) GetDepartment(ctx context.Context, departmentID uint) (*department.DepartmentDetailResponse, error) {
+	return s.loadDetail(ctx, departmentID)
+}
+
+func (s *departmentService) CreateDepartment(ctx context.Context, payload department.CreateDepartmentRequest) (*department.DepartmentDetailResponse, error) {
+	created := &models.Department{
+		Name:                payload.Name,
+		Description:         payload.Description,
+		EmailDomain:         payload.EmailDomain,
+		GoogleAutoProvision: payload.GoogleAutoProvision,
+	}
+	if created.GoogleAutoProvision && created.EmailDomain == "" {
+		return nil, ErrDepartmentDomainRequired
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ensureNameAvailable(ctx, created.Name, 0); err != nil {
+			return err
+		}
+		if err := s.departmentRepo.Create(ctx, created); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, created.ID, models.AuditDepartmentCreated, nil, toDepartmentResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, created.ID)
+}
+
+func (s *departmentService) UpdateDepartment(ctx context.Context, departmentID uint, payload department.UpdateDepartmentRequest) (*department.DepartmentDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.departmentRepo.FindByID(ctx, departmentID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrDepartmentNotFound
+		}
+		previous := toDepartmentResponse(target)
+
+		if payload.Name != nil && *payload.Name != target.Name {
+			if err := s.ensureNameAvailable(ctx, *payload.Name, target.ID); err != nil {
+				return err
+			}
+			target.Name = *payload.Name
+		}
+		if payload.Description != nil {
+			target.Description = *payload.Description
+		}
+		if payload.EmailDomain != nil {
+			target.EmailDomain = *payload.EmailDomain
+		}
+		if payload.GoogleAutoProvision != nil {
+			target.GoogleAutoProvision = *payload.GoogleAutoProvision
+		}
+		if target.GoogleAutoProvision && target.EmailDomain == "" {
+			return ErrDepartmentDomainRequired
+		}
+
+		if err := s.departmentRepo.Update(ctx, target); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, target.ID, models.AuditDepartmentUpdated, previous, toDepartmentResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, departmentID)
+}
+
+// DeleteDepartment soft-deletes a department that has no members left.
+func (s *departmentService) DeleteDepartment(ctx context.Context, departmentID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.departmentRepo.FindByID(ctx, departmentID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrDepartmentNotFound
+		}
+
+		members, err := s.departmentRepo.CountMembers(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		if members > 0 {
+			return ErrDepartmentInUse
+		}
+
+		if err := s.departmentRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, target.ID, models.AuditDepartmentDeleted, toDepartmentResponse(target), nil)
+	})
+}
+
+// ListMembers returns a page of the department's users, with the same
+// filters and paging as the user directory.
+func (s *departmentService) ListMembers(ctx context.Context, departmentID uint, query user.ListUsersQuery) (*user.UserListResponse, error) {
+	target, err := s.departmentRepo.FindByID(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrDepartmentNotFound
+	}
+
+	query.DepartmentID = &target.ID
+	return s.userService.ListUsers(ctx, query)
+}
+
+// SetHead makes a member of the department its head, or removes the head.
+func (s *departmentService) SetHead(ctx context.Context, departmentID uint, payload department.SetHeadRequest) (*department.DepartmentDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.departmentRepo.FindByID(ctx, departmentID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrDepartmentNotFound
+		}
+
+		if payload.UserID != nil {
+			head, err := s.userRepo.FindByID(ctx, *payload.UserID)
+			if err != nil {
+				return err
+			}
+			if head == nil {
+				return ErrUserNotFound
+			}
+			if head.DepartmentID == nil || *head.DepartmentID != target.ID {
+				return ErrHeadNotMember
+			}
+		}
+
+		if err := s.departmentRepo.UpdateHead(ctx, target.ID, payload.UserID); err != nil {
+			return err
+		}
+
+		previous := headChange{HeadID: target.HeadID}
+		next := headChange{HeadID: payload.UserID}
+		return s.recordChange(ctx, target.ID, models.AuditDepartmentHeadChanged, previous, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, departmentID)
+}
+
+type headChange struct {
+	HeadID *uint `json:"head_id"`
+}
+
+func (s *departmentService) ensureNameAvailable(ctx context.Context, name string, excludeID uint) error {
+	taken, err := s.departmentRepo.ExistsByName(ctx, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrDepartmentNameTaken
+	}
+	return nil
+}
+
+func (s *departmentService) recordChange(ctx context.Context, departmentID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, "department", &departmentID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *departmentService) loadDetail(ctx context.Context, departmentID uint) (*department.DepartmentDetailResponse, error) {
+	d, err := s.departmentRepo.FindByIDWithHead(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDepartmentNotFound
+	}
+
+	members, err := s.departmentRepo.CountMembers(ctx, d.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &department.DepartmentDetailResponse{
+		DepartmentResponse: toDepartmentResponse(d),
+		MemberCount:        members,
+	}, nil
+}
+
+func toDepartmentResponse(d *models.Department) department.DepartmentResponse {
+	res := department.DepartmentResponse{
+		ID:                  d.ID,
+		Name:                d.Name,
+		Description:         d.Description,
+		EmailDomain:         d.EmailDomain,
+		GoogleAutoProvision: d.GoogleAutoProvision,
+		CreatedAt:           d.CreatedAt,
+		UpdatedAt:           d.UpdatedAt,
+	}
+	if d.Head != nil {
+		res.Head = &department.HeadSummary{
+			ID:          d.Head.ID,
+			FirstName:   d.Head.FirstName,
+			LastName:    d.Head.LastName,
+			BadgeNumber: d.Head.BadgeNumber,
+		}
+	}
+	return res
+}
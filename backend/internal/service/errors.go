@@ -0,0 +1,94 @@
+package service
+
+import "errors"
+
+var (
+	ErrInvalidCredentials       = errors.New("invalid email or password")
+	ErrUserInactive             = errors.New("user account is inactive")
+	ErrEmailTaken               = errors.New("email is already registered")
+	ErrInvalidRefreshToken      = errors.New("invalid or expired refresh token")
+	ErrInvalidAccessToken       = errors.New("invalid or expired access token")
+	ErrUnauthenticated          = errors.New("authentication required")
+	ErrForbidden                = errors.New("you do not have permission to perform this action")
+	ErrRefreshTokenReused       = errors.New("refresh token has already been used; all tokens in its chain were revoked")
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrParentRoleNotFound       = errors.New("parent role not found")
+	ErrRoleHierarchyCycle       = errors.New("parent role would create a cycle in the role hierarchy")
+	ErrParentRoleNotSenior      = errors.New("parent role must have a higher level than the role")
+	ErrRoleNotManageable        = errors.New("none of your roles may manage this role")
+	ErrRoleEscalation           = errors.New("cannot grant or edit a role at or above your own level")
+	ErrUserOutranksActor        = errors.New("cannot change the roles of a user at or above your own level")
+	ErrRoleAlreadyAssigned      = errors.New("role is already assigned to this user")
+	ErrRoleNotAssigned          = errors.New("role is not assigned to this user")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrRoleNameTaken            = errors.New("a role with this name already exists")
+	ErrSystemRoleProtected      = errors.New("system roles cannot be deleted")
+	ErrRoleInUse                = errors.New("role is still assigned to users or has child roles")
+	ErrPermissionNotFound       = errors.New("permission not found")
+	ErrPermissionEscalation     = errors.New("cannot grant a permission you do not hold")
+	ErrPermissionAlreadyGranted = errors.New("permission is already granted to this role")
+	ErrPermissionNotGranted     = errors.New("permission is not granted to this role")
+	ErrInvalidResetToken        = errors.New("invalid or expired password reset token")
+	ErrGoogleSignInDisabled     = errors.New("google sign-in is not configured")
+	ErrInvalidOIDCState         = errors.New("invalid or expired sign-in state")
+	ErrGoogleSignInFailed       = errors.New("google sign-in could not be verified")
+	ErrGoogleAccountNotLinked   = errors.New("no account is linked to this google identity")
+	ErrGoogleAccountMismatch    = errors.New("this email is already linked to a different google account")
+	ErrInvalidMFAChallenge      = errors.New("invalid or expired mfa challenge")
+	ErrInvalidMFACode           = errors.New("invalid verification code")
+	ErrMFAAlreadyEnabled        = errors.New("mfa is already enabled")
+	ErrMFANotEnabled            = errors.New("mfa is not enabled")
+	ErrMFARequiredByRole        = errors.New("mfa is required by one of your roles and cannot be disabled")
+	ErrSessionNotFound          = errors.New("session not found")
+	ErrTooManyLoginAttempts     = errors.New("too many failed sign-in attempts, try again later")
+	ErrAccountLocked            = errors.New("account is temporarily locked after too many failed sign-in attempts")
+	ErrBadgeNumberTaken         = errors.New("badge number is already in use")
+	ErrUserNotManageable        = errors.New("cannot manage a user at or above your own level")
+	ErrCannotDeactivateSelf     = errors.New("you cannot deactivate or delete your own account")
+	ErrCannotReactivateSelf     = errors.New("you cannot reactivate your own account")
+	ErrCannotTransferSelf       = errors.New("you cannot change your own department")
+	ErrDepartmentNotFound       = errors.New("department not found")
+	ErrInvalidCursor            = errors.New("invalid pagination cursor")
+	ErrInvalidSort              = errors.New("unsupported sort field")
+	ErrInvalidDateRange         = errors.New("the end date is before the start date")
+	ErrStatsRangeTooLong        = errors.New("the date range spans too many intervals")
+	ErrDepartmentNameTaken      = errors.New("department name is already in use")
+	ErrDepartmentInUse          = errors.New("department still has members")
+	ErrDepartmentDomainRequired = errors.New("google auto-provisioning requires an email domain")
+	ErrHeadNotMember            = errors.New("department head must be a member of the department")
+	ErrInvalidCSV               = errors.New("invalid csv file")
+	ErrImportConflict           = errors.New("a user in the import was created concurrently, run it again")
+	ErrCaseNotFound             = errors.New("case not found")
+	ErrCaseNumberTaken          = errors.New("case number is already in use")
+	ErrInvalidCaseStatus        = errors.New("invalid case status")
+	ErrInvalidCasePriority      = errors.New("invalid case priority")
+	ErrCaseNumberFormatNotFound = errors.New("case number format not found")
+	ErrCaseTypeRequired         = errors.New("case type is required")
+	ErrCaseTypeTaken            = errors.New("a case number format already exists for this case type")
+	ErrCasePrefixTaken          = errors.New("case number prefix is already in use")
+	ErrDepartmentHasFormat      = errors.New("the department already has a case number format")
+	ErrUnknownCaseType          = errors.New("no case number format exists for this case type")
+	ErrNoCaseNumberFormat       = errors.New("no case number format applies, choose a case type")
+	ErrCaseNumberExhausted      = errors.New("no free case number found")
+	ErrCaseStatusNotFound       = errors.New("case status not found")
+	ErrCaseStatusNameTaken      = errors.New("a case status with this name already exists")
+	ErrCaseStatusInUse          = errors.New("case status is still used by cases")
+	ErrClosingStatusInUse       = errors.New("cannot change whether a status closes cases while cases are in it")
+	ErrInitialStatusConflict    = errors.New("another status was made the initial status at the same time, try again")
+	ErrNoInitialStatus          = errors.New("the case workflow has no initial status")
+	ErrCaseTransitionNotFound   = errors.New("case transition not found")
+	ErrCaseTransitionExists     = errors.New("a transition between these statuses already exists")
+	ErrInvalidTransition        = errors.New("a transition must lead to a different status")
+	ErrTransitionNotAllowed     = errors.New("the workflow does not allow this status change")
+	ErrReasonRequired           = errors.New("a reason is required for this status change")
+	ErrOfficerInactive          = errors.New("inactive users cannot be assigned to cases")
+	ErrOfficerAlreadyAssigned   = errors.New("officer is already assigned to this case")
+	ErrOfficerNotAssigned       = errors.New("officer is not assigned to this case")
+	ErrCaseHasLead              = errors.New("the case already has a lead investigator")
+	ErrTagNotFound              = errors.New("tag not found")
+	ErrTagNameTaken             = errors.New("a tag with this name already exists")
+	ErrInvalidTagColor          = errors.New("tag color must be a hex color such as #1E90FF")
+	ErrMergeSameTag             = errors.New("a tag cannot be merged into itself")
+	ErrTagAlreadyAttached       = errors.New("tag is already attached to this case")
+	ErrTagNotAttached           = errors.New("tag is not attached to this case")
+)
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/integration/oidc"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+)
+
+// OIDCLoginStateTTL is how long a started Google sign-in may be completed.
+const OIDCLoginStateTTL = 10 * time.Minute
+
+type GoogleAuthService interface {
+	AuthorizationURL(ctx context.Context) (*auth.GoogleAuthorizationResponse, error)
+	SignIn(ctx context.Context, payload auth.GoogleCallbackRequest) (*auth.SignInResponse, error)
+}
+
+type googleAuthService struct {
+	provider       oidc.Provider
+	stateRepo      repository.OIDCLoginStateRepository
+	userRepo       repository.UserRepository
+	departmentRepo repository.DepartmentRepository
+	txManager      repository.TransactionManager
+	authService    AuthService
+}
+
+// NewGoogleAuthService wires Google sign-in. provider may be nil when no
+// client is configured, in which case every call returns
+// ErrGoogleSignInDisabled.
+func NewGoogleAuthService(
+	provider oidc.Provider,
+	stateRepo repository.OIDCLoginStateRepository,
+	userRepo repository.UserRepository,
+	departmentRepo repository.DepartmentRepository,
+	txManager repository.TransactionManager,
+	authService AuthService,
+) GoogleAuthService {
+	return &googleAuthService{
+		provider:       provider,
+		stateRepo:      stateRepo,
+		userRepo:       userRepo,
+		departmentRepo: departmentRepo,
+		txManager:      txManager,
+		authService:    authService,
+	}
+}
+
+// AuthorizationURL starts a login attempt. The state, nonce and PKCE
+// verifier are kept server-side until the callback redeems them.
+func (s *googleAuthService) AuthorizationURL(ctx context.Context) (*auth.GoogleAuthorizationResponse, error) {
+	if s.provider == nil {
+		return nil, ErrGoogleSignInDisabled
+	}
+
+	state, err := security.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := security.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := security.RandomToken(48)
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := s.provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	stateHash := security.HashToken(state)
+	err = s.stateRepo.Create(ctx, &models.OIDCLoginState{
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(OIDCLoginStateTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.GoogleAuthorizationResponse{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		Binding:          stateHash,
+	}, nil
+}
+
+// SignIn completes a login attempt and issues the same tokens as password
+// sign-in. Users are matched by Google subject first, then by verified email;
+// unknown users are only created when their email domain belongs to a
+// department with auto-provisioning enabled.
+func (s *googleAuthService) SignIn(ctx context.Context, payload auth.GoogleCallbackRequest) (*auth.SignInResponse, error) {
+	if s.provider == nil {
+		return nil, ErrGoogleSignInDisabled
+	}
+
+	loginState, err := s.redeemState(ctx, payload.State, payload.Binding)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := s.provider.Exchange(ctx, payload.Code, loginState.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidIDToken) || errors.Is(err, oidc.ErrExchangeFailed) {
+			return nil, ErrGoogleSignInFailed
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(loginState.Nonce)) != 1 {
+		return nil, ErrGoogleSignInFailed
+	}
+
+	var user *models.User
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.resolveUser(ctx, claims)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.CompleteSignIn(ctx, user)
+}
+
+// redeemState consumes the login state in its own transaction so it stays
+// spent even if the code exchange that follows fails. binding must come from
+// the browser that started the attempt; otherwise someone could complete
+// their own sign-in in a victim's browser.
+func (s *googleAuthService) redeemState(ctx context.Context, state, binding string) (*models.OIDCLoginState, error) {
+	var loginState *models.OIDCLoginState
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		found, err := s.stateRepo.FindByHashForUpdate(ctx, security.HashToken(state))
+		if err != nil {
+			return err
+		}
+		if found == nil || found.UsedAt != nil || now.After(found.ExpiresAt) {
+			return ErrInvalidOIDCState
+		}
+		if subtle.ConstantTimeCompare([]byte(binding), []byte(found.StateHash)) != 1 {
+			return ErrInvalidOIDCState
+		}
+
+		loginState = found
+		return s.stateRepo.MarkUsed(ctx, found.ID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loginState, nil
+}
+
+func (s *googleAuthService) resolveUser(ctx context.Context, claims *oidc.Claims) (*models.User, error) {
+	user, err := s.userRepo.FindByGoogleID(ctx, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		return user, nil
+	}
+
+	// Only a verified address proves the Google account owns the email.
+	if !claims.EmailVerified || claims.Email == "" {
+		return nil, ErrGoogleAccountNotLinked
+	}
+	email := strings.ToLower(claims.Email)
+
+	user, err = s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		if user.GoogleID != "" {
+			return nil, ErrGoogleAccountMismatch
+		}
+		if err := s.userRepo.UpdateGoogleID(ctx, user.ID, claims.Subject); err != nil {
+			return nil, err
+		}
+		user.GoogleID = claims.Subject
+		return user, nil
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil, ErrGoogleAccountNotLinked
+	}
+	department, err := s.departmentRepo.FindAutoProvisionByEmailDomain(ctx, email[at+1:])
+	if err != nil {
+		return nil, err
+	}
+	if department == nil {
+		return nil, ErrGoogleAccountNotLinked
+	}
+
+	firstName := claims.GivenName
+	if firstName == "" {
+		firstName = email[:at]
+	}
+	user = &models.User{
+		Email:           email,
+		GoogleID:        claims.Subject,
+		FirstName:       firstName,
+		LastName:        claims.FamilyName,
+		DepartmentID:    &department.ID,
+		ProfileImageURL: claims.Picture,
+		IsActive:        true,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,276 @@
+package service
+
+import (
+	"backend/internal/clientinfo"
+	"backend/internal/integration/smtp"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+type LoginThrottleConfig struct {
+	// FreeAttempts is how many failures an account gets before each further
+	// attempt has to wait.
+	FreeAttempts int
+	// DelayBase is the first wait, doubled for each further failure up to
+	// DelayMax.
+	DelayBase time.Duration
+	DelayMax  time.Duration
+	// LockoutThreshold failures lock the account for LockoutDuration.
+	LockoutThreshold int
+	// IPLockoutThreshold failures from one IP, across any accounts, block
+	// that IP for LockoutDuration.
+	IPLockoutThreshold int
+	LockoutDuration    time.Duration
+	// FailureWindow is how long a failure counts towards the limits.
+	FailureWindow time.Duration
+}
+
+// LoginThrottleError rejects a sign-in attempt until RetryAfter has passed.
+// It unwraps to ErrTooManyLoginAttempts or ErrAccountLocked.
+type LoginThrottleError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottleError) Error() string { return e.Err.Error() }
+func (e *LoginThrottleError) Unwrap() error { return e.Err }
+
+// LoginThrottleService tracks failed password sign-ins per account and per
+// client IP. Accounts get progressively longer waits between attempts and a
+// temporary lockout; IPs get a lockout once they fail across many accounts.
+type LoginThrottleService interface {
+	Check(ctx context.Context, email string) error
+	RecordFailure(ctx context.Context, email string, user *models.User) error
+	RecordSuccess(ctx context.Context, email string) error
+	Unlock(ctx context.Context, userID uint) error
+}
+
+type loginThrottleService struct {
+	throttleRepo repository.LoginThrottleRepository
+	auditRepo    repository.AuditLogRepository
+	userRepo     repository.UserRepository
+	txManager    repository.TransactionManager
+	mailer       smtp.Mailer
+	config       LoginThrottleConfig
+}
+
+func NewLoginThrottleService(
+	throttleRepo repository.LoginThrottleRepository,
+	auditRepo repository.AuditLogRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TransactionManager,
+	mailer smtp.Mailer,
+	config LoginThrottleConfig,
+) LoginThrottleService {
+	return &loginThrottleService{
+		throttleRepo: throttleRepo,
+		auditRepo:    auditRepo,
+		userRepo:     userRepo,
+		txManager:    txManager,
+		mailer:       mailer,
+		config:       config,
+	}
+}
+
+type loginAuditDetails struct {
+	Email          string     `json:"email"`
+	Reason         string     `json:"reason,omitempty"`
+	FailedAttempts int        `json:"failed_attempts,omitempty"`
+	LockedUntil    *time.Time `json:"locked_until,omitempty"`
+}
+
+// Check rejects the attempt while the account or the client IP is locked
+// out or still waiting after its last failure. Rejected attempts are
+// audited but do not count as failures.
+func (s *loginThrottleService) Check(ctx context.Context, email string) error {
+	email = normalizeEmail(email)
+	now := time.Now()
+
+	account, err := s.throttleRepo.Find(ctx, models.LoginThrottleAccount, email)
+	if err != nil {
+		return err
+	}
+	var ip *models.LoginThrottle
+	if addr := clientinfo.FromContext(ctx).IPAddress; addr != "" {
+		if ip, err = s.throttleRepo.Find(ctx, models.LoginThrottleIP, addr); err != nil {
+			return err
+		}
+	}
+
+	var blocked *LoginThrottleError
+	var reason string
+	switch {
+	case account != nil && account.LockedUntil != nil && now.Before(*account.LockedUntil):
+		blocked = &LoginThrottleError{Err: ErrAccountLocked, RetryAfter: account.LockedUntil.Sub(now)}
+		reason = "account_locked"
+	case ip != nil && ip.LockedUntil != nil && now.Before(*ip.LockedUntil):
+		blocked = &LoginThrottleError{Err: ErrTooManyLoginAttempts, RetryAfter: ip.LockedUntil.Sub(now)}
+		reason = "ip_locked"
+	case account != nil && account.NextAttemptAt != nil && now.Before(*account.NextAttemptAt):
+		blocked = &LoginThrottleError{Err: ErrTooManyLoginAttempts, RetryAfter: account.NextAttemptAt.Sub(now)}
+		reason = "delayed"
+	default:
+		return nil
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if err := s.audit(ctx, user, models.AuditLoginBlocked, loginAuditDetails{Email: email, Reason: reason}); err != nil {
+		return err
+	}
+	return blocked
+}
+
+// RecordFailure counts a failed attempt against the account and the client
+// IP. user is the account the email belongs to, or nil if there is none.
+func (s *loginThrottleService) RecordFailure(ctx context.Context, email string, user *models.User) error {
+	email = normalizeEmail(email)
+	addr := clientinfo.FromContext(ctx).IPAddress
+	now := time.Now()
+
+	var locked *models.LoginThrottle
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		account, err := s.throttleRepo.FindOrCreateForUpdate(ctx, models.LoginThrottleAccount, email)
+		if err != nil {
+			return err
+		}
+		s.countFailure(account, now)
+
+		if account.FailedAttempts > s.config.FreeAttempts {
+			next := now.Add(s.delay(account.FailedAttempts - s.config.FreeAttempts))
+			account.NextAttemptAt = &next
+		}
+		if account.FailedAttempts >= s.config.LockoutThreshold {
+			until := now.Add(s.config.LockoutDuration)
+			account.LockedUntil = &until
+			locked = account
+		}
+		if err := s.throttleRepo.Save(ctx, account); err != nil {
+			return err
+		}
+
+		if addr != "" {
+			ip, err := s.throttleRepo.FindOrCreateForUpdate(ctx, models.LoginThrottleIP, addr)
+			if err != nil {
+				return err
+			}
+			s.countFailure(ip, now)
+			if ip.FailedAttempts >= s.config.IPLockoutThreshold {
+				until := now.Add(s.config.LockoutDuration)
+				ip.LockedUntil = &until
+			}
+			if err := s.throttleRepo.Save(ctx, ip); err != nil {
+				return err
+			}
+		}
+
+		details := loginAuditDetails{Email: email, FailedAttempts: account.FailedAttempts}
+		if err := s.audit(ctx, user, models.AuditLoginFailed, details); err != nil {
+			return err
+		}
+		if locked != nil {
+			details.LockedUntil = locked.LockedUntil
+			return s.audit(ctx, user, models.AuditAccountLocked, details)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if locked != nil && user != nil {
+		go func(email, name string) {
+			if err := s.mailer.SendAccountLockedEmail(email, name, s.config.LockoutDuration); err != nil {
+				log.Printf("failed to send account locked email to %s: %v", email, err)
+			}
+		}(user.Email, user.FirstName)
+	}
+
+	return nil
+}
+
+// RecordSuccess clears the account's failures after a correct password. The
+// IP's failures are kept, so one valid account cannot reset them.
+func (s *loginThrottleService) RecordSuccess(ctx context.Context, email string) error {
+	return s.throttleRepo.Delete(ctx, models.LoginThrottleAccount, normalizeEmail(email))
+}
+
+// Unlock lifts an account lockout and clears its failures.
+func (s *loginThrottleService) Unlock(ctx context.Context, userID uint) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.throttleRepo.Delete(ctx, models.LoginThrottleAccount, normalizeEmail(user.Email)); err != nil {
+			return err
+		}
+
+		actorID := p.UserID()
+		entry, err := newAuditLog(ctx, &actorID, models.AuditAccountUnlocked, "user", &user.ID, loginAuditDetails{Email: user.Email})
+		if err != nil {
+			return err
+		}
+		return s.auditRepo.Create(ctx, entry)
+	})
+}
+
+// countFailure adds a failure, first forgetting earlier ones that fell out
+// of the window or ended in a lockout that has since expired.
+func (s *loginThrottleService) countFailure(t *models.LoginThrottle, now time.Time) {
+	expired := t.LastFailedAt != nil && now.Sub(*t.LastFailedAt) > s.config.FailureWindow
+	unlocked := t.LockedUntil != nil && !now.Before(*t.LockedUntil)
+	if expired || unlocked {
+		t.FailedAttempts = 0
+		t.NextAttemptAt = nil
+		t.LockedUntil = nil
+	}
+
+	t.FailedAttempts++
+	t.LastFailedAt = &now
+}
+
+// delay is the wait after the nth failure past the free attempts.
+func (s *loginThrottleService) delay(n int) time.Duration {
+	d := s.config.DelayBase
+	for i := 1; i < n && d < s.config.DelayMax; i++ {
+		d *= 2
+	}
+	if d > s.config.DelayMax {
+		d = s.config.DelayMax
+	}
+	return d
+}
+
+func (s *loginThrottleService) audit(ctx context.Context, user *models.User, action string, details loginAuditDetails) error {
+	var userID *uint
+	if user != nil {
+		userID = &user.ID
+	}
+
+	entry, err := newAuditLog(ctx, userID, action, "user", userID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
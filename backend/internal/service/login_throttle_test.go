@@ -0,0 +1,96 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLoginThrottleDelay(t *testing.T) {
+	s := &loginThrottleService{config: LoginThrottleConfig{
+		DelayBase: time.Second,
+		DelayMax:  30 * time.Second,
+	}}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{5, 16 * time.Second},
+		{6, 30 * time.Second},
+		{50, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := s.delay(tt.failures); got != tt.want {
+			t.Errorf("delay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+type fakeLoginThrottleRepo struct {
+	repository.LoginThrottleRepository
+	throttle *models.LoginThrottle
+}
+
+func (r *fakeLoginThrottleRepo) FindOrCreateForUpdate(_ context.Context, scope, key string) (*models.LoginThrottle, error) {
+	if r.throttle == nil {
+		r.throttle = &models.LoginThrottle{Scope: scope, Key: key}
+	}
+	return r.throttle, nil
+}
+
+func (r *fakeLoginThrottleRepo) Save(context.Context, *models.LoginThrottle) error {
+	return nil
+}
+
+type fakeAuditLogRepo struct {
+	repository.AuditLogRepository
+	entries []*models.AuditLog
+}
+
+func (r *fakeAuditLogRepo) Create(_ context.Context, entry *models.AuditLog) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+func TestRecordFailureDelaysAfterFreeAttempts(t *testing.T) {
+	throttles := &fakeLoginThrottleRepo{}
+	s := NewLoginThrottleService(throttles, &fakeAuditLogRepo{}, nil, fakeTxManager{}, nil, LoginThrottleConfig{
+		FreeAttempts:     2,
+		DelayBase:        time.Second,
+		DelayMax:         4 * time.Second,
+		LockoutThreshold: 6,
+		LockoutDuration:  time.Hour,
+		FailureWindow:    time.Hour,
+	})
+	ctx := context.Background()
+
+	// The wait after each failure; zero means the next attempt is free.
+	wants := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}
+	for i, want := range wants {
+		if err := s.RecordFailure(ctx, "Officer@Example.com", nil); err != nil {
+			t.Fatalf("failure %d: %v", i+1, err)
+		}
+		throttle := throttles.throttle
+
+		var got time.Duration
+		if throttle.NextAttemptAt != nil {
+			got = throttle.NextAttemptAt.Sub(*throttle.LastFailedAt)
+		}
+		if got != want {
+			t.Errorf("failure %d: wait %v, want %v", i+1, got, want)
+		}
+	}
+
+	if throttles.throttle.Key != "officer@example.com" {
+		t.Errorf("key = %q, want the normalised email", throttles.throttle.Key)
+	}
+	if throttles.throttle.LockedUntil == nil {
+		t.Error("account is not locked after reaching the lockout threshold")
+	}
+}
@@ -0,0 +1,480 @@
+package service
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+)
+
+const (
+	mfaMaxChallengeAttempts = 5
+	mfaRecoveryCodeCount    = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type MFAConfig struct {
+	Issuer       string // shown as the account issuer in authenticator apps
+	ChallengeTTL time.Duration
+}
+
+// MFAService manages TOTP enrollment and the second step of sign-in.
+//
+// A user must pass a second factor when they have enrolled or when any of
+// their roles has MFARequired set. Users who must but have not enrolled yet
+// enroll through their sign-in challenge, so they never hold tokens without
+// a second factor.
+type MFAService interface {
+	Required(ctx context.Context, user *models.User) (bool, error)
+	CreateChallenge(ctx context.Context, user *models.User) (*auth.SignInResponse, error)
+	ChallengeUser(ctx context.Context, challengeToken string) (*models.User, error)
+	RedeemChallenge(ctx context.Context, payload auth.MFAVerifyRequest) (*models.User, []string, error)
+	BeginChallengeEnrollment(ctx context.Context, payload auth.MFAChallengeRequest) (*auth.MFAEnrollmentResponse, error)
+	BeginEnrollment(ctx context.Context) (*auth.MFAEnrollmentResponse, error)
+	ConfirmEnrollment(ctx context.Context, payload auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)
+	RegenerateRecoveryCodes(ctx context.Context, payload auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error)
+	Disable(ctx context.Context, payload auth.MFACodeRequest) error
+}
+
+type mfaService struct {
+	userRepo     repository.UserRepository
+	userRoleRepo repository.UserRoleRepository
+	mfaRepo      repository.MFARepository
+	txManager    repository.TransactionManager
+	secretBox    *security.SecretBox
+	config       MFAConfig
+}
+
+func NewMFAService(
+	userRepo repository.UserRepository,
+	userRoleRepo repository.UserRoleRepository,
+	mfaRepo repository.MFARepository,
+	txManager repository.TransactionManager,
+	secretBox *security.SecretBox,
+	config MFAConfig,
+) MFAService {
+	return &mfaService{
+		userRepo:     userRepo,
+		userRoleRepo: userRoleRepo,
+		mfaRepo:      mfaRepo,
+		txManager:    txManager,
+		secretBox:    secretBox,
+		config:       config,
+	}
+}
+
+func (s *mfaService) Required(ctx context.Context, user *models.User) (bool, error) {
+	if user.MFAEnabled {
+		return true, nil
+	}
+	return s.requiredByRole(ctx, user.ID)
+}
+
+func (s *mfaService) requiredByRole(ctx context.Context, userID uint) (bool, error) {
+	userRoles, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, userRole := range userRoles {
+		if userRole.Role != nil && userRole.Role.MFARequired {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *mfaService) CreateChallenge(ctx context.Context, user *models.User) (*auth.SignInResponse, error) {
+	token, err := security.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mfaRepo.CreateChallenge(ctx, &models.MFAChallenge{
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(s.config.ChallengeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.SignInResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: !user.MFAEnabled,
+		MFAChallengeToken:     token,
+		MFAChallengeExpiresIn: int(s.config.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// ChallengeUser returns the user an open sign-in challenge belongs to, so
+// that the caller can apply the sign-in throttle before redeeming it.
+func (s *mfaService) ChallengeUser(ctx context.Context, challengeToken string) (*models.User, error) {
+	challenge, err := s.mfaRepo.FindChallengeByHash(ctx, security.HashToken(challengeToken))
+	if err != nil {
+		return nil, err
+	}
+	if !challengeOpen(challenge, time.Now()) {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+	return user, nil
+}
+
+// challengeOpen reports whether challenge can still be redeemed at now.
+func challengeOpen(challenge *models.MFAChallenge, now time.Time) bool {
+	return challenge != nil && challenge.UsedAt == nil && !now.After(challenge.ExpiresAt) && challenge.Attempts < mfaMaxChallengeAttempts
+}
+
+// RedeemChallenge checks the second factor for a sign-in challenge and
+// returns the user to issue tokens for. A user completing enrollment through
+// the challenge also gets their first recovery codes.
+//
+// Failed attempts are committed before the error is returned so that the
+// attempt limit holds.
+func (s *mfaService) RedeemChallenge(ctx context.Context, payload auth.MFAVerifyRequest) (*models.User, []string, error) {
+	var (
+		user          *models.User
+		recoveryCodes []string
+		failed        bool
+	)
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		challenge, err := s.mfaRepo.FindChallengeByHashForUpdate(ctx, security.HashToken(payload.ChallengeToken))
+		if err != nil {
+			return err
+		}
+		if !challengeOpen(challenge, now) {
+			return ErrInvalidMFAChallenge
+		}
+
+		user, err = s.userRepo.FindByIDForUpdate(ctx, challenge.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrInvalidMFAChallenge
+		}
+
+		enrolling := !user.MFAEnabled
+		ok, err := s.verifyCode(ctx, user, payload.Code, !enrolling, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failed = true
+			return s.mfaRepo.IncrementChallengeAttempts(ctx, challenge.ID)
+		}
+
+		if enrolling {
+			recoveryCodes, err = s.enable(ctx, user, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.mfaRepo.MarkChallengeUsed(ctx, challenge.ID, now)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if failed {
+		return nil, nil, ErrInvalidMFACode
+	}
+
+	return user, recoveryCodes, nil
+}
+
+// BeginChallengeEnrollment lets a user whose role requires MFA enroll
+// using the challenge from their sign-in attempt.
+func (s *mfaService) BeginChallengeEnrollment(ctx context.Context, payload auth.MFAChallengeRequest) (*auth.MFAEnrollmentResponse, error) {
+	var res *auth.MFAEnrollmentResponse
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		challenge, err := s.mfaRepo.FindChallengeByHashForUpdate(ctx, security.HashToken(payload.ChallengeToken))
+		if err != nil {
+			return err
+		}
+		if !challengeOpen(challenge, time.Now()) {
+			return ErrInvalidMFAChallenge
+		}
+
+		res, err = s.beginEnrollment(ctx, challenge.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *mfaService) BeginEnrollment(ctx context.Context) (*auth.MFAEnrollmentResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var res *auth.MFAEnrollmentResponse
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		res, err = s.beginEnrollment(ctx, p.UserID())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// beginEnrollment stores a fresh, not yet active TOTP secret. Starting again
+// before confirming replaces the pending secret.
+func (s *mfaService) beginEnrollment(ctx context.Context, userID uint) (*auth.MFAEnrollmentResponse, error) {
+	user, err := s.userRepo.FindByIDForUpdate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.secretBox.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFASecret = sealed
+	user.MFALastUsedStep = 0
+	if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &auth.MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(s.config.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *mfaService) ConfirmEnrollment(ctx context.Context, payload auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var recoveryCodes []string
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		user, err := s.userRepo.FindByIDForUpdate(ctx, p.UserID())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+		if user.MFAEnabled {
+			return ErrMFAAlreadyEnabled
+		}
+
+		ok, err := s.verifyCode(ctx, user, payload.Code, false, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		recoveryCodes, err = s.enable(ctx, user, now)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, payload auth.MFACodeRequest) (*auth.MFARecoveryCodesResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	var recoveryCodes []string
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.FindByIDForUpdate(ctx, p.UserID())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+		if !user.MFAEnabled {
+			return ErrMFANotEnabled
+		}
+
+		ok, err := s.verifyCode(ctx, user, payload.Code, false, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		recoveryCodes, err = s.newRecoveryCodes(ctx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.MFARecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+// Disable turns MFA off for the current user. Users holding a role that
+// requires MFA cannot disable it.
+func (s *mfaService) Disable(ctx context.Context, payload auth.MFACodeRequest) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.FindByIDForUpdate(ctx, p.UserID())
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+		if !user.MFAEnabled {
+			return ErrMFANotEnabled
+		}
+
+		required, err := s.requiredByRole(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if required {
+			return ErrMFARequiredByRole
+		}
+
+		ok, err := s.verifyCode(ctx, user, payload.Code, true, time.Now())
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		user.MFAEnabled = false
+		user.MFAEnabledAt = nil
+		user.MFASecret = ""
+		user.MFALastUsedStep = 0
+		if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+			return err
+		}
+		return s.mfaRepo.DeleteRecoveryCodes(ctx, user.ID)
+	})
+}
+
+// verifyCode accepts a current TOTP code, or an unused recovery code when
+// allowRecovery is set. Accepted codes are consumed: the TOTP time step is
+// recorded so the same code cannot be replayed, and recovery codes are
+// marked used. The user row must be locked by the caller.
+func (s *mfaService) verifyCode(ctx context.Context, user *models.User, code string, allowRecovery bool, now time.Time) (bool, error) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+
+	if user.MFASecret != "" && isNumeric(code) {
+		secret, err := s.secretBox.Open(user.MFASecret)
+		if err != nil {
+			return false, err
+		}
+		step, ok := security.ValidateTOTP(secret, code, now)
+		if !ok || step <= user.MFALastUsedStep {
+			return false, nil
+		}
+		user.MFALastUsedStep = step
+		return true, s.userRepo.UpdateMFA(ctx, user)
+	}
+
+	if !allowRecovery {
+		return false, nil
+	}
+
+	recoveryCode, err := s.mfaRepo.FindUnusedRecoveryCode(ctx, user.ID, security.HashToken(normalizeRecoveryCode(code)))
+	if err != nil || recoveryCode == nil {
+		return false, err
+	}
+	return true, s.mfaRepo.MarkRecoveryCodeUsed(ctx, recoveryCode.ID, now)
+}
+
+func (s *mfaService) enable(ctx context.Context, user *models.User, now time.Time) ([]string, error) {
+	user.MFAEnabled = true
+	user.MFAEnabledAt = &now
+	if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(ctx, user.ID)
+}
+
+// newRecoveryCodes replaces the user's recovery codes and returns the new
+// plaintext codes, which are shown once and stored only as hashes.
+func (s *mfaService) newRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, 0, mfaRecoveryCodeCount)
+	hashes := make([]string, 0, mfaRecoveryCodeCount)
+
+	for i := 0; i < mfaRecoveryCodeCount; i++ {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf)) // 16 characters
+		codes = append(codes, raw[:4]+"-"+raw[4:8]+"-"+raw[8:12]+"-"+raw[12:])
+		hashes = append(hashes, security.HashToken(raw))
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
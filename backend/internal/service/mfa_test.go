@@ -0,0 +1,76 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/security"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func (r *fakeUserRepo) UpdateMFA(context.Context, *models.User) error {
+	return nil
+}
+
+// totpAt computes the RFC 6238 code of the base32 secret at step.
+func totpAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+func TestVerifyCodeRejectsReplayedSteps(t *testing.T) {
+	secretBox := security.NewSecretBox("test-key")
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	sealed, err := secretBox.Seal(secret)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	user := &models.User{MFAEnabled: true, MFASecret: sealed}
+	s := &mfaService{userRepo: &fakeUserRepo{user: user}, secretBox: secretBox}
+
+	now := time.Unix(1_800_000_000, 0)
+	current := now.Unix() / 30
+	steps := []struct {
+		name string
+		step int64
+		ok   bool
+	}{
+		{"previous step", current - 1, true},
+		{"same code again", current - 1, false},
+		{"current step", current, true},
+		{"older step after a newer one", current - 1, false},
+		{"current step again", current, false},
+		{"next step", current + 1, true},
+		{"current step after the next one", current, false},
+	}
+	for _, tt := range steps {
+		ok, err := s.verifyCode(context.Background(), user, totpAt(t, secret, tt.step), false, now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ok != tt.ok {
+			t.Errorf("%s: got %v, want %v", tt.name, ok, tt.ok)
+		}
+	}
+	if user.MFALastUsedStep != current+1 {
+		t.Errorf("last used step = %d, want %d", user.MFALastUsedStep, current+1)
+	}
+}
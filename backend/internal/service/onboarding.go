@@ -0,0 +1,486 @@
+package service
+
+import (
+	"backend/internal/dto/user"
+	"backend/internal/integration/smtp"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxImportRows caps the size of one CSV import.
+const maxImportRows = 1000
+
+type OnboardingConfig struct {
+	InvitationTTL  time.Duration
+	SetPasswordURL string // the emailed link is SetPasswordURL?token=...
+}
+
+// OnboardingService creates users in bulk from a CSV file. New users get no
+// password; each is emailed a single-use link to choose one, redeemed like a
+// password reset.
+//
+// Invitations are sent in the background once an import has committed, so
+// a large import does not hold the request open. Each email that cannot be
+// sent is recorded in the audit log as models.AuditUserInvitationFailed.
+type OnboardingService interface {
+	ImportUsers(ctx context.Context, file io.Reader, dryRun bool) (*user.ImportUsersResponse, error)
+	// WaitForInvitations blocks until the invitations of earlier imports
+	// have been sent, for callers that exit afterwards.
+	WaitForInvitations()
+}
+
+type onboardingService struct {
+	userRepo           repository.UserRepository
+	userRoleRepo       repository.UserRoleRepository
+	roleRepo           repository.RoleRepository
+	roleManagementRepo repository.RoleManagementRepository
+	departmentRepo     repository.DepartmentRepository
+	resetTokenRepo     repository.PasswordResetTokenRepository
+	auditRepo          repository.AuditLogRepository
+	txManager          repository.TransactionManager
+	mailer             smtp.Mailer
+	config             OnboardingConfig
+	sending            sync.WaitGroup
+}
+
+func NewOnboardingService(
+	userRepo repository.UserRepository,
+	userRoleRepo repository.UserRoleRepository,
+	roleRepo repository.RoleRepository,
+	roleManagementRepo repository.RoleManagementRepository,
+	departmentRepo repository.DepartmentRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	mailer smtp.Mailer,
+	config OnboardingConfig,
+) OnboardingService {
+	return &onboardingService{
+		userRepo:           userRepo,
+		userRoleRepo:       userRoleRepo,
+		roleRepo:           roleRepo,
+		roleManagementRepo: roleManagementRepo,
+		departmentRepo:     departmentRepo,
+		resetTokenRepo:     resetTokenRepo,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+		mailer:             mailer,
+		config:             config,
+	}
+}
+
+// importRow is a validated CSV row, ready to be created.
+type importRow struct {
+	line  int
+	user  *models.User
+	roles []*models.Role
+}
+
+type invitation struct {
+	userID uint
+	email  string
+	name   string
+	token  string
+}
+
+// ImportUsers validates every row of the CSV and, unless dryRun is set or
+// any row is invalid, creates all users and their role assignments in one
+// transaction. Invitations are sent in the background once the transaction
+// has committed.
+//
+// The file needs a header row. Recognised columns are first_name,
+// last_name (or a single name column), email, badge_number, department
+// and roles; department and roles are matched by name, and roles are
+// separated by semicolons.
+func (s *onboardingService) ImportUsers(ctx context.Context, file io.Reader, dryRun bool) (*user.ImportUsersResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	records, err := readImportCSV(file)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := s.newImportValidator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &user.ImportUsersResponse{
+		DryRun:    dryRun,
+		TotalRows: len(records.rows),
+		Errors:    []user.ImportRowError{},
+		Users:     []user.ImportedUser{},
+	}
+	rows := make([]importRow, 0, len(records.rows))
+	for i, record := range records.rows {
+		row, rowErrors, err := v.validate(ctx, records.lines[i], records.field(record))
+		if err != nil {
+			return nil, err
+		}
+		if len(rowErrors) > 0 {
+			res.Errors = append(res.Errors, rowErrors...)
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if dryRun || len(res.Errors) > 0 {
+		for _, row := range rows {
+			res.Users = append(res.Users, toImportedUser(row))
+		}
+		return res, nil
+	}
+
+	actorID := p.UserID()
+	expiresAt := time.Now().Add(s.config.InvitationTTL)
+	invitations := make([]invitation, 0, len(rows))
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, row := range rows {
+			if err := s.userRepo.Create(ctx, row.user); err != nil {
+				// Lost a race with a concurrent write of the same email or badge.
+				if errors.Is(err, repository.ErrDuplicateKey) {
+					return fmt.Errorf("%w (row %d)", ErrImportConflict, row.line)
+				}
+				return err
+			}
+			for _, role := range row.roles {
+				err := s.userRoleRepo.Create(ctx, &models.UserRole{
+					UserID:       row.user.ID,
+					RoleID:       role.ID,
+					AssignedByID: &actorID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			token, err := security.RandomToken(32)
+			if err != nil {
+				return err
+			}
+			err = s.resetTokenRepo.Create(ctx, &models.PasswordResetToken{
+				UserID:    row.user.ID,
+				TokenHash: security.HashToken(token),
+				ExpiresAt: expiresAt,
+			})
+			if err != nil {
+				return err
+			}
+
+			imported := toImportedUser(row)
+			entry, err := newAuditLog(ctx, &actorID, models.AuditUserCreated, "user", &row.user.ID, auditChange{New: imported})
+			if err != nil {
+				return err
+			}
+			if err := s.auditRepo.Create(ctx, entry); err != nil {
+				return err
+			}
+
+			res.Users = append(res.Users, imported)
+			invitations = append(invitations, invitation{
+				userID: row.user.ID,
+				email:  row.user.Email,
+				name:   row.user.FirstName,
+				token:  token,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.Created = len(rows)
+	res.InvitationsQueued = len(invitations)
+
+	s.sending.Add(1)
+	go func() {
+		defer s.sending.Done()
+		s.sendInvitations(context.WithoutCancel(ctx), actorID, invitations)
+	}()
+	return res, nil
+}
+
+func (s *onboardingService) WaitForInvitations() {
+	s.sending.Wait()
+}
+
+type invitationFailure struct {
+	Email string `json:"email"`
+}
+
+// sendInvitations emails the set-password links. A failed email does not
+// undo the import; it is audited against the user, who can still use
+// "forgot password" later.
+func (s *onboardingService) sendInvitations(ctx context.Context, actorID uint, invitations []invitation) {
+	failed := 0
+	for _, inv := range invitations {
+		link := s.config.SetPasswordURL + "?" + url.Values{"token": {inv.token}}.Encode()
+		err := s.mailer.SendInvitationEmail(inv.email, inv.name, link, s.config.InvitationTTL)
+		if err == nil {
+			continue
+		}
+		failed++
+		log.Printf("failed to send invitation email to %s: %v", inv.email, err)
+
+		entry, err := newAuditLog(ctx, &actorID, models.AuditUserInvitationFailed, "user", &inv.userID, invitationFailure{Email: inv.email})
+		if err == nil {
+			err = s.auditRepo.Create(ctx, entry)
+		}
+		if err != nil {
+			log.Printf("failed to audit the invitation failure for user %d: %v", inv.userID, err)
+		}
+	}
+	if failed > 0 {
+		log.Printf("sent %d of %d invitation emails", len(invitations)-failed, len(invitations))
+	}
+}
+
+// importRecords holds the data rows of a CSV file and where they came from.
+type importRecords struct {
+	columns map[string]int
+	rows    [][]string
+	lines   []int
+}
+
+// field returns a getter for the named column of record, empty when the
+// file has no such column.
+func (r *importRecords) field(record []string) func(name string) string {
+	return func(name string) string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+}
+
+func readImportCSV(file io.Reader) (*importRecords, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidCSV)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	records := &importRecords{columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // BOM written by spreadsheet exports
+		}
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		records.columns[name] = i
+	}
+	if _, ok := records.columns["email"]; !ok {
+		return nil, fmt.Errorf("%w: missing email column", ErrInvalidCSV)
+	}
+	_, hasName := records.columns["name"]
+	_, hasFirst := records.columns["first_name"]
+	_, hasLast := records.columns["last_name"]
+	if !hasName && !(hasFirst && hasLast) {
+		return nil, fmt.Errorf("%w: missing name or first_name and last_name columns", ErrInvalidCSV)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+		}
+		if len(records.rows) == maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidCSV, maxImportRows)
+		}
+		line, _ := reader.FieldPos(0)
+		records.rows = append(records.rows, record)
+		records.lines = append(records.lines, line)
+	}
+	if len(records.rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows to import", ErrInvalidCSV)
+	}
+
+	return records, nil
+}
+
+// importValidator checks rows against the database and against the rows
+// before them in the same file.
+type importValidator struct {
+	userRepo    repository.UserRepository
+	departments map[string]*models.Department
+	roles       map[string]*models.Role
+	guard       *roleGuard
+	emails      map[string]int
+	badges      map[string]int
+}
+
+func (s *onboardingService) newImportValidator(ctx context.Context) (*importValidator, error) {
+	departments, err := s.departmentRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hierarchy := newRoleHierarchy(roles)
+	guard, err := loadRoleGuard(ctx, s.userRoleRepo, s.roleManagementRepo, hierarchy)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &importValidator{
+		userRepo:    s.userRepo,
+		departments: make(map[string]*models.Department, len(departments)),
+		roles:       make(map[string]*models.Role, len(roles)),
+		guard:       guard,
+		emails:      make(map[string]int),
+		badges:      make(map[string]int),
+	}
+	for i := range departments {
+		v.departments[strings.ToLower(departments[i].Name)] = &departments[i]
+	}
+	for _, role := range hierarchy.roles {
+		v.roles[strings.ToLower(role.Name)] = role
+	}
+	return v, nil
+}
+
+func (v *importValidator) validate(ctx context.Context, line int, field func(string) string) (importRow, []user.ImportRowError, error) {
+	var rowErrors []user.ImportRowError
+	fail := func(name, format string, args ...interface{}) {
+		rowErrors = append(rowErrors, user.ImportRowError{Row: line, Field: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	firstName, lastName := field("first_name"), field("last_name")
+	if firstName == "" && lastName == "" {
+		if parts := strings.Fields(field("name")); len(parts) > 1 {
+			firstName = strings.Join(parts[:len(parts)-1], " ")
+			lastName = parts[len(parts)-1]
+		}
+	}
+	switch {
+	case firstName == "" || lastName == "":
+		fail("name", "first and last name are required")
+	case len(firstName) > 50 || len(lastName) > 50:
+		fail("name", "first and last name must be at most 50 characters")
+	}
+
+	email := normalizeEmail(field("email"))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email || len(email) > 100 {
+		fail("email", "a valid email address of at most 100 characters is required")
+	} else if prev, ok := v.emails[email]; ok {
+		fail("email", "duplicates row %d", prev)
+	} else {
+		v.emails[email] = line
+		taken, err := v.userRepo.ExistsByEmail(ctx, email, 0)
+		if err != nil {
+			return importRow{}, nil, err
+		}
+		if taken {
+			fail("email", "%v", ErrEmailTaken)
+		}
+	}
+
+	badge := field("badge_number")
+	if len(badge) > 20 {
+		fail("badge_number", "must be at most 20 characters")
+	} else if badge != "" {
+		if prev, ok := v.badges[badge]; ok {
+			fail("badge_number", "duplicates row %d", prev)
+		} else {
+			v.badges[badge] = line
+			taken, err := v.userRepo.ExistsByBadgeNumber(ctx, badge, 0)
+			if err != nil {
+				return importRow{}, nil, err
+			}
+			if taken {
+				fail("badge_number", "%v", ErrBadgeNumberTaken)
+			}
+		}
+	}
+
+	var departmentID *uint
+	if name := field("department"); name != "" {
+		if d, ok := v.departments[strings.ToLower(name)]; ok {
+			departmentID = &d.ID
+		} else {
+			fail("department", "unknown department %q", name)
+		}
+	}
+
+	var roles []*models.Role
+	seen := make(map[uint]bool)
+	for _, name := range strings.Split(field("roles"), ";") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		role, ok := v.roles[strings.ToLower(name)]
+		if !ok {
+			fail("roles", "unknown role %q", name)
+			continue
+		}
+		if err := v.guard.checkRole(role); err != nil {
+			fail("roles", "%s: %v", role.Name, err)
+			continue
+		}
+		if !seen[role.ID] {
+			seen[role.ID] = true
+			roles = append(roles, role)
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return importRow{}, rowErrors, nil
+	}
+	return importRow{
+		line: line,
+		user: &models.User{
+			Email:        email,
+			FirstName:    firstName,
+			LastName:     lastName,
+			BadgeNumber:  badge,
+			DepartmentID: departmentID,
+			IsActive:     true,
+		},
+		roles: roles,
+	}, nil, nil
+}
+
+func toImportedUser(row importRow) user.ImportedUser {
+	roles := make([]string, 0, len(row.roles))
+	for _, role := range row.roles {
+		roles = append(roles, role.Name)
+	}
+	return user.ImportedUser{
+		Row:          row.line,
+		ID:           row.user.ID,
+		Email:        row.user.Email,
+		FirstName:    row.user.FirstName,
+		LastName:     row.user.LastName,
+		BadgeNumber:  row.user.BadgeNumber,
+		DepartmentID: row.user.DepartmentID,
+		Roles:        roles,
+	}
+}
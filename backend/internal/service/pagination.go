@@ -0,0 +1,73 @@
+package service
+
+import (
+	"backend/internal/dto/pagination"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	defaultPageLimit = 20
+)
+
+// listCursor is the opaque next_cursor handed to clients. Sort ties the
+// cursor to the ordering it was issued for.
+type listCursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+func encodeCursor(sort string, value interface{}, id uint) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(listCursor{Sort: sort, Value: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reads a cursor issued for sort, storing its sort value in
+// value.
+func decodeCursor(encoded, sort string, value interface{}) (uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.ID == 0 {
+		return 0, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(cursor.Value, value); err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return cursor.ID, nil
+}
+
+// parseSort splits a "field" or "-field" sort parameter, falling back to
+// fallback when empty.
+func parseSort(sort, fallback string) (field string, desc bool) {
+	if sort == "" {
+		sort = fallback
+	}
+	if strings.HasPrefix(sort, "-") {
+		return sort[1:], true
+	}
+	return sort, false
+}
+
+// offsetMeta describes an offset page of total rows.
+func offsetMeta(limit, page int, total int64) pagination.Meta {
+	pages := (total + int64(limit) - 1) / int64(limit)
+	return pagination.Meta{
+		Limit:      limit,
+		Page:       page,
+		Total:      &total,
+		TotalPages: &pages,
+		HasMore:    int64(page) < pages,
+	}
+}
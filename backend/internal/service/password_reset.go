@@ -0,0 +1,144 @@
+package service
+
+import (
+	"backend/internal/dto/auth"
+	"backend/internal/integration/smtp"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"log"
+	"net/url"
+	"time"
+)
+
+type PasswordResetService interface {
+	RequestReset(ctx context.Context, payload auth.ForgetPasswordRequest) error
+	ResetPassword(ctx context.Context, payload auth.ResetPasswordRequest) error
+}
+
+type PasswordResetConfig struct {
+	TokenTTL time.Duration
+	ResetURL string // the emailed link is ResetURL?token=...
+}
+
+type passwordResetService struct {
+	userRepo         repository.UserRepository
+	resetTokenRepo   repository.PasswordResetTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.UserSessionRepository
+	txManager        repository.TransactionManager
+	passwordHasher   security.PasswordHasher
+	mailer           smtp.Mailer
+	config           PasswordResetConfig
+}
+
+func NewPasswordResetService(
+	userRepo repository.UserRepository,
+	resetTokenRepo repository.PasswordResetTokenRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	txManager repository.TransactionManager,
+	passwordHasher security.PasswordHasher,
+	mailer smtp.Mailer,
+	config PasswordResetConfig,
+) PasswordResetService {
+	return &passwordResetService{
+		userRepo:         userRepo,
+		resetTokenRepo:   resetTokenRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		txManager:        txManager,
+		passwordHasher:   passwordHasher,
+		mailer:           mailer,
+		config:           config,
+	}
+}
+
+// RequestReset emails a single-use reset link to the account behind the
+// address. Unknown and inactive accounts are silently ignored so callers
+// cannot use the endpoint to discover which emails are registered.
+func (s *passwordResetService) RequestReset(ctx context.Context, payload auth.ForgetPasswordRequest) error {
+	user, err := s.userRepo.FindByEmail(ctx, payload.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil || !user.IsActive {
+		return nil
+	}
+
+	token, err := security.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// Only the most recently issued link stays valid.
+		if err := s.resetTokenRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.resetTokenRepo.Create(ctx, &models.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: security.HashToken(token),
+			ExpiresAt: time.Now().Add(s.config.TokenTTL),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	resetURL := s.config.ResetURL + "?" + url.Values{"token": {token}}.Encode()
+
+	// Sent in the background so the response time does not depend on
+	// whether the account exists.
+	go func(email, name string) {
+		if err := s.mailer.SendPasswordResetEmail(email, name, resetURL, s.config.TokenTTL); err != nil {
+			log.Printf("failed to send password reset email to %s: %v", email, err)
+		}
+	}(user.Email, user.FirstName)
+
+	return nil
+}
+
+// ResetPassword redeems a reset token, sets the new password and signs the
+// user out everywhere by revoking all refresh tokens and sessions.
+func (s *passwordResetService) ResetPassword(ctx context.Context, payload auth.ResetPasswordRequest) error {
+	passwordHash, err := s.passwordHasher.Hash(payload.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		resetToken, err := s.resetTokenRepo.FindByHashForUpdate(ctx, security.HashToken(payload.Token))
+		if err != nil {
+			return err
+		}
+		if resetToken == nil || resetToken.UsedAt != nil || now.After(resetToken.ExpiresAt) {
+			return ErrInvalidResetToken
+		}
+
+		user, err := s.userRepo.FindByID(ctx, resetToken.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil || !user.IsActive {
+			return ErrInvalidResetToken
+		}
+
+		if err := s.userRepo.UpdatePasswordHash(ctx, user.ID, passwordHash); err != nil {
+			return err
+		}
+		if err := s.resetTokenRepo.MarkUsed(ctx, resetToken.ID, now); err != nil {
+			return err
+		}
+		if err := s.resetTokenRepo.InvalidateByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.RevokeByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.sessionRepo.DeactivateByUserID(ctx, user.ID)
+	})
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"backend/internal/dto/role"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"context"
+	"sort"
+)
+
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]role.RoleResponse, error)
+	GetRole(ctx context.Context, roleID uint) (*role.RoleDetailResponse, error)
+	CreateRole(ctx context.Context, payload role.CreateRoleRequest) (*role.RoleResponse, error)
+	UpdateRole(ctx context.Context, roleID uint, payload role.UpdateRoleRequest) (*role.RoleResponse, error)
+	DeleteRole(ctx context.Context, roleID uint) error
+	GetRoleHistory(ctx context.Context, roleID uint) ([]role.RoleChangeResponse, error)
+	ListPermissions(ctx context.Context) ([]role.PermissionCategoryResponse, error)
+	GrantPermission(ctx context.Context, roleID uint, payload role.GrantPermissionRequest) error
+	RevokePermission(ctx context.Context, roleID, permissionID uint) error
+	GetEffectivePermissions(ctx context.Context, roleID uint) (*role.EffectivePermissionsResponse, error)
+	SetParent(ctx context.Context, roleID uint, payload role.SetParentRequest) error
+	ListUserRoles(ctx context.Context, userID uint) ([]role.UserRoleResponse, error)
+	AssignRole(ctx context.Context, userID uint, payload role.AssignRoleRequest) error
+	UnassignRole(ctx context.Context, userID, roleID uint) error
+}
+
+type roleService struct {
+	roleRepo           repository.RoleRepository
+	permissionRepo     repository.PermissionRepository
+	userRepo           repository.UserRepository
+	userRoleRepo       repository.UserRoleRepository
+	roleManagementRepo repository.RoleManagementRepository
+	historyRepo        repository.RoleChangeHistoryRepository
+	txManager          repository.TransactionManager
+	authorizer         Authorizer
+}
+
+func NewRoleService(
+	roleRepo repository.RoleRepository,
+	permissionRepo repository.PermissionRepository,
+	userRepo repository.UserRepository,
+	userRoleRepo repository.UserRoleRepository,
+	roleManagementRepo repository.RoleManagementRepository,
+	historyRepo repository.RoleChangeHistoryRepository,
+	txManager repository.TransactionManager,
+	authorizer Authorizer,
+) RoleService {
+	return &roleService{
+		roleRepo:           roleRepo,
+		permissionRepo:     permissionRepo,
+		userRepo:           userRepo,
+		userRoleRepo:       userRoleRepo,
+		roleManagementRepo: roleManagementRepo,
+		historyRepo:        historyRepo,
+		txManager:          txManager,
+		authorizer:         authorizer,
+	}
+}
+
+// GetEffectivePermissions lists everything the role can do, marking each
+// permission as granted directly and/or inherited from a role below it.
+func (s *roleService) GetEffectivePermissions(ctx context.Context, roleID uint) (*role.EffectivePermissionsResponse, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hierarchy := newRoleHierarchy(roles)
+
+	target, ok := hierarchy.roles[roleID]
+	if !ok {
+		return nil, ErrRoleNotFound
+	}
+
+	rolePermissions, err := s.permissionRepo.FindRolePermissionsByRoleIDs(ctx, hierarchy.expand([]uint{roleID}))
+	if err != nil {
+		return nil, err
+	}
+
+	byPermission := make(map[uint]*role.EffectivePermission)
+	for _, rp := range rolePermissions {
+		if rp.Permission == nil {
+			continue
+		}
+		entry, ok := byPermission[rp.PermissionID]
+		if !ok {
+			entry = &role.EffectivePermission{
+				ID:            rp.Permission.ID,
+				Code:          rp.Permission.Code,
+				Name:          rp.Permission.Name,
+				InheritedFrom: []role.RoleRef{},
+			}
+			byPermission[rp.PermissionID] = entry
+		}
+
+		if rp.RoleID == roleID {
+			entry.Direct = true
+			continue
+		}
+		source := hierarchy.roles[rp.RoleID]
+		entry.InheritedFrom = append(entry.InheritedFrom, role.RoleRef{ID: source.ID, Name: source.Name})
+	}
+
+	permissions := make([]role.EffectivePermission, 0, len(byPermission))
+	for _, entry := range byPermission {
+		sort.Slice(entry.InheritedFrom, func(i, j int) bool {
+			return entry.InheritedFrom[i].ID < entry.InheritedFrom[j].ID
+		})
+		permissions = append(permissions, *entry)
+	}
+	sort.Slice(permissions, func(i, j int) bool {
+		return permissions[i].Code < permissions[j].Code
+	})
+
+	return &role.EffectivePermissionsResponse{
+		Role:        role.RoleRef{ID: target.ID, Name: target.Name},
+		Level:       target.Level,
+		Permissions: permissions,
+	}, nil
+}
+
+// SetParent moves a role under a new parent, refusing changes that would
+// loop the hierarchy or put a role under someone less senior. The actor must
+// manage both the role and its new parent, since the parent inherits the
+// role's permissions.
+func (s *roleService) SetParent(ctx context.Context, roleID uint, payload role.SetParentRequest) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		roles, err := s.roleRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		hierarchy := newRoleHierarchy(roles)
+
+		target, ok := hierarchy.roles[roleID]
+		if !ok {
+			return ErrRoleNotFound
+		}
+
+		guard, err := s.newRoleGuard(ctx, hierarchy)
+		if err != nil {
+			return err
+		}
+		if err := guard.checkRole(target); err != nil {
+			return err
+		}
+
+		if payload.ParentRoleID != nil {
+			parent, ok := hierarchy.roles[*payload.ParentRoleID]
+			if !ok {
+				return ErrParentRoleNotFound
+			}
+			if err := guard.checkRole(parent); err != nil {
+				return err
+			}
+			if hierarchy.createsCycle(roleID, parent.ID) {
+				return ErrRoleHierarchyCycle
+			}
+			if parent.Level <= target.Level {
+				return ErrParentRoleNotSenior
+			}
+		}
+
+		previous := toRoleResponse(target)
+		if err := s.roleRepo.UpdateParent(ctx, roleID, payload.ParentRoleID, guard.actorID); err != nil {
+			return err
+		}
+		target.ParentRoleID = payload.ParentRoleID
+
+		return s.recordChange(ctx, roleID, guard.actorID, models.RoleChangeUpdated, previous, toRoleResponse(target))
+	})
+	if err != nil {
+		return err
+	}
+
+	// Inherited permissions of every role above the old and new parent changed.
+	s.authorizer.InvalidateAll()
+	return nil
+}
+
+func (s *roleService) ListUserRoles(ctx context.Context, userID uint) ([]role.UserRoleResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	userRoles, err := s.userRoleRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]role.UserRoleResponse, 0, len(userRoles))
+	for _, userRole := range userRoles {
+		if userRole.Role == nil {
+			continue
+		}
+		res = append(res, role.UserRoleResponse{
+			Role:         role.RoleRef{ID: userRole.Role.ID, Name: userRole.Role.Name},
+			Level:        userRole.Role.Level,
+			AssignedByID: userRole.AssignedByID,
+			AssignedAt:   userRole.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *roleService) AssignRole(ctx context.Context, userID uint, payload role.AssignRoleRequest) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, currentRoleIDs, guard, err := s.loadAssignment(ctx, userID, payload.RoleID)
+		if err != nil {
+			return err
+		}
+		if err := guard.checkUser(userID, currentRoleIDs); err != nil {
+			return err
+		}
+		if err := guard.checkRole(target); err != nil {
+			return err
+		}
+
+		existing, err := s.userRoleRepo.FindByUserAndRole(ctx, userID, target.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrRoleAlreadyAssigned
+		}
+
+		return s.userRoleRepo.Create(ctx, &models.UserRole{
+			UserID:       userID,
+			RoleID:       target.ID,
+			AssignedByID: &guard.actorID,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.authorizer.InvalidateUser(userID)
+	return nil
+}
+
+func (s *roleService) UnassignRole(ctx context.Context, userID, roleID uint) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, currentRoleIDs, guard, err := s.loadAssignment(ctx, userID, roleID)
+		if err != nil {
+			return err
+		}
+		if err := guard.checkUser(userID, currentRoleIDs); err != nil {
+			return err
+		}
+		if err := guard.checkRole(target); err != nil {
+			return err
+		}
+
+		existing, err := s.userRoleRepo.FindByUserAndRole(ctx, userID, target.ID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrRoleNotAssigned
+		}
+
+		return s.userRoleRepo.Delete(ctx, existing)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.authorizer.InvalidateUser(userID)
+	return nil
+}
+
+// loadAssignment fetches what both assign and unassign need to run their checks.
+func (s *roleService) loadAssignment(ctx context.Context, userID, roleID uint) (*models.Role, []uint, *roleGuard, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, nil, ErrUserNotFound
+	}
+
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	hierarchy := newRoleHierarchy(roles)
+
+	target, ok := hierarchy.roles[roleID]
+	if !ok {
+		return nil, nil, nil, ErrRoleNotFound
+	}
+
+	currentRoleIDs, err := s.userRoleRepo.FindRoleIDsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	guard, err := s.newRoleGuard(ctx, hierarchy)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return target, currentRoleIDs, guard, nil
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"backend/internal/dto/role"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type permissionSnapshot struct {
+	PermissionID uint   `json:"permission_id"`
+	Code         string `json:"code"`
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]role.RoleResponse, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]role.RoleResponse, 0, len(roles))
+	for i := range roles {
+		res = append(res, toRoleResponse(&roles[i]))
+	}
+	return res, nil
+}
+
+func (s *roleService) GetRole(ctx context.Context, roleID uint) (*role.RoleDetailResponse, error) {
+	target, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	rolePermissions, err := s.permissionRepo.FindRolePermissionsByRoleIDs(ctx, []uint{roleID})
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := make([]role.PermissionResponse, 0, len(rolePermissions))
+	for _, rp := range rolePermissions {
+		if rp.Permission != nil {
+			permissions = append(permissions, toPermissionResponse(rp.Permission))
+		}
+	}
+
+	return &role.RoleDetailResponse{
+		RoleResponse: toRoleResponse(target),
+		Permissions:  permissions,
+	}, nil
+}
+
+// CreateRole adds a role below the actor's own seniority. Every one of the
+// actor's roles that outranks the new role becomes one of its managers, so
+// the creator can go on to assign and edit it.
+func (s *roleService) CreateRole(ctx context.Context, payload role.CreateRoleRequest) (*role.RoleResponse, error) {
+	var created *models.Role
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		roles, err := s.roleRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		hierarchy := newRoleHierarchy(roles)
+
+		guard, err := s.newRoleGuard(ctx, hierarchy)
+		if err != nil {
+			return err
+		}
+		if payload.Level >= guard.level {
+			return ErrRoleEscalation
+		}
+
+		if payload.ParentRoleID != nil {
+			parent, ok := hierarchy.roles[*payload.ParentRoleID]
+			if !ok {
+				return ErrParentRoleNotFound
+			}
+			if err := guard.checkRole(parent); err != nil {
+				return err
+			}
+			if parent.Level <= payload.Level {
+				return ErrParentRoleNotSenior
+			}
+		}
+
+		name := strings.TrimSpace(payload.Name)
+		if err := s.ensureRoleNameAvailable(ctx, name, 0); err != nil {
+			return err
+		}
+
+		created = &models.Role{
+			Name:         name,
+			Description:  payload.Description,
+			ParentRoleID: payload.ParentRoleID,
+			Level:        payload.Level,
+			MFARequired:  payload.MFARequired,
+			CreatedByID:  &guard.actorID,
+			UpdatedByID:  &guard.actorID,
+		}
+		if err := s.roleRepo.Create(ctx, created); err != nil {
+			return err
+		}
+
+		for _, managerRoleID := range guard.actorRoleIDs {
+			if hierarchy.roles[managerRoleID].Level <= created.Level {
+				continue
+			}
+			err := s.roleManagementRepo.Create(ctx, &models.RoleManagementPermission{
+				ManagerRoleID:    managerRoleID,
+				ManageableRoleID: created.ID,
+				CreatedByID:      &guard.actorID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.recordChange(ctx, created.ID, guard.actorID, models.RoleChangeCreated, nil, toRoleResponse(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(created)
+	return &res, nil
+}
+
+func (s *roleService) UpdateRole(ctx context.Context, roleID uint, payload role.UpdateRoleRequest) (*role.RoleResponse, error) {
+	var updated *models.Role
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		roles, err := s.roleRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		hierarchy := newRoleHierarchy(roles)
+
+		target, ok := hierarchy.roles[roleID]
+		if !ok {
+			return ErrRoleNotFound
+		}
+
+		guard, err := s.newRoleGuard(ctx, hierarchy)
+		if err != nil {
+			return err
+		}
+		if err := guard.checkRole(target); err != nil {
+			return err
+		}
+
+		previous := toRoleResponse(target)
+
+		if payload.Name != nil {
+			name := strings.TrimSpace(*payload.Name)
+			if err := s.ensureRoleNameAvailable(ctx, name, target.ID); err != nil {
+				return err
+			}
+			target.Name = name
+		}
+		if payload.Description != nil {
+			target.Description = *payload.Description
+		}
+		if payload.MFARequired != nil {
+			target.MFARequired = *payload.MFARequired
+		}
+		if payload.Level != nil && *payload.Level != target.Level {
+			if *payload.Level >= guard.level {
+				return ErrRoleEscalation
+			}
+			// Keep the tree ordered by seniority: above every child, below the parent.
+			if target.ParentRoleID != nil {
+				if parent, ok := hierarchy.roles[*target.ParentRoleID]; ok && parent.Level <= *payload.Level {
+					return ErrParentRoleNotSenior
+				}
+			}
+			for _, childID := range hierarchy.children[target.ID] {
+				if hierarchy.roles[childID].Level >= *payload.Level {
+					return ErrParentRoleNotSenior
+				}
+			}
+			target.Level = *payload.Level
+		}
+		target.UpdatedByID = &guard.actorID
+
+		if err := s.roleRepo.Update(ctx, target); err != nil {
+			return err
+		}
+
+		updated = target
+		return s.recordChange(ctx, target.ID, guard.actorID, models.RoleChangeUpdated, previous, toRoleResponse(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toRoleResponse(updated)
+	return &res, nil
+}
+
+// DeleteRole soft-deletes a custom role that nobody holds and nothing sits
+// beneath. System roles cannot be deleted.
+func (s *roleService) DeleteRole(ctx context.Context, roleID uint) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		roles, err := s.roleRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		hierarchy := newRoleHierarchy(roles)
+
+		target, ok := hierarchy.roles[roleID]
+		if !ok {
+			return ErrRoleNotFound
+		}
+		if target.IsSystemRole {
+			return ErrSystemRoleProtected
+		}
+
+		guard, err := s.newRoleGuard(ctx, hierarchy)
+		if err != nil {
+			return err
+		}
+		if err := guard.checkRole(target); err != nil {
+			return err
+		}
+
+		holders, err := s.userRoleRepo.CountByRoleID(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		if holders > 0 || len(hierarchy.children[roleID]) > 0 {
+			return ErrRoleInUse
+		}
+
+		if err := s.recordChange(ctx, roleID, guard.actorID, models.RoleChangeDeleted, toRoleResponse(target), nil); err != nil {
+			return err
+		}
+		return s.roleRepo.Delete(ctx, target)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Roles above this one no longer inherit its permissions.
+	s.authorizer.InvalidateAll()
+	return nil
+}
+
+func (s *roleService) GetRoleHistory(ctx context.Context, roleID uint) ([]role.RoleChangeResponse, error) {
+	target, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	history, err := s.historyRepo.FindByRoleID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]role.RoleChangeResponse, 0, len(history))
+	for _, h := range history {
+		res = append(res, role.RoleChangeResponse{
+			ID:           h.ID,
+			Action:       h.Action,
+			ChangedByID:  h.ChangedByID,
+			PreviousData: json.RawMessage(h.PreviousData),
+			NewData:      json.RawMessage(h.NewData),
+			CreatedAt:    h.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *roleService) ListPermissions(ctx context.Context) ([]role.PermissionCategoryResponse, error) {
+	categories, err := s.permissionRepo.FindCategoriesWithPermissions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]role.PermissionCategoryResponse, 0, len(categories))
+	for _, category := range categories {
+		permissions := make([]role.PermissionResponse, 0, len(category.Permissions))
+		for _, permission := range category.Permissions {
+			permissions = append(permissions, toPermissionResponse(permission))
+		}
+		res = append(res, role.PermissionCategoryResponse{
+			ID:          category.ID,
+			Name:        category.Name,
+			Description: category.Description,
+			Permissions: permissions,
+		})
+	}
+	return res, nil
+}
+
+// GrantPermission adds a permission to a role the actor manages. Actors can
+// only hand out permissions they hold themselves.
+func (s *roleService) GrantPermission(ctx context.Context, roleID uint, payload role.GrantPermissionRequest) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, guard, err := s.loadManagedRole(ctx, roleID)
+		if err != nil {
+			return err
+		}
+
+		permission, err := s.permissionRepo.FindByID(ctx, payload.PermissionID)
+		if err != nil {
+			return err
+		}
+		if permission == nil {
+			return ErrPermissionNotFound
+		}
+		if p, _ := principal.FromContext(ctx); !p.HasPermission(permission.Code) {
+			return ErrPermissionEscalation
+		}
+
+		existing, err := s.permissionRepo.FindRolePermission(ctx, target.ID, permission.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrPermissionAlreadyGranted
+		}
+
+		err = s.permissionRepo.CreateRolePermission(ctx, &models.RolePermission{
+			RoleID:       target.ID,
+			PermissionID: permission.ID,
+			GrantedByID:  &guard.actorID,
+			GrantedAt:    time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.recordChange(ctx, target.ID, guard.actorID, models.RoleChangePermissionAdded, nil, permissionSnapshot{
+			PermissionID: permission.ID,
+			Code:         permission.Code,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.authorizer.InvalidateAll()
+	return nil
+}
+
+func (s *roleService) RevokePermission(ctx context.Context, roleID, permissionID uint) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, guard, err := s.loadManagedRole(ctx, roleID)
+		if err != nil {
+			return err
+		}
+
+		existing, err := s.permissionRepo.FindRolePermission(ctx, target.ID, permissionID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return ErrPermissionNotGranted
+		}
+
+		if err := s.permissionRepo.DeleteRolePermission(ctx, existing); err != nil {
+			return err
+		}
+
+		previous := permissionSnapshot{PermissionID: existing.PermissionID}
+		if existing.Permission != nil {
+			previous.Code = existing.Permission.Code
+		}
+		return s.recordChange(ctx, target.ID, guard.actorID, models.RoleChangePermissionRemoved, previous, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.authorizer.InvalidateAll()
+	return nil
+}
+
+func (s *roleService) loadManagedRole(ctx context.Context, roleID uint) (*models.Role, *roleGuard, error) {
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	hierarchy := newRoleHierarchy(roles)
+
+	target, ok := hierarchy.roles[roleID]
+	if !ok {
+		return nil, nil, ErrRoleNotFound
+	}
+
+	guard, err := s.newRoleGuard(ctx, hierarchy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := guard.checkRole(target); err != nil {
+		return nil, nil, err
+	}
+
+	return target, guard, nil
+}
+
+func (s *roleService) ensureRoleNameAvailable(ctx context.Context, name string, exceptID uint) error {
+	existing, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return ErrRoleNameTaken
+	}
+	return nil
+}
+
+// recordChange writes a RoleChangeHistory row. previous and next are stored
+// as JSON; pass nil for the side that does not exist.
+func (s *roleService) recordChange(ctx context.Context, roleID, actorID uint, action string, previous, next interface{}) error {
+	history := &models.RoleChangeHistory{
+		RoleID:      roleID,
+		ChangedByID: actorID,
+		Action:      action,
+		CreatedAt:   time.Now(),
+	}
+
+	if previous != nil {
+		data, err := json.Marshal(previous)
+		if err != nil {
+			return err
+		}
+		history.PreviousData = datatypes.JSON(data)
+	}
+	if next != nil {
+		data, err := json.Marshal(next)
+		if err != nil {
+			return err
+		}
+		history.NewData = datatypes.JSON(data)
+	}
+
+	return s.historyRepo.Create(ctx, history)
+}
+
+func toRoleResponse(r *models.Role) role.RoleResponse {
+	return role.RoleResponse{
+		ID:           r.ID,
+		Name:         r.Name,
+		Description:  r.Description,
+		ParentRoleID: r.ParentRoleID,
+		Level:        r.Level,
+		IsSystemRole: r.IsSystemRole,
+		MFARequired:  r.MFARequired,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+	}
+}
+
+func toPermissionResponse(p *models.Permission) role.PermissionResponse {
+	return role.PermissionResponse{
+		ID:          p.ID,
+		Code:        p.Code,
+		Name:        p.Name,
+		Description: p.Description,
+	}
+}
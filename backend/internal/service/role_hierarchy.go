@@ -0,0 +1,86 @@
+package service
+
+import "backend/internal/model"
+
+// roleHierarchy is an in-memory view of the Role.ParentRoleID tree.
+//
+// ParentRoleID points at the more senior role (see Role.Level), so grants
+// flow up the tree: a role holds its own permissions plus everything
+// granted to the roles beneath it. A Captain can therefore do whatever a
+// Lieutenant can, but never the other way round.
+type roleHierarchy struct {
+	roles    map[uint]*models.Role
+	children map[uint][]uint
+}
+
+func newRoleHierarchy(roles []models.Role) *roleHierarchy {
+	h := &roleHierarchy{
+		roles:    make(map[uint]*models.Role, len(roles)),
+		children: make(map[uint][]uint),
+	}
+	for i := range roles {
+		role := &roles[i]
+		h.roles[role.ID] = role
+		if role.ParentRoleID != nil {
+			h.children[*role.ParentRoleID] = append(h.children[*role.ParentRoleID], role.ID)
+		}
+	}
+	return h
+}
+
+// expand returns roleIDs together with every role they inherit from.
+func (h *roleHierarchy) expand(roleIDs []uint) []uint {
+	seen := make(map[uint]bool)
+	var result []uint
+	queue := append([]uint(nil), roleIDs...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+		queue = append(queue, h.children[id]...)
+	}
+	return result
+}
+
+// descendants returns the roles below roleID, excluding roleID itself.
+func (h *roleHierarchy) descendants(roleID uint) []uint {
+	return h.expand([]uint{roleID})[1:]
+}
+
+// highestLevel returns the most senior Level among roleIDs, or 0 if none exist.
+func (h *roleHierarchy) highestLevel(roleIDs []uint) int {
+	level := 0
+	for _, id := range roleIDs {
+		if role, ok := h.roles[id]; ok && role.Level > level {
+			level = role.Level
+		}
+	}
+	return level
+}
+
+// createsCycle reports whether making parentID the parent of roleID would
+// loop back to roleID.
+func (h *roleHierarchy) createsCycle(roleID, parentID uint) bool {
+	seen := make(map[uint]bool)
+	for current := &parentID; current != nil; {
+		if *current == roleID {
+			return true
+		}
+		if seen[*current] {
+			// An existing loop that does not involve roleID.
+			return true
+		}
+		seen[*current] = true
+
+		role, ok := h.roles[*current]
+		if !ok {
+			return false
+		}
+		current = role.ParentRoleID
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+)
+
+// roleGuard decides which roles an actor may assign, unassign or edit.
+//
+// An actor may manage a role only when one of their own roles is listed as
+// its ManagerRole in RoleManagementPermission, and only when the role sits
+// below the actor's most senior role. The level check stops escalation even
+// if the management table is misconfigured.
+type roleGuard struct {
+	actorID      uint
+	actorRoleIDs []uint
+	level        int
+	manageable   map[uint]bool
+	hierarchy    *roleHierarchy
+}
+
+func (s *roleService) newRoleGuard(ctx context.Context, hierarchy *roleHierarchy) (*roleGuard, error) {
+	return loadRoleGuard(ctx, s.userRoleRepo, s.roleManagementRepo, hierarchy)
+}
+
+// loadRoleGuard builds the guard for the principal in ctx.
+func loadRoleGuard(
+	ctx context.Context,
+	userRoleRepo repository.UserRoleRepository,
+	roleManagementRepo repository.RoleManagementRepository,
+	hierarchy *roleHierarchy,
+) (*roleGuard, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	actorRoleIDs, err := userRoleRepo.FindRoleIDsByUserID(ctx, p.UserID())
+	if err != nil {
+		return nil, err
+	}
+	manageableIDs, err := roleManagementRepo.FindManageableRoleIDs(ctx, actorRoleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	guard := &roleGuard{
+		actorID:      p.UserID(),
+		actorRoleIDs: actorRoleIDs,
+		level:        hierarchy.highestLevel(actorRoleIDs),
+		manageable:   make(map[uint]bool, len(manageableIDs)),
+		hierarchy:    hierarchy,
+	}
+	for _, id := range manageableIDs {
+		guard.manageable[id] = true
+	}
+	return guard, nil
+}
+
+func (g *roleGuard) checkRole(role *models.Role) error {
+	if !g.manageable[role.ID] {
+		return ErrRoleNotManageable
+	}
+	if role.Level >= g.level {
+		return ErrRoleEscalation
+	}
+	return nil
+}
+
+// checkUser stops actors from reshaping the roles of someone at or above
+// their own seniority.
+func (g *roleGuard) checkUser(userID uint, userRoleIDs []uint) error {
+	if userID == g.actorID {
+		return nil
+	}
+	if g.hierarchy.highestLevel(userRoleIDs) >= g.level {
+		return ErrUserOutranksActor
+	}
+	return nil
+}
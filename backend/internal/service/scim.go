@@ -0,0 +1,847 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/scim"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// defaultScimCount is the page size of SCIM list requests without count.
+const defaultScimCount = 100
+
+// ScimService provisions users and roles for an external identity system
+// over SCIM 2.0. SCIM Users are models.User, keyed by email as userName;
+// SCIM Groups are models.Role, and group membership is role assignment.
+//
+// Requests are authenticated with a shared bearer token rather than as a
+// user, so there is no principal: audit entries have no actor and the role
+// hierarchy checks of the admin API do not apply.
+type ScimService interface {
+	ListUsers(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error)
+	GetUser(ctx context.Context, id string) (*scim.User, error)
+	CreateUser(ctx context.Context, payload scim.User) (*scim.User, error)
+	ReplaceUser(ctx context.Context, id string, payload scim.User) (*scim.User, error)
+	PatchUser(ctx context.Context, id string, payload scim.PatchRequest) (*scim.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	ListGroups(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error)
+	GetGroup(ctx context.Context, id string) (*scim.Group, error)
+	CreateGroup(ctx context.Context, payload scim.Group) (*scim.Group, error)
+	ReplaceGroup(ctx context.Context, id string, payload scim.Group) (*scim.Group, error)
+	PatchGroup(ctx context.Context, id string, payload scim.PatchRequest) (*scim.Group, error)
+	DeleteGroup(ctx context.Context, id string) error
+}
+
+type scimService struct {
+	scimRepo       repository.ScimRepository
+	userRepo       repository.UserRepository
+	userRoleRepo   repository.UserRoleRepository
+	roleRepo       repository.RoleRepository
+	departmentRepo repository.DepartmentRepository
+	auditRepo      repository.AuditLogRepository
+	txManager      repository.TransactionManager
+	sessionService SessionService
+	authorizer     Authorizer
+}
+
+func NewScimService(
+	scimRepo repository.ScimRepository,
+	userRepo repository.UserRepository,
+	userRoleRepo repository.UserRoleRepository,
+	roleRepo repository.RoleRepository,
+	departmentRepo repository.DepartmentRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	sessionService SessionService,
+	authorizer Authorizer,
+) ScimService {
+	return &scimService{
+		scimRepo:       scimRepo,
+		userRepo:       userRepo,
+		userRoleRepo:   userRoleRepo,
+		roleRepo:       roleRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+		sessionService: sessionService,
+		authorizer:     authorizer,
+	}
+}
+
+func (s *scimService) ListUsers(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error) {
+	filter, offset, limit, err := parseScimListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	users, total, err := s.scimRepo.ListUsers(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(users))
+	for i := range users {
+		ids = append(ids, users[i].ID)
+	}
+	groups, err := s.userGroups(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := newScimListResponse(total, offset, len(users))
+	for i := range users {
+		res.Resources = append(res.Resources, toScimUser(&users[i], groups[users[i].ID]))
+	}
+	return res, nil
+}
+
+func (s *scimService) GetUser(ctx context.Context, id string) (*scim.User, error) {
+	target, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadUser(ctx, target)
+}
+
+// CreateUser adds an active account without a password. Provisioned users
+// sign in through single sign-on or set a password with "forgot password".
+func (s *scimService) CreateUser(ctx context.Context, payload scim.User) (*scim.User, error) {
+	fields, err := scimUserFields(payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &models.User{
+		Email:       fields.email,
+		FirstName:   fields.firstName,
+		LastName:    fields.lastName,
+		PhoneNumber: fields.phoneNumber,
+		BadgeNumber: fields.badgeNumber,
+		IsActive:    true,
+	}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ensureUserUnique(ctx, created.Email, created.BadgeNumber, 0); err != nil {
+			return err
+		}
+		if err := s.userRepo.Create(ctx, created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return ErrEmailTaken
+			}
+			return err
+		}
+		// IsActive defaults to true on insert, so an inactive user is
+		// deactivated afterwards.
+		if !fields.active {
+			if err := s.userRepo.UpdateActive(ctx, created.ID, false); err != nil {
+				return err
+			}
+			created.IsActive = false
+		}
+
+		return s.audit(ctx, models.AuditUserCreated, "user", created.ID, auditChange{New: toUserResponse(created)})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadUser(ctx, created)
+}
+
+// ReplaceUser applies a full representation of the user. Active and the
+// badge extension are left unchanged when the payload omits them.
+func (s *scimService) ReplaceUser(ctx context.Context, id string, payload scim.User) (*scim.User, error) {
+	return s.updateUser(ctx, id, func(*scim.User) (scim.User, error) {
+		return payload, nil
+	})
+}
+
+func (s *scimService) PatchUser(ctx context.Context, id string, payload scim.PatchRequest) (*scim.User, error) {
+	return s.updateUser(ctx, id, func(current *scim.User) (scim.User, error) {
+		var patched scim.User
+		err := applyScimPatch(current, payload.Operations, &patched)
+		return patched, err
+	})
+}
+
+// DeleteUser soft-deletes the user and ends their sessions, as the admin
+// API does.
+func (s *scimService) DeleteUser(ctx context.Context, id string) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.findUser(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sessionService.EndAllSessions(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.departmentRepo.ClearHead(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.userRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+
+		return s.audit(ctx, models.AuditUserDeleted, "user", target.ID, auditChange{Previous: toUserResponse(target)})
+	})
+}
+
+// updateUser loads the user, builds the desired representation from the
+// current one and saves the differences.
+func (s *scimService) updateUser(ctx context.Context, id string, build func(current *scim.User) (scim.User, error)) (*scim.User, error) {
+	var target *models.User
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		target, err = s.findUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		current, err := s.loadUser(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		desired, err := build(current)
+		if err != nil {
+			return err
+		}
+		fields, err := scimUserFields(desired, target)
+		if err != nil {
+			return err
+		}
+
+		previous := toUserResponse(target)
+		if err := s.ensureUserUnique(ctx, fields.email, fields.badgeNumber, target.ID); err != nil {
+			return err
+		}
+
+		updated := *target
+		updated.Email = fields.email
+		updated.FirstName = fields.firstName
+		updated.LastName = fields.lastName
+		updated.PhoneNumber = fields.phoneNumber
+		updated.BadgeNumber = fields.badgeNumber
+		next := toUserResponse(&updated)
+		if next.Email != previous.Email || next.FirstName != previous.FirstName || next.LastName != previous.LastName ||
+			next.PhoneNumber != previous.PhoneNumber || next.BadgeNumber != previous.BadgeNumber {
+			if err := s.userRepo.UpdateProfile(ctx, &updated); err != nil {
+				if errors.Is(err, repository.ErrDuplicateKey) {
+					return ErrEmailTaken
+				}
+				return err
+			}
+			if err := s.audit(ctx, models.AuditUserUpdated, "user", target.ID, auditChange{Previous: previous, New: next}); err != nil {
+				return err
+			}
+		}
+
+		if fields.active != target.IsActive {
+			if err := s.userRepo.UpdateActive(ctx, target.ID, fields.active); err != nil {
+				return err
+			}
+			action := models.AuditUserReactivated
+			if !fields.active {
+				action = models.AuditUserDeactivated
+				if err := s.sessionService.EndAllSessions(ctx, target.ID); err != nil {
+					return err
+				}
+			}
+			if err := s.audit(ctx, action, "user", target.ID, nil); err != nil {
+				return err
+			}
+		}
+
+		updated.IsActive = fields.active
+		target = &updated
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadUser(ctx, target)
+}
+
+func (s *scimService) ListGroups(ctx context.Context, query scim.ListQuery) (*scim.ListResponse, error) {
+	filter, offset, limit, err := parseScimListQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, total, err := s.scimRepo.ListGroups(ctx, filter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(roles))
+	for i := range roles {
+		ids = append(ids, roles[i].ID)
+	}
+	members, err := s.groupMembers(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := newScimListResponse(total, offset, len(roles))
+	for i := range roles {
+		res.Resources = append(res.Resources, toScimGroup(&roles[i], members[roles[i].ID]))
+	}
+	return res, nil
+}
+
+func (s *scimService) GetGroup(ctx context.Context, id string) (*scim.Group, error) {
+	target, err := s.findRole(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadGroup(ctx, target)
+}
+
+// CreateGroup adds a role with no permissions at the lowest level. Admins
+// grant it permissions through the role API.
+func (s *scimService) CreateGroup(ctx context.Context, payload scim.Group) (*scim.Group, error) {
+	name, err := scimGroupName(payload)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs, err := scimMemberIDs(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	created := &models.Role{Name: name}
+	var changed []uint
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.ensureRoleNameAvailable(ctx, name, 0); err != nil {
+			return err
+		}
+		if err := s.roleRepo.Create(ctx, created); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, models.AuditRoleCreated, "role", created.ID, auditChange{New: toRoleResponse(created)}); err != nil {
+			return err
+		}
+
+		changed, err = s.setMembers(ctx, created.ID, memberIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUsers(changed)
+	return s.loadGroup(ctx, created)
+}
+
+func (s *scimService) ReplaceGroup(ctx context.Context, id string, payload scim.Group) (*scim.Group, error) {
+	return s.updateGroup(ctx, id, func(*scim.Group) (scim.Group, error) {
+		return payload, nil
+	})
+}
+
+func (s *scimService) PatchGroup(ctx context.Context, id string, payload scim.PatchRequest) (*scim.Group, error) {
+	return s.updateGroup(ctx, id, func(current *scim.Group) (scim.Group, error) {
+		var patched scim.Group
+		err := applyScimPatch(current, payload.Operations, &patched)
+		return patched, err
+	})
+}
+
+// DeleteGroup deletes the role and its assignments. System roles and roles
+// with child roles cannot be deleted.
+func (s *scimService) DeleteGroup(ctx context.Context, id string) error {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		roles, err := s.roleRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		hierarchy := newRoleHierarchy(roles)
+
+		roleID, ok := parseScimResourceID(id)
+		target := hierarchy.roles[roleID]
+		if !ok || target == nil {
+			return ErrRoleNotFound
+		}
+		if target.IsSystemRole {
+			return ErrSystemRoleProtected
+		}
+		if len(hierarchy.children[roleID]) > 0 {
+			return ErrRoleInUse
+		}
+
+		if _, err := s.setMembers(ctx, target.ID, nil); err != nil {
+			return err
+		}
+		if err := s.audit(ctx, models.AuditRoleDeleted, "role", target.ID, auditChange{Previous: toRoleResponse(target)}); err != nil {
+			return err
+		}
+		return s.roleRepo.Delete(ctx, target)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.authorizer.InvalidateAll()
+	return nil
+}
+
+// updateGroup loads the role, builds the desired representation from the
+// current one and saves the differences. System roles keep their name.
+func (s *scimService) updateGroup(ctx context.Context, id string, build func(current *scim.Group) (scim.Group, error)) (*scim.Group, error) {
+	var target *models.Role
+	var changed []uint
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		target, err = s.findRole(ctx, id)
+		if err != nil {
+			return err
+		}
+		current, err := s.loadGroup(ctx, target)
+		if err != nil {
+			return err
+		}
+
+		desired, err := build(current)
+		if err != nil {
+			return err
+		}
+		name, err := scimGroupName(desired)
+		if err != nil {
+			return err
+		}
+		memberIDs, err := scimMemberIDs(desired)
+		if err != nil {
+			return err
+		}
+
+		if name != target.Name {
+			if target.IsSystemRole {
+				return &scim.Error{ScimType: scim.ErrTypeMutability, Detail: "system roles cannot be renamed"}
+			}
+			if err := s.ensureRoleNameAvailable(ctx, name, target.ID); err != nil {
+				return err
+			}
+			previous := toRoleResponse(target)
+			target.Name = name
+			if err := s.roleRepo.Update(ctx, target); err != nil {
+				return err
+			}
+			if err := s.audit(ctx, models.AuditRoleUpdated, "role", target.ID, auditChange{Previous: previous, New: toRoleResponse(target)}); err != nil {
+				return err
+			}
+		}
+
+		changed, err = s.setMembers(ctx, target.ID, memberIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateUsers(changed)
+	return s.loadGroup(ctx, target)
+}
+
+type roleMembersChange struct {
+	Added   []uint `json:"added,omitempty"`
+	Removed []uint `json:"removed,omitempty"`
+}
+
+// setMembers makes userIDs the exact members of the role and returns the
+// users whose roles changed.
+func (s *scimService) setMembers(ctx context.Context, roleID uint, userIDs []uint) ([]uint, error) {
+	current, err := s.scimRepo.FindRoleMembers(ctx, []uint{roleID})
+	if err != nil {
+		return nil, err
+	}
+
+	desired := make(map[uint]bool, len(userIDs))
+	for _, id := range userIDs {
+		desired[id] = true
+	}
+
+	var change roleMembersChange
+	existing := make(map[uint]bool, len(current))
+	for i := range current {
+		existing[current[i].UserID] = true
+		if desired[current[i].UserID] {
+			continue
+		}
+		if err := s.userRoleRepo.Delete(ctx, &current[i]); err != nil {
+			return nil, err
+		}
+		change.Removed = append(change.Removed, current[i].UserID)
+	}
+
+	for _, id := range userIDs {
+		if existing[id] {
+			continue
+		}
+		member, err := s.userRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if member == nil {
+			return nil, &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: fmt.Sprintf("member %d does not exist", id)}
+		}
+		if err := s.userRoleRepo.Create(ctx, &models.UserRole{UserID: id, RoleID: roleID}); err != nil {
+			return nil, err
+		}
+		existing[id] = true
+		change.Added = append(change.Added, id)
+	}
+
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return nil, nil
+	}
+	if err := s.audit(ctx, models.AuditRoleMembersChanged, "role", roleID, change); err != nil {
+		return nil, err
+	}
+	return append(change.Added, change.Removed...), nil
+}
+
+func (s *scimService) invalidateUsers(userIDs []uint) {
+	for _, id := range userIDs {
+		s.authorizer.InvalidateUser(id)
+	}
+}
+
+func (s *scimService) findUser(ctx context.Context, id string) (*models.User, error) {
+	userID, ok := parseScimResourceID(id)
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	target, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+	return target, nil
+}
+
+func (s *scimService) findRole(ctx context.Context, id string) (*models.Role, error) {
+	roleID, ok := parseScimResourceID(id)
+	if !ok {
+		return nil, ErrRoleNotFound
+	}
+	target, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrRoleNotFound
+	}
+	return target, nil
+}
+
+func (s *scimService) loadUser(ctx context.Context, u *models.User) (*scim.User, error) {
+	groups, err := s.userGroups(ctx, []uint{u.ID})
+	if err != nil {
+		return nil, err
+	}
+	return toScimUser(u, groups[u.ID]), nil
+}
+
+func (s *scimService) loadGroup(ctx context.Context, r *models.Role) (*scim.Group, error) {
+	members, err := s.groupMembers(ctx, []uint{r.ID})
+	if err != nil {
+		return nil, err
+	}
+	return toScimGroup(r, members[r.ID]), nil
+}
+
+func (s *scimService) userGroups(ctx context.Context, userIDs []uint) (map[uint][]scim.MultiValue, error) {
+	groups := make(map[uint][]scim.MultiValue, len(userIDs))
+	if len(userIDs) == 0 {
+		return groups, nil
+	}
+	userRoles, err := s.scimRepo.FindUserRoles(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ur := range userRoles {
+		if ur.Role == nil {
+			continue
+		}
+		groups[ur.UserID] = append(groups[ur.UserID], scim.MultiValue{
+			Value:   formatScimID(ur.RoleID),
+			Display: ur.Role.Name,
+		})
+	}
+	return groups, nil
+}
+
+func (s *scimService) groupMembers(ctx context.Context, roleIDs []uint) (map[uint][]scim.MultiValue, error) {
+	members := make(map[uint][]scim.MultiValue, len(roleIDs))
+	if len(roleIDs) == 0 {
+		return members, nil
+	}
+	userRoles, err := s.scimRepo.FindRoleMembers(ctx, roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ur := range userRoles {
+		if ur.User == nil {
+			continue
+		}
+		members[ur.RoleID] = append(members[ur.RoleID], scim.MultiValue{
+			Value:   formatScimID(ur.UserID),
+			Display: strings.TrimSpace(ur.User.FirstName + " " + ur.User.LastName),
+		})
+	}
+	return members, nil
+}
+
+func (s *scimService) ensureUserUnique(ctx context.Context, email, badgeNumber string, excludeID uint) error {
+	taken, err := s.userRepo.ExistsByEmail(ctx, email, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
+	}
+	if badgeNumber == "" {
+		return nil
+	}
+	taken, err = s.userRepo.ExistsByBadgeNumber(ctx, badgeNumber, excludeID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrBadgeNumberTaken
+	}
+	return nil
+}
+
+func (s *scimService) ensureRoleNameAvailable(ctx context.Context, name string, exceptID uint) error {
+	existing, err := s.roleRepo.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != exceptID {
+		return ErrRoleNameTaken
+	}
+	return nil
+}
+
+func (s *scimService) audit(ctx context.Context, action, entityType string, entityID uint, details interface{}) error {
+	entry, err := newAuditLog(ctx, nil, action, entityType, &entityID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+// scimUser holds the validated columns a SCIM User maps to.
+type scimUser struct {
+	email       string
+	firstName   string
+	lastName    string
+	phoneNumber string
+	badgeNumber string
+	active      bool
+}
+
+// scimUserFields validates payload. current is the stored user on updates;
+// its active flag and badge number are kept when payload omits them.
+func scimUserFields(payload scim.User, current *models.User) (scimUser, error) {
+	invalid := func(detail string) (scimUser, error) {
+		return scimUser{}, &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: detail}
+	}
+
+	var fields scimUser
+	fields.email = normalizeEmail(payload.UserName)
+	if fields.email == "" {
+		for _, e := range payload.Emails {
+			if e.Primary || fields.email == "" {
+				fields.email = normalizeEmail(e.Value)
+			}
+		}
+	}
+	if addr, err := mail.ParseAddress(fields.email); err != nil || addr.Address != fields.email || len(fields.email) > 100 {
+		return invalid("userName must be an email address of at most 100 characters")
+	}
+
+	if payload.Name != nil {
+		fields.firstName = strings.TrimSpace(payload.Name.GivenName)
+		fields.lastName = strings.TrimSpace(payload.Name.FamilyName)
+	}
+	if fields.firstName == "" && fields.lastName == "" {
+		if parts := strings.Fields(payload.DisplayName); len(parts) > 0 {
+			fields.firstName = strings.Join(parts[:len(parts)-1], " ")
+			fields.lastName = parts[len(parts)-1]
+		}
+	}
+	if fields.firstName == "" {
+		fields.firstName, _, _ = strings.Cut(fields.email, "@")
+	}
+	if len(fields.firstName) > 50 || len(fields.lastName) > 50 {
+		return invalid("name.givenName and name.familyName must be at most 50 characters")
+	}
+
+	for _, p := range payload.PhoneNumbers {
+		if p.Primary || fields.phoneNumber == "" {
+			fields.phoneNumber = strings.TrimSpace(p.Value)
+		}
+	}
+	if len(fields.phoneNumber) > 20 {
+		return invalid("phone numbers must be at most 20 characters")
+	}
+
+	fields.active = true
+	if current != nil {
+		fields.active = current.IsActive
+		fields.badgeNumber = current.BadgeNumber
+	}
+	if payload.Active != nil {
+		fields.active = *payload.Active
+	}
+	if payload.Extension != nil {
+		fields.badgeNumber = strings.TrimSpace(payload.Extension.BadgeNumber)
+	}
+	if len(fields.badgeNumber) > 20 {
+		return invalid("badgeNumber must be at most 20 characters")
+	}
+
+	return fields, nil
+}
+
+func scimGroupName(payload scim.Group) (string, error) {
+	name := strings.TrimSpace(payload.DisplayName)
+	if name == "" || len(name) > 50 {
+		return "", &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: "displayName is required and must be at most 50 characters"}
+	}
+	return name, nil
+}
+
+func scimMemberIDs(payload scim.Group) ([]uint, error) {
+	ids := make([]uint, 0, len(payload.Members))
+	for _, m := range payload.Members {
+		id, ok := parseScimResourceID(m.Value)
+		if !ok {
+			return nil, &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: fmt.Sprintf("invalid member %q", m.Value)}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// applyScimPatch applies the operations to a copy of current and decodes
+// the result into out.
+func applyScimPatch(current interface{}, ops []scim.PatchOperation, out interface{}) error {
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	var resource map[string]interface{}
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return err
+	}
+
+	if err := scim.ApplyPatch(resource, ops); err != nil {
+		return err
+	}
+
+	// Some clients send booleans as strings, such as "False".
+	for key, value := range resource {
+		if s, ok := value.(string); ok && strings.EqualFold(key, "active") {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: "active must be a boolean"}
+			}
+			resource[key] = b
+		}
+	}
+
+	raw, err = json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return &scim.Error{ScimType: scim.ErrTypeInvalidValue, Detail: "patched resource is invalid: " + err.Error()}
+	}
+	return nil
+}
+
+func parseScimListQuery(query scim.ListQuery) (scim.Filter, int, int, error) {
+	var filter scim.Filter
+	if query.Filter != "" {
+		f, err := scim.ParseFilter(query.Filter)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		filter = f
+	}
+
+	offset := 0
+	if query.StartIndex > 1 {
+		offset = query.StartIndex - 1
+	}
+	limit := defaultScimCount
+	if query.Count != nil {
+		limit = min(max(*query.Count, 0), scim.MaxResults)
+	}
+	return filter, offset, limit, nil
+}
+
+func newScimListResponse(total int64, offset, count int) *scim.ListResponse {
+	return &scim.ListResponse{
+		Schemas:      []string{scim.SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   offset + 1,
+		ItemsPerPage: count,
+		Resources:    make([]interface{}, 0, count),
+	}
+}
+
+func toScimUser(u *models.User, groups []scim.MultiValue) *scim.User {
+	active := u.IsActive
+	created, modified := u.CreatedAt, u.UpdatedAt
+	fullName := strings.TrimSpace(u.FirstName + " " + u.LastName)
+
+	res := &scim.User{
+		Schemas:     []string{scim.SchemaUser, scim.SchemaUserExtension},
+		ID:          formatScimID(u.ID),
+		UserName:    u.Email,
+		Name:        &scim.Name{Formatted: fullName, GivenName: u.FirstName, FamilyName: u.LastName},
+		DisplayName: fullName,
+		Emails:      []scim.MultiValue{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Groups:      groups,
+		Extension:   &scim.UserExtension{BadgeNumber: u.BadgeNumber},
+		Meta:        &scim.Meta{ResourceType: "User", Created: &created, LastModified: &modified},
+	}
+	if u.PhoneNumber != "" {
+		res.PhoneNumbers = []scim.MultiValue{{Value: u.PhoneNumber, Type: "work", Primary: true}}
+	}
+	return res
+}
+
+func toScimGroup(r *models.Role, members []scim.MultiValue) *scim.Group {
+	created, modified := r.CreatedAt, r.UpdatedAt
+	return &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          formatScimID(r.ID),
+		DisplayName: r.Name,
+		Members:     members,
+		Meta:        &scim.Meta{ResourceType: "Group", Created: &created, LastModified: &modified},
+	}
+}
+
+func formatScimID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func parseScimResourceID(id string) (uint, bool) {
+	n, err := strconv.ParseUint(id, 10, 0)
+	return uint(n), err == nil && n > 0
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"backend/internal/dto/search"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"html"
+	"strings"
+)
+
+const (
+	// PermissionViewEvidence lets the caller see the evidence of the cases
+	// they may see, in case details and search results.
+	PermissionViewEvidence = "evidence.view"
+	// PermissionViewConfidentialEvidence extends PermissionViewEvidence to
+	// confidential evidence.
+	PermissionViewConfidentialEvidence = "evidence.confidential"
+)
+
+var searchHighlighter = strings.NewReplacer(
+	repository.SearchHighlightStart, "<mark>",
+	repository.SearchHighlightStop, "</mark>",
+)
+
+// SearchService runs full-text searches over cases and their evidence.
+type SearchService interface {
+	Search(ctx context.Context, query search.SearchQuery) (*search.SearchResponse, error)
+}
+
+type searchService struct {
+	caseRepo repository.CaseRepository
+}
+
+func NewSearchService(caseRepo repository.CaseRepository) SearchService {
+	return &searchService{caseRepo: caseRepo}
+}
+
+// Search returns one page of the cases the caller may see that match the
+// query, in their own fields or in their evidence. Evidence only counts
+// when the caller may view it.
+func (s *searchService) Search(ctx context.Context, query search.SearchQuery) (*search.SearchResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	page := query.Page
+	if page == 0 {
+		page = 1
+	}
+
+	searchQuery := repository.CaseSearchQuery{
+		Text:             strings.TrimSpace(query.Q),
+		MatchEvidence:    p.HasPermission(PermissionViewEvidence),
+		ShowConfidential: p.HasPermission(PermissionViewConfidentialEvidence),
+		Offset:           (page - 1) * limit,
+		Limit:            limit,
+	}
+
+	hits, err := s.caseRepo.Search(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.caseRepo.CountSearch(ctx, searchQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.CaseID)
+	}
+	found, err := s.caseRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Case, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+	evidenceHits, err := s.caseRepo.SearchEvidence(ctx, searchQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	evidenceByCase := make(map[uint][]search.EvidenceHit)
+	for _, hit := range evidenceHits {
+		evidenceByCase[hit.CaseID] = append(evidenceByCase[hit.CaseID], search.EvidenceHit{
+			ID:    hit.EvidenceID,
+			Title: hit.Title,
+			Rank:  hit.Rank,
+			Highlights: search.EvidenceHighlights{
+				Title:       highlight(hit.TitleSnippet),
+				Description: highlight(hit.DescriptionSnippet),
+				Metadata:    highlight(hit.MetadataSnippet),
+			},
+		})
+	}
+
+	res := &search.SearchResponse{
+		Items:      make([]search.CaseHit, 0, len(hits)),
+		Pagination: offsetMeta(limit, page, total),
+	}
+	for _, hit := range hits {
+		c, ok := byID[hit.CaseID]
+		if !ok {
+			continue // deleted since the search ran
+		}
+		evidence := evidenceByCase[hit.CaseID]
+		if evidence == nil {
+			evidence = []search.EvidenceHit{}
+		}
+		res.Items = append(res.Items, search.CaseHit{
+			Case: toCaseResponse(c),
+			Rank: hit.Rank,
+			Highlights: search.CaseHighlights{
+				Title:       highlight(hit.TitleSnippet),
+				Description: highlight(hit.DescriptionSnippet),
+				Location:    highlight(hit.LocationSnippet),
+			},
+			Evidence: evidence,
+		})
+	}
+	return res, nil
+}
+
+// highlight turns a search snippet into HTML with the matched words in
+// <mark>. Snippets without a match are dropped.
+func highlight(snippet string) string {
+	if !strings.Contains(snippet, repository.SearchHighlightStart) {
+		return ""
+	}
+	return searchHighlighter.Replace(html.EscapeString(snippet))
+}
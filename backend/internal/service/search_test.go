@@ -0,0 +1,26 @@
+package service
+
+import (
+	"backend/internal/repository"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	start, stop := repository.SearchHighlightStart, repository.SearchHighlightStop
+
+	tests := []struct {
+		name    string
+		snippet string
+		want    string
+	}{
+		{"marked words", "a " + start + "red" + stop + " bike", "a <mark>red</mark> bike"},
+		{"escapes the text", start + "knife" + stop + " <b>& fork", "<mark>knife</mark> &lt;b&gt;&amp; fork"},
+		{"no match", "a red bike", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.snippet); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
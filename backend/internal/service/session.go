@@ -0,0 +1,195 @@
+package service
+
+import (
+	"backend/internal/dto/session"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"time"
+)
+
+type SessionConfig struct {
+	// ActivityInterval is the minimum time between LastActivity writes for
+	// one session, so busy clients do not write on every request.
+	ActivityInterval time.Duration
+}
+
+type SessionService interface {
+	Check(ctx context.Context, sessionID, userID uint) error
+	ListSessions(ctx context.Context) ([]session.SessionResponse, error)
+	RevokeSession(ctx context.Context, sessionID uint) error
+	RevokeOtherSessions(ctx context.Context) error
+	ListUserSessions(ctx context.Context, userID uint) ([]session.SessionResponse, error)
+	EndAllSessions(ctx context.Context, userID uint) error
+}
+
+type sessionService struct {
+	sessionRepo      repository.UserSessionRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	userRepo         repository.UserRepository
+	txManager        repository.TransactionManager
+	config           SessionConfig
+}
+
+func NewSessionService(
+	sessionRepo repository.UserSessionRepository,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TransactionManager,
+	config SessionConfig,
+) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		userRepo:         userRepo,
+		txManager:        txManager,
+		config:           config,
+	}
+}
+
+// Check rejects access tokens whose session has ended and records activity
+// on the session, at most once per ActivityInterval.
+func (s *sessionService) Check(ctx context.Context, sessionID, userID uint) error {
+	current, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if current == nil || !current.IsActive || current.UserID != userID {
+		return ErrInvalidAccessToken
+	}
+
+	now := time.Now()
+	if now.Sub(current.LastActivity) >= s.config.ActivityInterval {
+		return s.sessionRepo.Touch(ctx, current.ID, now)
+	}
+	return nil
+}
+
+func (s *sessionService) ListSessions(ctx context.Context) ([]session.SessionResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, p.UserID())
+	if err != nil {
+		return nil, err
+	}
+	return toSessionResponses(sessions, p.SessionID), nil
+}
+
+// RevokeSession ends one of the caller's own sessions, signing that device
+// out as soon as its current access token is next used.
+func (s *sessionService) RevokeSession(ctx context.Context, sessionID uint) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.sessionRepo.FindByID(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if target == nil || !target.IsActive || target.UserID != p.UserID() {
+			return ErrSessionNotFound
+		}
+		return s.endSession(ctx, target)
+	})
+}
+
+func (s *sessionService) RevokeOtherSessions(ctx context.Context) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		sessions, err := s.sessionRepo.FindActiveByUserID(ctx, p.UserID())
+		if err != nil {
+			return err
+		}
+		for i := range sessions {
+			if sessions[i].ID == p.SessionID {
+				continue
+			}
+			if err := s.endSession(ctx, &sessions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *sessionService) ListUserSessions(ctx context.Context, userID uint) ([]session.SessionResponse, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	sessions, err := s.sessionRepo.FindActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentID uint
+	if p, ok := principal.FromContext(ctx); ok {
+		currentID = p.SessionID
+	}
+	return toSessionResponses(sessions, currentID), nil
+}
+
+// EndAllSessions signs the user out everywhere: every session ends and every
+// refresh token is revoked. Used for admin force-logout and when a user is
+// deactivated.
+func (s *sessionService) EndAllSessions(ctx context.Context, userID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+
+		if err := s.sessionRepo.DeactivateByUserID(ctx, userID); err != nil {
+			return err
+		}
+		return s.refreshTokenRepo.RevokeByUserID(ctx, userID)
+	})
+}
+
+func (s *sessionService) endSession(ctx context.Context, target *models.UserSession) error {
+	if err := s.sessionRepo.Deactivate(ctx, target.ID); err != nil {
+		return err
+	}
+	if target.RefreshTokenID == nil {
+		return nil
+	}
+
+	token, err := s.refreshTokenRepo.FindByID(ctx, *target.RefreshTokenID)
+	if err != nil || token == nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+func toSessionResponses(sessions []models.UserSession, currentID uint) []session.SessionResponse {
+	res := make([]session.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, session.SessionResponse{
+			ID:           s.ID,
+			DeviceInfo:   s.DeviceInfo,
+			IPAddress:    s.IPAddress,
+			UserAgent:    s.UserAgent,
+			LastActivity: s.LastActivity,
+			CreatedAt:    s.CreatedAt,
+			Current:      s.ID == currentID,
+		})
+	}
+	return res
+}
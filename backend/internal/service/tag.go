@@ -0,0 +1,380 @@
+package service
+
+import (
+	"backend/internal/dto/cases"
+	"backend/internal/dto/tag"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"backend/internal/repository"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var tagColorPattern = regexp.MustCompile(`^#([0-9A-F]{3}|[0-9A-F]{6})$`)
+
+// TagService manages tags and their attachment to cases.
+type TagService interface {
+	ListTags(ctx context.Context) ([]tag.TagResponse, error)
+	CreateTag(ctx context.Context, payload tag.CreateTagRequest) (*tag.TagResponse, error)
+	UpdateTag(ctx context.Context, tagID uint, payload tag.UpdateTagRequest) (*tag.TagResponse, error)
+	DeleteTag(ctx context.Context, tagID uint) error
+	MergeTag(ctx context.Context, tagID uint, payload tag.MergeTagRequest) (*tag.TagResponse, error)
+	ListCaseTags(ctx context.Context, caseID uint) ([]tag.CaseTagResponse, error)
+	AttachTag(ctx context.Context, caseID uint, payload tag.AttachTagRequest) ([]tag.CaseTagResponse, error)
+	DetachTag(ctx context.Context, caseID, tagID uint) error
+}
+
+type tagService struct {
+	tagRepo   repository.TagRepository
+	caseRepo  repository.CaseRepository
+	auditRepo repository.AuditLogRepository
+	txManager repository.TransactionManager
+}
+
+func NewTagService(
+	tagRepo repository.TagRepository,
+	caseRepo repository.CaseRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+) TagService {
+	return &tagService{
+		tagRepo:   tagRepo,
+		caseRepo:  caseRepo,
+		auditRepo: auditRepo,
+		txManager: txManager,
+	}
+}
+
+// ListTags returns every tag with the number of cases the caller can see
+// that carry it.
+func (s *tagService) ListTags(ctx context.Context) ([]tag.TagResponse, error) {
+	tags, err := s.tagRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.caseRepo.CountByTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]tag.TagResponse, 0, len(tags))
+	for i := range tags {
+		res = append(res, toTagResponse(&tags[i], counts[tags[i].ID]))
+	}
+	return res, nil
+}
+
+func (s *tagService) CreateTag(ctx context.Context, payload tag.CreateTagRequest) (*tag.TagResponse, error) {
+	color, err := normalizeTagColor(payload.Color)
+	if err != nil {
+		return nil, err
+	}
+	created := &models.Tag{Name: strings.TrimSpace(payload.Name), Color: color}
+
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkName(ctx, created); err != nil {
+			return err
+		}
+		if err := s.tagRepo.Create(ctx, created); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return ErrTagNameTaken
+			}
+			return err
+		}
+		return s.recordChange(ctx, "tag", created.ID, models.AuditTagCreated, nil, toTagSummary(created))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := toTagResponse(created, 0)
+	return &res, nil
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, tagID uint, payload tag.UpdateTagRequest) (*tag.TagResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.tagRepo.FindByID(ctx, tagID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrTagNotFound
+		}
+		previous := toTagSummary(target)
+
+		if payload.Name != nil {
+			target.Name = strings.TrimSpace(*payload.Name)
+			if err := s.checkName(ctx, target); err != nil {
+				return err
+			}
+		}
+		if payload.Color != nil {
+			if target.Color, err = normalizeTagColor(*payload.Color); err != nil {
+				return err
+			}
+		}
+
+		if err := s.tagRepo.Update(ctx, target); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return ErrTagNameTaken
+			}
+			return err
+		}
+		return s.recordChange(ctx, "tag", target.ID, models.AuditTagUpdated, previous, toTagSummary(target))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, tagID)
+}
+
+// DeleteTag soft-deletes the tag and detaches it from every case.
+func (s *tagService) DeleteTag(ctx context.Context, tagID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.tagRepo.FindByID(ctx, tagID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrTagNotFound
+		}
+
+		if err := s.tagRepo.DeleteCaseTagsByTag(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.tagRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "tag", target.ID, models.AuditTagDeleted, toTagSummary(target), nil)
+	})
+}
+
+// MergeTag moves the tag's cases onto the target tag and deletes the tag.
+func (s *tagService) MergeTag(ctx context.Context, tagID uint, payload tag.MergeTagRequest) (*tag.TagResponse, error) {
+	if tagID == payload.TargetTagID {
+		return nil, ErrMergeSameTag
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		source, err := s.tagRepo.FindByID(ctx, tagID)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			return ErrTagNotFound
+		}
+		target, err := s.tagRepo.FindByID(ctx, payload.TargetTagID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrTagNotFound
+		}
+
+		moved, err := s.tagRepo.MergeCaseTags(ctx, source.ID, target.ID)
+		if err != nil {
+			return err
+		}
+		if err := s.tagRepo.Delete(ctx, source.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "tag", target.ID, models.AuditTagMerged, nil, tagMergeAuditDetails{
+			Source:     toTagSummary(source),
+			Target:     toTagSummary(target),
+			CasesMoved: moved,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.load(ctx, payload.TargetTagID)
+}
+
+func (s *tagService) ListCaseTags(ctx context.Context, caseID uint) ([]tag.CaseTagResponse, error) {
+	c, err := s.caseRepo.FindByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if c == nil {
+		return nil, ErrCaseNotFound
+	}
+	return s.caseTags(ctx, caseID)
+}
+
+// AttachTag tags the case and returns the case's tags.
+func (s *tagService) AttachTag(ctx context.Context, caseID uint, payload tag.AttachTagRequest) ([]tag.CaseTagResponse, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		c, err := s.caseRepo.FindByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return ErrCaseNotFound
+		}
+		t, err := s.tagRepo.FindByID(ctx, payload.TagID)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return ErrTagNotFound
+		}
+
+		existing, err := s.tagRepo.FindCaseTag(ctx, caseID, t.ID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrTagAlreadyAttached
+		}
+
+		caseTag := &models.CaseTag{CaseID: caseID, TagID: t.ID, CreatedByID: p.UserIDRef()}
+		if err := s.tagRepo.CreateCaseTag(ctx, caseTag); err != nil {
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				return ErrTagAlreadyAttached
+			}
+			return err
+		}
+		return s.recordChange(ctx, "case", caseID, models.AuditCaseTagAdded, nil, toTagSummary(t))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.caseTags(ctx, caseID)
+}
+
+// DetachTag removes the tag from the case. The attachment stays in the
+// history.
+func (s *tagService) DetachTag(ctx context.Context, caseID, tagID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		c, err := s.caseRepo.FindByID(ctx, caseID)
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return ErrCaseNotFound
+		}
+
+		caseTag, err := s.tagRepo.FindCaseTag(ctx, caseID, tagID)
+		if err != nil {
+			return err
+		}
+		if caseTag == nil || caseTag.Tag == nil {
+			return ErrTagNotAttached
+		}
+
+		if err := s.tagRepo.DeleteCaseTag(ctx, caseTag.ID); err != nil {
+			return err
+		}
+		return s.recordChange(ctx, "case", caseID, models.AuditCaseTagRemoved, toTagSummary(caseTag.Tag), nil)
+	})
+}
+
+func (s *tagService) checkName(ctx context.Context, t *models.Tag) error {
+	taken, err := s.tagRepo.ExistsByName(ctx, t.Name, t.ID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrTagNameTaken
+	}
+	return nil
+}
+
+func (s *tagService) recordChange(ctx context.Context, entityType string, entityID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	details := auditChange{Previous: previous, New: next}
+	entry, err := newAuditLog(ctx, &actorID, action, entityType, &entityID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *tagService) load(ctx context.Context, tagID uint) (*tag.TagResponse, error) {
+	t, err := s.tagRepo.FindByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTagNotFound
+	}
+	counts, err := s.caseRepo.CountByTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := toTagResponse(t, counts[t.ID])
+	return &res, nil
+}
+
+func (s *tagService) caseTags(ctx context.Context, caseID uint) ([]tag.CaseTagResponse, error) {
+	caseTags, err := s.tagRepo.FindCaseTagsByCase(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]tag.CaseTagResponse, 0, len(caseTags))
+	for _, ct := range caseTags {
+		if ct.Tag == nil {
+			continue
+		}
+		res = append(res, tag.CaseTagResponse{
+			ID:         ct.Tag.ID,
+			Name:       ct.Tag.Name,
+			Color:      ct.Tag.Color,
+			AttachedBy: ct.CreatedByID,
+			AttachedAt: ct.CreatedAt,
+		})
+	}
+	return res, nil
+}
+
+// tagMergeAuditDetails is the Details payload of models.AuditTagMerged
+// entries.
+type tagMergeAuditDetails struct {
+	Source     cases.TagSummary `json:"source"`
+	Target     cases.TagSummary `json:"target"`
+	CasesMoved int64            `json:"cases_moved"`
+}
+
+// normalizeTagColor accepts #RGB and #RRGGBB in either case and returns
+// the upper-case #RRGGBB form.
+func normalizeTagColor(color string) (string, error) {
+	color = strings.ToUpper(strings.TrimSpace(color))
+	if !tagColorPattern.MatchString(color) {
+		return "", ErrInvalidTagColor
+	}
+	if len(color) == 4 {
+		color = string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	return color, nil
+}
+
+func toTagResponse(t *models.Tag, caseCount int64) tag.TagResponse {
+	return tag.TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Color:     t.Color,
+		CaseCount: caseCount,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func toTagSummary(t *models.Tag) cases.TagSummary {
+	return cases.TagSummary{ID: t.ID, Name: t.Name, Color: t.Color}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeTagColor(t *testing.T) {
+	tests := []struct {
+		color   string
+		want    string
+		wantErr error
+	}{
+		{"#FF0000", "#FF0000", nil},
+		{"#ff00aa", "#FF00AA", nil},
+		{"#f0a", "#FF00AA", nil},
+		{"  #abc ", "#AABBCC", nil},
+		{"FF0000", "", ErrInvalidTagColor},
+		{"#FF00", "", ErrInvalidTagColor},
+		{"#GG0000", "", ErrInvalidTagColor},
+		{"#FF00000", "", ErrInvalidTagColor},
+		{"", "", ErrInvalidTagColor},
+	}
+	for _, tt := range tests {
+		got, err := normalizeTagColor(tt.color)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("normalizeTagColor(%q) error = %v, want %v", tt.color, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeTagColor(%q) = %q, want %q", tt.color, got, tt.want)
+		}
+	}
+}
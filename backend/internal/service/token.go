@@ -0,0 +1,261 @@
+package service
+
+import (
+	"backend/internal/clientinfo"
+	"backend/internal/dto/auth"
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	tokenTypeBearer    = "Bearer"
+	tokenPurposeAccess = "access"
+)
+
+type TokenConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// TokenService issues signed access tokens and rotating refresh tokens.
+//
+// Every refresh token belongs to a family that starts at sign-in. Using a
+// refresh token revokes it and issues its successor in the same family;
+// presenting an already rotated token revokes the whole family.
+//
+// Each family backs one UserSession, which follows the family's latest
+// refresh token and is named in the sid claim of every access token issued
+// for it.
+type TokenService interface {
+	IssueTokens(ctx context.Context, user *models.User) (*auth.TokenPair, error)
+	RotateRefreshToken(ctx context.Context, rawToken string) (*auth.TokenPair, error)
+	RevokeRefreshToken(ctx context.Context, rawToken string) error
+	ParseAccessToken(token string) (*security.Claims, error)
+}
+
+type tokenService struct {
+	signer           *security.JWTSigner
+	refreshTokenRepo repository.RefreshTokenRepository
+	sessionRepo      repository.UserSessionRepository
+	userRepo         repository.UserRepository
+	txManager        repository.TransactionManager
+	config           TokenConfig
+}
+
+func NewTokenService(
+	signer *security.JWTSigner,
+	refreshTokenRepo repository.RefreshTokenRepository,
+	sessionRepo repository.UserSessionRepository,
+	userRepo repository.UserRepository,
+	txManager repository.TransactionManager,
+	config TokenConfig,
+) TokenService {
+	return &tokenService{
+		signer:           signer,
+		refreshTokenRepo: refreshTokenRepo,
+		sessionRepo:      sessionRepo,
+		userRepo:         userRepo,
+		txManager:        txManager,
+		config:           config,
+	}
+}
+
+// IssueTokens starts a new session for the user.
+func (s *tokenService) IssueTokens(ctx context.Context, user *models.User) (*auth.TokenPair, error) {
+	var pair *auth.TokenPair
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		rawToken, token, err := s.createRefreshToken(ctx, user.ID, uuid.NewString())
+		if err != nil {
+			return err
+		}
+
+		session, err := s.createSession(ctx, user.ID, token.ID)
+		if err != nil {
+			return err
+		}
+
+		pair, err = s.newTokenPair(user, rawToken, session.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+func (s *tokenService) RotateRefreshToken(ctx context.Context, rawToken string) (*auth.TokenPair, error) {
+	var (
+		pair   *auth.TokenPair
+		reused bool
+	)
+
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.refreshTokenRepo.FindByHashForUpdate(ctx, security.HashToken(rawToken))
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrInvalidRefreshToken
+		}
+
+		if current.IsRevoked {
+			if current.ReplacedByID == nil {
+				return ErrInvalidRefreshToken
+			}
+			// The token was already exchanged, so whoever presents it now may
+			// hold a stolen copy. Kill the family and let the commit go through.
+			reused = true
+			return s.revokeFamily(ctx, current.FamilyID)
+		}
+
+		if time.Now().After(current.ExpiryDate) {
+			return ErrInvalidRefreshToken
+		}
+
+		user, err := s.userRepo.FindByID(ctx, current.UserID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrInvalidRefreshToken
+		}
+		if !user.IsActive {
+			return ErrUserInactive
+		}
+
+		session, err := s.sessionRepo.FindByRefreshTokenID(ctx, current.ID)
+		if err != nil {
+			return err
+		}
+		if session != nil && !session.IsActive {
+			return ErrInvalidRefreshToken
+		}
+
+		nextRaw, next, err := s.createRefreshToken(ctx, user.ID, current.FamilyID)
+		if err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.MarkRotated(ctx, current.ID, next.ID); err != nil {
+			return err
+		}
+
+		// Families issued before sessions existed get one on first rotation.
+		if session == nil {
+			session, err = s.createSession(ctx, user.ID, next.ID)
+			if err != nil {
+				return err
+			}
+		} else if err := s.sessionRepo.UpdateRefreshToken(ctx, session.ID, next.ID, time.Now()); err != nil {
+			return err
+		}
+
+		pair, err = s.newTokenPair(user, nextRaw, session.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+
+	return pair, nil
+}
+
+func (s *tokenService) RevokeRefreshToken(ctx context.Context, rawToken string) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		current, err := s.refreshTokenRepo.FindByHashForUpdate(ctx, security.HashToken(rawToken))
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return ErrInvalidRefreshToken
+		}
+		return s.revokeFamily(ctx, current.FamilyID)
+	})
+}
+
+// revokeFamily revokes every refresh token of the family and ends its session.
+func (s *tokenService) revokeFamily(ctx context.Context, familyID string) error {
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return s.sessionRepo.DeactivateByRefreshTokenFamily(ctx, familyID)
+}
+
+func (s *tokenService) createSession(ctx context.Context, userID, refreshTokenID uint) (*models.UserSession, error) {
+	client := clientinfo.FromContext(ctx)
+	session := &models.UserSession{
+		UserID:         userID,
+		RefreshTokenID: &refreshTokenID,
+		DeviceInfo:     client.DeviceInfo(),
+		IPAddress:      client.IPAddress,
+		UserAgent:      client.UserAgent,
+		LastActivity:   time.Now(),
+		IsActive:       true,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (s *tokenService) ParseAccessToken(token string) (*security.Claims, error) {
+	claims, err := s.signer.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != tokenPurposeAccess {
+		return nil, security.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (s *tokenService) createRefreshToken(ctx context.Context, userID uint, familyID string) (string, *models.RefreshToken, error) {
+	rawToken, err := security.RandomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := &models.RefreshToken{
+		UserID:     userID,
+		Token:      security.HashToken(rawToken),
+		FamilyID:   familyID,
+		ExpiryDate: time.Now().Add(s.config.RefreshTokenTTL),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, token); err != nil {
+		return "", nil, err
+	}
+
+	return rawToken, token, nil
+}
+
+func (s *tokenService) newTokenPair(user *models.User, refreshToken string, sessionID uint) (*auth.TokenPair, error) {
+	now := time.Now()
+	accessToken, err := s.signer.Sign(security.Claims{
+		Subject:   strconv.FormatUint(uint64(user.ID), 10),
+		ID:        uuid.NewString(),
+		Purpose:   tokenPurposeAccess,
+		SessionID: strconv.FormatUint(uint64(sessionID), 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.AccessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    tokenTypeBearer,
+		ExpiresIn:    int64(s.config.AccessTokenTTL.Seconds()),
+	}, nil
+}
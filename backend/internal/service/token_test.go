@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend/internal/model"
+	"backend/internal/repository"
+	"backend/internal/security"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTxManager runs the function directly; the fakes below keep their
+// state in memory and need no transaction.
+type fakeTxManager struct{}
+
+func (fakeTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeRefreshTokenRepo struct {
+	repository.RefreshTokenRepository
+	tokens []*models.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) Create(_ context.Context, token *models.RefreshToken) error {
+	token.ID = uint(len(r.tokens) + 1)
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) FindByHashForUpdate(_ context.Context, hash string) (*models.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.Token == hash {
+			copied := *token
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeRefreshTokenRepo) MarkRotated(_ context.Context, id, replacedByID uint) error {
+	token := r.tokens[id-1]
+	token.IsRevoked = true
+	token.ReplacedByID = &replacedByID
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(_ context.Context, familyID string) error {
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID {
+			token.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+type fakeSessionRepo struct {
+	repository.UserSessionRepository
+	sessions []*models.UserSession
+}
+
+func (r *fakeSessionRepo) Create(_ context.Context, session *models.UserSession) error {
+	session.ID = uint(len(r.sessions) + 1)
+	r.sessions = append(r.sessions, session)
+	return nil
+}
+
+func (r *fakeSessionRepo) FindByRefreshTokenID(_ context.Context, refreshTokenID uint) (*models.UserSession, error) {
+	for _, session := range r.sessions {
+		if session.RefreshTokenID != nil && *session.RefreshTokenID == refreshTokenID {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSessionRepo) UpdateRefreshToken(_ context.Context, id, refreshTokenID uint, _ time.Time) error {
+	r.sessions[id-1].RefreshTokenID = &refreshTokenID
+	return nil
+}
+
+func (r *fakeSessionRepo) DeactivateByRefreshTokenFamily(context.Context, string) error {
+	for _, session := range r.sessions {
+		session.IsActive = false
+	}
+	return nil
+}
+
+type fakeUserRepo struct {
+	repository.UserRepository
+	user *models.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id uint) (*models.User, error) {
+	if r.user.ID != id {
+		return nil, nil
+	}
+	return r.user, nil
+}
+
+func TestRotateRefreshTokenDetectsReuse(t *testing.T) {
+	user := &models.User{IsActive: true}
+	user.ID = 7
+	tokens := &fakeRefreshTokenRepo{}
+	sessions := &fakeSessionRepo{}
+	s := NewTokenService(
+		security.NewJWTSigner("secret", "test"),
+		tokens,
+		sessions,
+		&fakeUserRepo{user: user},
+		fakeTxManager{},
+		TokenConfig{AccessTokenTTL: time.Minute, RefreshTokenTTL: time.Hour},
+	)
+	ctx := context.Background()
+
+	first, err := s.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	second, err := s.RotateRefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("first rotation: %v", err)
+	}
+
+	steps := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"rotated token is reused", first.RefreshToken, ErrRefreshTokenReused},
+		{"successor is revoked with the family", second.RefreshToken, ErrInvalidRefreshToken},
+		{"reused token stays rejected", first.RefreshToken, ErrRefreshTokenReused},
+		{"unknown token", "not-a-token", ErrInvalidRefreshToken},
+	}
+	for _, step := range steps {
+		if _, err := s.RotateRefreshToken(ctx, step.token); !errors.Is(err, step.want) {
+			t.Errorf("%s: got %v, want %v", step.name, err, step.want)
+		}
+	}
+
+	for _, token := range tokens.tokens {
+		if !token.IsRevoked {
+			t.Errorf("token %d of the family is not revoked", token.ID)
+		}
+	}
+	if sessions.sessions[0].IsActive {
+		t.Error("session of the reused family is still active")
+	}
+}
@@ -1,30 +1,566 @@
 package service
 
 import (
+	"backend/internal/dto/pagination"
+	"backend/internal/dto/user"
+	"backend/internal/integration/smtp"
 	"backend/internal/model"
+	"backend/internal/principal"
 	"backend/internal/repository"
-	"backend/internal/integration/smtp"
-	"backend/internal/dto/auth"
+	"backend/internal/security"
 	"context"
+	"errors"
+	"log"
+	"time"
 )
 
 type UserService interface {
-	Register(email, password, firstName, lastName string) error
-	GetAllUsers() ([]models.User, error)
+	Register(ctx context.Context, email, password, firstName, lastName string) error
+	ListUsers(ctx context.Context, query user.ListUsersQuery) (*user.UserListResponse, error)
+	GetUser(ctx context.Context, userID uint) (*user.UserDetailResponse, error)
+	UpdateUser(ctx context.Context, userID uint, payload user.UpdateUserRequest) (*user.UserDetailResponse, error)
+	DeactivateUser(ctx context.Context, userID uint) error
+	ReactivateUser(ctx context.Context, userID uint) error
+	DeleteUser(ctx context.Context, userID uint) error
+	RestoreUser(ctx context.Context, userID uint) (*user.UserDetailResponse, error)
+	TransferDepartment(ctx context.Context, userID uint, payload user.TransferDepartmentRequest) (*user.UserDetailResponse, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
-	mailer   smtp.Mailer
+	userRepo       repository.UserRepository
+	userRoleRepo   repository.UserRoleRepository
+	roleRepo       repository.RoleRepository
+	departmentRepo repository.DepartmentRepository
+	auditRepo      repository.AuditLogRepository
+	txManager      repository.TransactionManager
+	sessionService SessionService
+	mailer         smtp.Mailer
+	passwordHasher security.PasswordHasher
+}
+
+func NewUserService(
+	userRepo repository.UserRepository,
+	userRoleRepo repository.UserRoleRepository,
+	roleRepo repository.RoleRepository,
+	departmentRepo repository.DepartmentRepository,
+	auditRepo repository.AuditLogRepository,
+	txManager repository.TransactionManager,
+	sessionService SessionService,
+	mailer smtp.Mailer,
+	passwordHasher security.PasswordHasher,
+) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		userRoleRepo:   userRoleRepo,
+		roleRepo:       roleRepo,
+		departmentRepo: departmentRepo,
+		auditRepo:      auditRepo,
+		txManager:      txManager,
+		sessionService: sessionService,
+		mailer:         mailer,
+		passwordHasher: passwordHasher,
+	}
+}
+
+func (s *userService) Register(ctx context.Context, email, password, firstName, lastName string) error {
+	existing, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrEmailTaken
+	}
+
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	user := &models.User{
+		Email:        email,
+		PasswordHash: passwordHash,
+		FirstName:    firstName,
+		LastName:     lastName,
+		IsActive:     true,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.mailer.SendWelcomeEmail(user.Email, user.FirstName); err != nil {
+		log.Printf("failed to send welcome email to %s: %v", user.Email, err)
+	}
+
+	return nil
+}
+
+// userSortColumns maps the sort fields of the user directory to columns.
+var userSortColumns = map[string]string{
+	"first_name":   "first_name",
+	"last_name":    "last_name",
+	"email":        "email",
+	"badge_number": "badge_number",
+	"created_at":   "created_at",
+}
+
+// ListUsers returns one page of the user directory.
+func (s *userService) ListUsers(ctx context.Context, query user.ListUsersQuery) (*user.UserListResponse, error) {
+	field, desc := parseSort(query.Sort, "last_name")
+	column, ok := userSortColumns[field]
+	if !ok {
+		return nil, ErrInvalidSort
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	listQuery := repository.UserListQuery{
+		DepartmentID: query.DepartmentID,
+		RoleID:       query.RoleID,
+		IsActive:     query.IsActive,
+		Search:       query.Search,
+		SortColumn:   column,
+		SortDesc:     desc,
+		Limit:        limit + 1, // one extra row tells whether more follow
+	}
+
+	sort := query.Sort
+	if sort == "" {
+		sort = field
+	}
+
+	if query.Cursor != "" {
+		after, err := decodeUserCursor(query.Cursor, sort, column)
+		if err != nil {
+			return nil, err
+		}
+		listQuery.After = after
+	}
+	if query.Page > 0 {
+		listQuery.Offset = (query.Page - 1) * limit
+	}
+
+	users, err := s.userRepo.List(ctx, listQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+
+	res := &user.UserListResponse{
+		Items:      make([]user.UserListItem, 0, len(users)),
+		Pagination: pagination.Meta{Limit: limit, HasMore: hasMore},
+	}
+	for i := range users {
+		res.Items = append(res.Items, toUserListItem(&users[i]))
+	}
+
+	if query.Page > 0 {
+		total, err := s.userRepo.Count(ctx, listQuery)
+		if err != nil {
+			return nil, err
+		}
+		res.Pagination = offsetMeta(limit, query.Page, total)
+	} else if hasMore {
+		last := &users[len(users)-1]
+		cursor, err := encodeCursor(sort, userSortValue(last, column), last.ID)
+		if err != nil {
+			return nil, err
+		}
+		res.Pagination.NextCursor = cursor
+	}
+
+	return res, nil
+}
+
+func userSortValue(u *models.User, column string) interface{} {
+	switch column {
+	case "first_name":
+		return u.FirstName
+	case "email":
+		return u.Email
+	case "badge_number":
+		return u.BadgeNumber
+	case "created_at":
+		return u.CreatedAt
+	default:
+		return u.LastName
+	}
+}
+
+func decodeUserCursor(encoded, sort, column string) (*repository.KeysetCursor, error) {
+	if column == "created_at" {
+		var value time.Time
+		id, err := decodeCursor(encoded, sort, &value)
+		if err != nil {
+			return nil, err
+		}
+		return &repository.KeysetCursor{Value: value, ID: id}, nil
+	}
+
+	var value string
+	id, err := decodeCursor(encoded, sort, &value)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.KeysetCursor{Value: value, ID: id}, nil
 }
 
-func (s *userService) SignIn (ctx context.Context, payload auth.SignInRequest) (auth.SignInResponse, error) {
-	_, err := s.userRepo.FindByEmail(ctx, payload.Email)
+func (s *userService) GetUser(ctx context.Context, userID uint) (*user.UserDetailResponse, error) {
+	return s.loadDetail(ctx, userID)
+}
+
+// UpdateUser changes profile fields. Email and badge number must stay
+// unique across all users, including soft-deleted ones.
+func (s *userService) UpdateUser(ctx context.Context, userID uint, payload user.UpdateUserRequest) (*user.UserDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.loadManagedUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		previous := toUserResponse(target)
+
+		if payload.Email != nil && *payload.Email != target.Email {
+			taken, err := s.userRepo.ExistsByEmail(ctx, *payload.Email, target.ID)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return ErrEmailTaken
+			}
+			target.Email = *payload.Email
+		}
+		if payload.BadgeNumber != nil && *payload.BadgeNumber != target.BadgeNumber {
+			if *payload.BadgeNumber != "" {
+				taken, err := s.userRepo.ExistsByBadgeNumber(ctx, *payload.BadgeNumber, target.ID)
+				if err != nil {
+					return err
+				}
+				if taken {
+					return ErrBadgeNumberTaken
+				}
+			}
+			target.BadgeNumber = *payload.BadgeNumber
+		}
+		if payload.FirstName != nil {
+			target.FirstName = *payload.FirstName
+		}
+		if payload.LastName != nil {
+			target.LastName = *payload.LastName
+		}
+		if payload.PhoneNumber != nil {
+			target.PhoneNumber = *payload.PhoneNumber
+		}
+		if payload.ProfileImageURL != nil {
+			target.ProfileImageURL = *payload.ProfileImageURL
+		}
+
+		if err := s.userRepo.UpdateProfile(ctx, target); err != nil {
+			// Lost a race with a concurrent write of the same value.
+			if errors.Is(err, repository.ErrDuplicateKey) {
+				if payload.BadgeNumber != nil && *payload.BadgeNumber != previous.BadgeNumber {
+					return ErrBadgeNumberTaken
+				}
+				return ErrEmailTaken
+			}
+			return err
+		}
+
+		return s.recordUserChange(ctx, target.ID, models.AuditUserUpdated, previous, toUserResponse(target))
+	})
 	if err != nil {
-		return auth.SignInResponse{}, err
+		return nil, err
 	}
 
-	return auth.SignInResponse{
-		Token: "token",
-	}, nil
+	return s.loadDetail(ctx, userID)
+}
+
+// DeactivateUser blocks sign-in for the user and ends all their sessions
+// right away.
+func (s *userService) DeactivateUser(ctx context.Context, userID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.loadManagedUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNotSelf(ctx, target.ID, ErrCannotDeactivateSelf); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.UpdateActive(ctx, target.ID, false); err != nil {
+			return err
+		}
+		if err := s.sessionService.EndAllSessions(ctx, target.ID); err != nil {
+			return err
+		}
+
+		return s.recordUserChange(ctx, target.ID, models.AuditUserDeactivated, nil, nil)
+	})
+}
+
+func (s *userService) ReactivateUser(ctx context.Context, userID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.loadManagedUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNotSelf(ctx, target.ID, ErrCannotReactivateSelf); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.UpdateActive(ctx, target.ID, true); err != nil {
+			return err
+		}
+
+		return s.recordUserChange(ctx, target.ID, models.AuditUserReactivated, nil, nil)
+	})
+}
+
+// DeleteUser soft-deletes the user and ends all their sessions. The email
+// and badge number stay reserved so the user can be restored.
+func (s *userService) DeleteUser(ctx context.Context, userID uint) error {
+	return s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.loadManagedUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNotSelf(ctx, target.ID, ErrCannotDeactivateSelf); err != nil {
+			return err
+		}
+
+		if err := s.sessionService.EndAllSessions(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.departmentRepo.ClearHead(ctx, target.ID); err != nil {
+			return err
+		}
+		if err := s.userRepo.Delete(ctx, target.ID); err != nil {
+			return err
+		}
+
+		return s.recordUserChange(ctx, target.ID, models.AuditUserDeleted, toUserResponse(target), nil)
+	})
+}
+
+// RestoreUser undoes a soft delete. Restoring a user that is not deleted is
+// a no-op.
+func (s *userService) RestoreUser(ctx context.Context, userID uint) (*user.UserDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.userRepo.FindByIDUnscoped(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if target == nil {
+			return ErrUserNotFound
+		}
+		if !target.DeletedAt.Valid {
+			return nil
+		}
+		if err := s.checkSeniority(ctx, target.ID); err != nil {
+			return err
+		}
+
+		if err := s.userRepo.Restore(ctx, target.ID); err != nil {
+			return err
+		}
+
+		return s.recordUserChange(ctx, target.ID, models.AuditUserRestored, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, userID)
+}
+
+// TransferDepartment moves the user to another department, or out of any
+// with a nil DepartmentID. Users cannot move themselves, since their
+// department decides which cases they see.
+func (s *userService) TransferDepartment(ctx context.Context, userID uint, payload user.TransferDepartmentRequest) (*user.UserDetailResponse, error) {
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		target, err := s.loadManagedUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNotSelf(ctx, target.ID, ErrCannotTransferSelf); err != nil {
+			return err
+		}
+
+		if payload.DepartmentID != nil {
+			department, err := s.departmentRepo.FindByID(ctx, *payload.DepartmentID)
+			if err != nil {
+				return err
+			}
+			if department == nil {
+				return ErrDepartmentNotFound
+			}
+		}
+
+		if err := s.userRepo.UpdateDepartment(ctx, target.ID, payload.DepartmentID); err != nil {
+			return err
+		}
+		// A head who leaves stops leading their old department.
+		if err := s.departmentRepo.ClearHead(ctx, target.ID); err != nil {
+			return err
+		}
+
+		previous := departmentChange{DepartmentID: target.DepartmentID}
+		next := departmentChange{DepartmentID: payload.DepartmentID}
+		return s.recordUserChange(ctx, target.ID, models.AuditUserTransferred, previous, next)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.loadDetail(ctx, userID)
+}
+
+type departmentChange struct {
+	DepartmentID *uint `json:"department_id"`
+}
+
+// loadManagedUser returns the user if the caller outranks them.
+func (s *userService) loadManagedUser(ctx context.Context, userID uint) (*models.User, error) {
+	target, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.checkSeniority(ctx, target.ID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// checkSeniority stops actors from managing users at or above their own
+// role level, the same rule that applies to role assignment. Users may
+// manage themselves, except where ensureNotSelf says otherwise.
+func (s *userService) checkSeniority(ctx context.Context, userID uint) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if p.UserID() == userID {
+		return nil
+	}
+
+	roles, err := s.roleRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	hierarchy := newRoleHierarchy(roles)
+
+	actorRoleIDs, err := s.userRoleRepo.FindRoleIDsByUserID(ctx, p.UserID())
+	if err != nil {
+		return err
+	}
+	targetRoleIDs, err := s.userRoleRepo.FindRoleIDsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if hierarchy.highestLevel(targetRoleIDs) >= hierarchy.highestLevel(actorRoleIDs) {
+		return ErrUserNotManageable
+	}
+	return nil
+}
+
+// ensureNotSelf returns selfErr when the caller is the user, for changes
+// nobody may make to their own account.
+func (s *userService) ensureNotSelf(ctx context.Context, userID uint, selfErr error) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if p.UserID() == userID {
+		return selfErr
+	}
+	return nil
+}
+
+func (s *userService) recordUserChange(ctx context.Context, userID uint, action string, previous, next interface{}) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	actorID := p.UserID()
+
+	var details interface{}
+	if previous != nil || next != nil {
+		details = auditChange{Previous: previous, New: next}
+	}
+
+	entry, err := newAuditLog(ctx, &actorID, action, "user", &userID, details)
+	if err != nil {
+		return err
+	}
+	return s.auditRepo.Create(ctx, entry)
+}
+
+func (s *userService) loadDetail(ctx context.Context, userID uint) (*user.UserDetailResponse, error) {
+	u, err := s.userRepo.FindByIDWithDetails(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+
+	res := &user.UserDetailResponse{
+		UserResponse: toUserResponse(u),
+		Roles:        make([]user.RoleSummary, 0, len(u.UserRoles)),
+	}
+	if u.Department != nil {
+		res.Department = &user.DepartmentSummary{ID: u.Department.ID, Name: u.Department.Name}
+	}
+	for _, ur := range u.UserRoles {
+		if ur.Role == nil {
+			continue
+		}
+		res.Roles = append(res.Roles, user.RoleSummary{ID: ur.Role.ID, Name: ur.Role.Name, Level: ur.Role.Level})
+	}
+	return res, nil
+}
+
+func toUserListItem(u *models.User) user.UserListItem {
+	return user.UserListItem{
+		ID:           u.ID,
+		Email:        u.Email,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		BadgeNumber:  u.BadgeNumber,
+		DepartmentID: u.DepartmentID,
+		IsActive:     u.IsActive,
+		LastLogin:    u.LastLogin,
+		CreatedAt:    u.CreatedAt,
+	}
+}
+
+func toUserResponse(u *models.User) user.UserResponse {
+	res := user.UserResponse{
+		ID:              u.ID,
+		Email:           u.Email,
+		FirstName:       u.FirstName,
+		LastName:        u.LastName,
+		PhoneNumber:     u.PhoneNumber,
+		BadgeNumber:     u.BadgeNumber,
+		DepartmentID:    u.DepartmentID,
+		ProfileImageURL: u.ProfileImageURL,
+		IsActive:        u.IsActive,
+		MFAEnabled:      u.MFAEnabled,
+		LastLogin:       u.LastLogin,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+	if u.DeletedAt.Valid {
+		res.DeletedAt = &u.DeletedAt.Time
+	}
+	return res
 }
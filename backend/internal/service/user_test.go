@@ -0,0 +1,40 @@
+package service
+
+import (
+	"backend/internal/dto/user"
+	"backend/internal/model"
+	"backend/internal/principal"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUsersCannotChangeTheirOwnAccess(t *testing.T) {
+	self := &models.User{IsActive: true}
+	self.ID = 4
+	s := &userService{userRepo: &fakeUserRepo{user: self}, txManager: fakeTxManager{}}
+	ctx := principal.NewContext(context.Background(), &principal.Principal{User: self})
+
+	departmentID := uint(2)
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"transfer", func() error {
+			_, err := s.TransferDepartment(ctx, self.ID, user.TransferDepartmentRequest{DepartmentID: &departmentID})
+			return err
+		}, ErrCannotTransferSelf},
+		{"leave department", func() error {
+			_, err := s.TransferDepartment(ctx, self.ID, user.TransferDepartmentRequest{})
+			return err
+		}, ErrCannotTransferSelf},
+		{"reactivate", func() error { return s.ReactivateUser(ctx, self.ID) }, ErrCannotReactivateSelf},
+		{"deactivate", func() error { return s.DeactivateUser(ctx, self.ID) }, ErrCannotDeactivateSelf},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
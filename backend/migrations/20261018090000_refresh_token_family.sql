@@ -0,0 +1,8 @@
+-- Modify "refresh_tokens" table
+ALTER TABLE "public"."refresh_tokens" ADD COLUMN "family_id" character varying(64) NULL, ADD COLUMN "replaced_by_id" bigint NULL;
+-- Start a family of its own for every token issued before families existed
+UPDATE "public"."refresh_tokens" SET "family_id" = md5("id"::text) WHERE "family_id" IS NULL;
+-- Modify "refresh_tokens" table
+ALTER TABLE "public"."refresh_tokens" ALTER COLUMN "family_id" SET NOT NULL;
+-- Create index "idx_refresh_tokens_family_id" to table: "refresh_tokens"
+CREATE INDEX "idx_refresh_tokens_family_id" ON "public"."refresh_tokens" ("family_id");
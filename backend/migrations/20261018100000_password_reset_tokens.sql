@@ -0,0 +1,19 @@
+-- Create "password_reset_tokens" table
+CREATE TABLE "public"."password_reset_tokens" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "user_id" bigint NOT NULL,
+ "token_hash" character varying(64) NOT NULL,
+ "expires_at" timestamptz NOT NULL,
+ "used_at" timestamptz NULL,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_users_password_reset_tokens" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_password_reset_tokens_deleted_at" to table: "password_reset_tokens"
+CREATE INDEX "idx_password_reset_tokens_deleted_at" ON "public"."password_reset_tokens" ("deleted_at");
+-- Create index "idx_password_reset_tokens_token_hash" to table: "password_reset_tokens"
+CREATE UNIQUE INDEX "idx_password_reset_tokens_token_hash" ON "public"."password_reset_tokens" ("token_hash");
+-- Create index "idx_password_reset_tokens_user_id" to table: "password_reset_tokens"
+CREATE INDEX "idx_password_reset_tokens_user_id" ON "public"."password_reset_tokens" ("user_id");
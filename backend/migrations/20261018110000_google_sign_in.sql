@@ -0,0 +1,23 @@
+-- Modify "departments" table
+ALTER TABLE "public"."departments" ADD COLUMN "email_domain" character varying(100) NULL, ADD COLUMN "google_auto_provision" boolean NOT NULL DEFAULT false;
+-- Create index "idx_departments_email_domain" to table: "departments"
+CREATE INDEX "idx_departments_email_domain" ON "public"."departments" ("email_domain");
+-- Create index "idx_users_google_id" to table: "users"
+CREATE INDEX "idx_users_google_id" ON "public"."users" ("google_id");
+-- Create "oidc_login_states" table
+CREATE TABLE "public"."oidc_login_states" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "state_hash" character varying(64) NOT NULL,
+ "nonce" character varying(64) NOT NULL,
+ "code_verifier" character varying(128) NOT NULL,
+ "expires_at" timestamptz NOT NULL,
+ "used_at" timestamptz NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "idx_oidc_login_states_deleted_at" to table: "oidc_login_states"
+CREATE INDEX "idx_oidc_login_states_deleted_at" ON "public"."oidc_login_states" ("deleted_at");
+-- Create index "idx_oidc_login_states_state_hash" to table: "oidc_login_states"
+CREATE UNIQUE INDEX "idx_oidc_login_states_state_hash" ON "public"."oidc_login_states" ("state_hash");
@@ -0,0 +1,40 @@
+-- Modify "roles" table
+ALTER TABLE "public"."roles" ADD COLUMN "mfa_required" boolean NOT NULL DEFAULT false;
+-- Modify "users" table
+ALTER TABLE "public"."users" ADD COLUMN "mfa_enabled" boolean NOT NULL DEFAULT false, ADD COLUMN "mfa_enabled_at" timestamptz NULL, ADD COLUMN "mfa_secret" character varying(255) NULL, ADD COLUMN "mfa_last_used_step" bigint NOT NULL DEFAULT 0;
+-- Create "mfa_challenges" table
+CREATE TABLE "public"."mfa_challenges" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "user_id" bigint NOT NULL,
+ "token_hash" character varying(64) NOT NULL,
+ "expires_at" timestamptz NOT NULL,
+ "attempts" bigint NOT NULL DEFAULT 0,
+ "used_at" timestamptz NULL,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_users_mfa_challenges" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_mfa_challenges_deleted_at" to table: "mfa_challenges"
+CREATE INDEX "idx_mfa_challenges_deleted_at" ON "public"."mfa_challenges" ("deleted_at");
+-- Create index "idx_mfa_challenges_token_hash" to table: "mfa_challenges"
+CREATE UNIQUE INDEX "idx_mfa_challenges_token_hash" ON "public"."mfa_challenges" ("token_hash");
+-- Create index "idx_mfa_challenges_user_id" to table: "mfa_challenges"
+CREATE INDEX "idx_mfa_challenges_user_id" ON "public"."mfa_challenges" ("user_id");
+-- Create "mfa_recovery_codes" table
+CREATE TABLE "public"."mfa_recovery_codes" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "user_id" bigint NOT NULL,
+ "code_hash" character varying(64) NOT NULL,
+ "used_at" timestamptz NULL,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_users_mfa_recovery_codes" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_mfa_recovery_codes_deleted_at" to table: "mfa_recovery_codes"
+CREATE INDEX "idx_mfa_recovery_codes_deleted_at" ON "public"."mfa_recovery_codes" ("deleted_at");
+-- Create index "idx_mfa_recovery_codes_user_id" to table: "mfa_recovery_codes"
+CREATE INDEX "idx_mfa_recovery_codes_user_id" ON "public"."mfa_recovery_codes" ("user_id");
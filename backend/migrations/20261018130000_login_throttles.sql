@@ -0,0 +1,15 @@
+-- Create "login_throttles" table
+CREATE TABLE "public"."login_throttles" (
+ "id" bigserial NOT NULL,
+ "scope" character varying(10) NOT NULL,
+ "key" character varying(100) NOT NULL,
+ "failed_attempts" bigint NOT NULL DEFAULT 0,
+ "last_failed_at" timestamptz NULL,
+ "next_attempt_at" timestamptz NULL,
+ "locked_until" timestamptz NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ PRIMARY KEY ("id")
+);
+-- Create index "idx_login_throttle_scope_key" to table: "login_throttles"
+CREATE UNIQUE INDEX "idx_login_throttle_scope_key" ON "public"."login_throttles" ("scope", "key");
@@ -0,0 +1,4 @@
+-- Drop index "idx_users_badge_number" from table: "users"
+DROP INDEX "public"."idx_users_badge_number";
+-- Create index "idx_users_badge_number" to table: "users"
+CREATE UNIQUE INDEX "idx_users_badge_number" ON "public"."users" ("badge_number") WHERE ((badge_number)::text <> ''::text);
@@ -0,0 +1,2 @@
+-- Modify "departments" table
+ALTER TABLE "public"."departments" ADD COLUMN "head_id" bigint NULL, ADD CONSTRAINT "fk_departments_head" FOREIGN KEY ("head_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
@@ -0,0 +1,32 @@
+-- Create "case_number_formats" table
+CREATE TABLE "public"."case_number_formats" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "case_type" character varying(50) NOT NULL,
+ "prefix" character varying(10) NOT NULL,
+ "padding" bigint NOT NULL DEFAULT 3,
+ "include_year" boolean NOT NULL DEFAULT true,
+ "department_id" bigint NULL,
+ "is_default" boolean NOT NULL DEFAULT false,
+ "created_by_id" bigint NULL,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_case_number_formats_created_by" FOREIGN KEY ("created_by_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+ CONSTRAINT "fk_case_number_formats_department" FOREIGN KEY ("department_id") REFERENCES "public"."departments" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_case_number_formats_deleted_at" to table: "case_number_formats"
+CREATE INDEX "idx_case_number_formats_deleted_at" ON "public"."case_number_formats" ("deleted_at");
+-- Create "case_number_sequences" table
+CREATE TABLE "public"."case_number_sequences" (
+ "id" bigserial NOT NULL,
+ "format_id" bigint NOT NULL,
+ "year" bigint NOT NULL,
+ "last_value" bigint NOT NULL DEFAULT 0,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_case_number_sequences_format" FOREIGN KEY ("format_id") REFERENCES "public"."case_number_formats" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_case_number_sequence_format_year" to table: "case_number_sequences"
+CREATE UNIQUE INDEX "idx_case_number_sequence_format_year" ON "public"."case_number_sequences" ("format_id", "year");
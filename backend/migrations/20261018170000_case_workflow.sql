@@ -0,0 +1,71 @@
+-- Modify "cases" table
+ALTER TABLE "public"."cases" ADD COLUMN "closure_reason" text NULL;
+-- Create "case_statuses" table
+CREATE TABLE "public"."case_statuses" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "name" character varying(50) NOT NULL,
+ "description" text NULL,
+ "is_initial" boolean NOT NULL DEFAULT false,
+ "is_closed" boolean NOT NULL DEFAULT false,
+ "sort_order" bigint NOT NULL DEFAULT 0,
+ PRIMARY KEY ("id")
+);
+-- Create index "idx_case_statuses_deleted_at" to table: "case_statuses"
+CREATE INDEX "idx_case_statuses_deleted_at" ON "public"."case_statuses" ("deleted_at");
+-- Create index "idx_case_statuses_initial" to table: "case_statuses"
+CREATE UNIQUE INDEX "idx_case_statuses_initial" ON "public"."case_statuses" ("is_initial") WHERE (is_initial AND (deleted_at IS NULL));
+-- Create index "idx_case_statuses_name" to table: "case_statuses"
+CREATE UNIQUE INDEX "idx_case_statuses_name" ON "public"."case_statuses" ((lower((name)::text))) WHERE (deleted_at IS NULL);
+-- Create "case_transitions" table
+CREATE TABLE "public"."case_transitions" (
+ "id" bigserial NOT NULL,
+ "created_at" timestamptz NULL,
+ "updated_at" timestamptz NULL,
+ "deleted_at" timestamptz NULL,
+ "name" character varying(50) NOT NULL,
+ "from_status_id" bigint NOT NULL,
+ "to_status_id" bigint NOT NULL,
+ "required_permission" character varying(100) NULL,
+ "requires_reason" boolean NOT NULL DEFAULT false,
+ PRIMARY KEY ("id"),
+ CONSTRAINT "fk_case_transitions_from_status" FOREIGN KEY ("from_status_id") REFERENCES "public"."case_statuses" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+ CONSTRAINT "fk_case_transitions_to_status" FOREIGN KEY ("to_status_id") REFERENCES "public"."case_statuses" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_case_transitions_deleted_at" to table: "case_transitions"
+CREATE INDEX "idx_case_transitions_deleted_at" ON "public"."case_transitions" ("deleted_at");
+-- Create index "idx_case_transitions_from_status_id" to table: "case_transitions"
+CREATE INDEX "idx_case_transitions_from_status_id" ON "public"."case_transitions" ("from_status_id");
+-- Install the default workflow, the same one the seed creates
+INSERT INTO "public"."case_statuses" ("created_at", "updated_at", "name", "description", "is_initial", "is_closed", "sort_order") VALUES
+ (now(), now(), 'Open', 'Reported and awaiting an investigator', true, false, 10),
+ (now(), now(), 'Under Investigation', 'Actively being investigated', false, false, 20),
+ (now(), now(), 'Pending Review', 'Investigation finished, awaiting supervisor review', false, false, 30),
+ (now(), now(), 'Closed', 'Resolved and closed', false, true, 40),
+ (now(), now(), 'Reopened', 'Closed or cold case taken up again', false, false, 50),
+ (now(), now(), 'Cold', 'No active leads, kept on file', false, false, 60);
+INSERT INTO "public"."case_transitions" ("created_at", "updated_at", "name", "from_status_id", "to_status_id", "required_permission", "requires_reason")
+SELECT now(), now(), t.name, from_status.id, to_status.id, t.required_permission, t.requires_reason
+FROM (VALUES
+ ('Start Investigation', 'Open', 'Under Investigation', '', false),
+ ('Submit for Review', 'Under Investigation', 'Pending Review', '', false),
+ ('Return to Investigation', 'Pending Review', 'Under Investigation', '', true),
+ ('Close', 'Pending Review', 'Closed', 'case.close', true),
+ ('Close Without Investigation', 'Open', 'Closed', 'case.close', true),
+ ('Mark Cold', 'Under Investigation', 'Cold', '', true),
+ ('Reopen', 'Closed', 'Reopened', 'case.close', true),
+ ('Reopen Cold Case', 'Cold', 'Reopened', '', false),
+ ('Resume Investigation', 'Reopened', 'Under Investigation', '', false)
+) AS t (name, from_name, to_name, required_permission, requires_reason)
+JOIN "public"."case_statuses" AS from_status ON from_status.name = t.from_name
+JOIN "public"."case_statuses" AS to_status ON to_status.name = t.to_name;
+-- Move cases out of the fixed statuses that the workflow replaced
+UPDATE "public"."cases" SET "status" = 'Under Investigation' WHERE "status" = 'Active';
+UPDATE "public"."cases" SET "status" = 'Pending Review' WHERE "status" = 'Pending';
+-- Start cases in any other unknown status over in the initial status, so a transition leads out of it
+UPDATE "public"."cases" SET "status" = 'Open' WHERE "status" NOT IN (SELECT "name" FROM "public"."case_statuses");
+-- A case is closed exactly while it has a closed_at
+UPDATE "public"."cases" SET "closed_at" = COALESCE("updated_at", "created_at", now()) WHERE "status" = 'Closed' AND "closed_at" IS NULL;
+UPDATE "public"."cases" SET "closed_at" = NULL, "closed_by_id" = NULL WHERE "status" <> 'Closed' AND "closed_at" IS NOT NULL;
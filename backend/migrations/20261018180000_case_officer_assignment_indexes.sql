@@ -0,0 +1,16 @@
+-- Unassign all but the newest of an officer's duplicate active assignments to a case
+UPDATE "public"."case_officers" AS o SET "deleted_at" = now(), "updated_at" = now()
+WHERE o.deleted_at IS NULL AND EXISTS (
+ SELECT 1 FROM "public"."case_officers" AS newer
+ WHERE newer.case_id = o.case_id AND newer.officer_id = o.officer_id AND newer.deleted_at IS NULL AND newer.id > o.id
+);
+-- Unassign all but the newest active lead investigator of a case
+UPDATE "public"."case_officers" AS o SET "deleted_at" = now(), "updated_at" = now()
+WHERE o.deleted_at IS NULL AND o.role = 'Lead Investigator' AND EXISTS (
+ SELECT 1 FROM "public"."case_officers" AS newer
+ WHERE newer.case_id = o.case_id AND newer.role = 'Lead Investigator' AND newer.deleted_at IS NULL AND newer.id > o.id
+);
+-- Create index "idx_case_officers_active" to table: "case_officers"
+CREATE UNIQUE INDEX "idx_case_officers_active" ON "public"."case_officers" ("case_id", "officer_id") WHERE (deleted_at IS NULL);
+-- Create index "idx_case_officers_lead" to table: "case_officers"
+CREATE UNIQUE INDEX "idx_case_officers_lead" ON "public"."case_officers" ("case_id") WHERE (((role)::text = 'Lead Investigator'::text) AND (deleted_at IS NULL));
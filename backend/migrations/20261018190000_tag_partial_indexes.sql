@@ -0,0 +1,6 @@
+-- Drop index "idx_tags_name" from table: "tags"
+DROP INDEX "public"."idx_tags_name";
+-- Create index "idx_tags_name" to table: "tags"
+CREATE UNIQUE INDEX "idx_tags_name" ON "public"."tags" ("name") WHERE (deleted_at IS NULL);
+-- Create index "idx_case_tags_active" to table: "case_tags"
+CREATE UNIQUE INDEX "idx_case_tags_active" ON "public"."case_tags" ("case_id", "tag_id") WHERE (deleted_at IS NULL);
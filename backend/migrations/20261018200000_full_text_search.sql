@@ -0,0 +1,8 @@
+-- Modify "cases" table
+ALTER TABLE "public"."cases" ADD COLUMN "search_vector" tsvector NULL GENERATED ALWAYS AS (((setweight(to_tsvector('english'::regconfig, (COALESCE(title, ''::character varying))::text), 'A'::"char") || setweight(to_tsvector('english'::regconfig, COALESCE(description, ''::text)), 'B'::"char")) || setweight(to_tsvector('english'::regconfig, COALESCE(location, ''::text)), 'C'::"char"))) STORED;
+-- Create index "idx_cases_search_vector" to table: "cases"
+CREATE INDEX "idx_cases_search_vector" ON "public"."cases" USING gin ("search_vector");
+-- Modify "evidences" table
+ALTER TABLE "public"."evidences" ADD COLUMN "search_vector" tsvector NULL GENERATED ALWAYS AS (((setweight(to_tsvector('english'::regconfig, (COALESCE(title, ''::character varying))::text), 'A'::"char") || setweight(to_tsvector('english'::regconfig, COALESCE(description, ''::text)), 'B'::"char")) || setweight(to_tsvector('english'::regconfig, (COALESCE(metadata ->> 'item'::text, ''::text) || ' ' || COALESCE(metadata ->> 'description'::text, ''::text) || ' ' || COALESCE(metadata ->> 'notes'::text, ''::text) || ' ' || COALESCE(metadata ->> 'label'::text, ''::text) || ' ' || COALESCE(metadata ->> 'contents'::text, ''::text) || ' ' || COALESCE(metadata ->> 'brand'::text, ''::text) || ' ' || COALESCE(metadata ->> 'model'::text, ''::text) || ' ' || COALESCE(metadata ->> 'color'::text, ''::text) || ' ' || COALESCE(metadata ->> 'serial_number'::text, ''::text) || ' ' || COALESCE(metadata ->> 'location'::text, ''::text))), 'C'::"char"))) STORED;
+-- Create index "idx_evidences_search_vector" to table: "evidences"
+CREATE INDEX "idx_evidences_search_vector" ON "public"."evidences" USING gin ("search_vector");
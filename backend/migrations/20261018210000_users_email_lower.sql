@@ -0,0 +1,2 @@
+-- Create index "idx_users_email_lower" to table: "users"
+CREATE INDEX "idx_users_email_lower" ON "public"."users" ((lower((email)::text)));
@@ -19,10 +19,19 @@ func main() {
 		&models.AuditLog{},
 		&models.Case{},
 		&models.CaseOfficer{},
+		&models.CaseNumberFormat{},
+		&models.CaseNumberSequence{},
+		&models.CaseStatus{},
+		&models.CaseTransition{},
 		&models.Role{},
 		&models.Department{},
 		&models.RoleChangeHistory{},
 		&models.RefreshToken{},
+		&models.PasswordResetToken{},
+		&models.OIDCLoginState{},
+		&models.MFARecoveryCode{},
+		&models.MFAChallenge{},
+		&models.LoginThrottle{},
 		&models.RolePermission{},
 		&models.RoleManagementPermission{},
 		&models.Evidence{},
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
 	"log"
 	"time"
@@ -14,6 +12,7 @@ import (
 
 	// Assuming your models are in this package
 	"backend/internal/model"
+	"backend/internal/security"
 )
 
 func main() {
@@ -83,34 +82,49 @@ func seedDatabase(db *gorm.DB) error {
 			return err
 		}
 
-		// Step 9: Create Sample Tags
+		// Step 9: Appoint Department Heads
+		if err := seedDepartmentHeads(tx, departments, users); err != nil {
+			return err
+		}
+
+		// Step 10: Create Case Number Formats
+		if err := seedCaseNumberFormats(tx, departments); err != nil {
+			return err
+		}
+
+		// Step 11: Create Case Workflow
+		if err := seedCaseWorkflow(tx); err != nil {
+			return err
+		}
+
+		// Step 12: Create Sample Tags
 		tags, err := seedTags(tx)
 		if err != nil {
 			return err
 		}
 
-		// Step 10: Create Sample Cases
+		// Step 13: Create Sample Cases
 		cases, err := seedCases(tx, users)
 		if err != nil {
 			return err
 		}
 
-		// Step 11: Assign Officers to Cases
+		// Step 14: Assign Officers to Cases
 		if err := seedCaseOfficers(tx, cases, users); err != nil {
 			return err
 		}
 
-		// Step 12: Add Tags to Cases
+		// Step 15: Add Tags to Cases
 		if err := seedCaseTags(tx, cases, tags, users); err != nil {
 			return err
 		}
 
-		// Step 13: Add Sample Evidence
+		// Step 16: Add Sample Evidence
 		if err := seedEvidence(tx, cases, users); err != nil {
 			return err
 		}
 
-		// Step 14: Create Sample Audit Logs
+		// Step 17: Create Sample Audit Logs
 		if err := seedAuditLogs(tx, users, cases); err != nil {
 			return err
 		}
@@ -119,10 +133,15 @@ func seedDatabase(db *gorm.DB) error {
 	})
 }
 
-// Helper to hash passwords
+// Helper to hash passwords with the same hasher the API uses
+var passwordHasher = security.NewPasswordHasher(security.DefaultPasswordConfig())
+
 func hashPassword(password string) string {
-	hash := sha256.Sum256([]byte(password))
-	return hex.EncodeToString(hash[:])
+	hash, err := passwordHasher.Hash(password)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+	return hash
 }
 
 // Seed Departments
@@ -183,6 +202,10 @@ func seedPermissionCategories(tx *gorm.DB) (map[string]*models.PermissionCategor
 			Name:        "Reporting",
 			Description: "Permissions related to viewing and generating reports",
 		},
+		"department": {
+			Name:        "Department Management",
+			Description: "Permissions related to department administration",
+		},
 		"system": {
 			Name:        "System Administration",
 			Description: "Permissions related to system settings",
@@ -265,6 +288,12 @@ func seedPermissions(tx *gorm.DB, categories map[string]*models.PermissionCatego
 			Code:        "case.assign",
 			Description: "Can assign officers to cases",
 		},
+		"case.view_all": {
+			CategoryID:  categories["case"].ID,
+			Name:        "View All Departments' Cases",
+			Code:        "case.view_all",
+			Description: "Can view cases outside their own department",
+		},
 
 		// Evidence permissions
 		"evidence.upload": {
@@ -350,6 +379,32 @@ func seedPermissions(tx *gorm.DB, categories map[string]*models.PermissionCatego
 			Description: "Can export reports",
 		},
 
+		// Department permissions
+		"department.view": {
+			CategoryID:  categories["department"].ID,
+			Name:        "View Departments",
+			Code:        "department.view",
+			Description: "Can view departments and their members",
+		},
+		"department.create": {
+			CategoryID:  categories["department"].ID,
+			Name:        "Create Department",
+			Code:        "department.create",
+			Description: "Can create new departments",
+		},
+		"department.edit": {
+			CategoryID:  categories["department"].ID,
+			Name:        "Edit Department",
+			Code:        "department.edit",
+			Description: "Can edit departments and appoint their heads",
+		},
+		"department.delete": {
+			CategoryID:  categories["department"].ID,
+			Name:        "Delete Department",
+			Code:        "department.delete",
+			Description: "Can delete departments",
+		},
+
 		// System permissions
 		"system.settings": {
 			CategoryID:  categories["system"].ID,
@@ -384,12 +439,14 @@ func seedRoles(tx *gorm.DB) (map[string]*models.Role, error) {
 			Description:  "System administrator with full access",
 			Level:        100,
 			IsSystemRole: true,
+			MFARequired:  true,
 		},
 		"chief": {
 			Name:         "Police Chief",
 			Description:  "Head of the police department",
 			Level:        90,
 			IsSystemRole: true,
+			MFARequired:  true,
 		},
 		"captain": {
 			Name:         "Captain",
@@ -479,19 +536,21 @@ func seedRolePermissions(tx *gorm.DB, roles map[string]*models.Role, permissions
 		"admin": {
 			// Admin has all permissions
 			"user.create", "user.view", "user.edit", "user.delete",
-			"case.create", "case.view", "case.edit", "case.delete", "case.close", "case.assign",
+			"case.create", "case.view", "case.edit", "case.delete", "case.close", "case.assign", "case.view_all",
 			"evidence.upload", "evidence.view", "evidence.edit", "evidence.delete", "evidence.confidential",
 			"role.create", "role.view", "role.edit", "role.delete", "role.assign",
 			"report.view", "report.create", "report.export",
+			"department.view", "department.create", "department.edit", "department.delete",
 			"system.settings", "system.audit",
 		},
 		"chief": {
 			// Police Chief has most permissions except some system ones
 			"user.create", "user.view", "user.edit", "user.delete",
-			"case.create", "case.view", "case.edit", "case.delete", "case.close", "case.assign",
+			"case.create", "case.view", "case.edit", "case.delete", "case.close", "case.assign", "case.view_all",
 			"evidence.upload", "evidence.view", "evidence.edit", "evidence.delete", "evidence.confidential",
 			"role.view", "role.assign",
 			"report.view", "report.create", "report.export",
+			"department.view", "department.create", "department.edit",
 			"system.audit",
 		},
 		"captain": {
@@ -501,6 +560,7 @@ func seedRolePermissions(tx *gorm.DB, roles map[string]*models.Role, permissions
 			"evidence.upload", "evidence.view", "evidence.edit", "evidence.confidential",
 			"role.view", "role.assign",
 			"report.view", "report.create", "report.export",
+			"department.view",
 			"system.audit",
 		},
 		"lieutenant": {
@@ -509,6 +569,7 @@ func seedRolePermissions(tx *gorm.DB, roles map[string]*models.Role, permissions
 			"case.create", "case.view", "case.edit", "case.close", "case.assign",
 			"evidence.upload", "evidence.view", "evidence.edit", "evidence.confidential",
 			"report.view", "report.create",
+			"department.view",
 		},
 		"sergeant": {
 			// Sergeant supervises officers and handles case assignments
@@ -535,8 +596,8 @@ func seedRolePermissions(tx *gorm.DB, roles map[string]*models.Role, permissions
 			"evidence.view",
 		},
 		"analyst": {
-			// Crime analyst focused on reporting
-			"case.view",
+			// Crime analyst focused on reporting across divisions
+			"case.view", "case.view_all",
 			"evidence.view",
 			"report.view", "report.create", "report.export",
 		},
@@ -630,7 +691,7 @@ func seedUsers(tx *gorm.DB, departments map[string]*models.Department) (map[stri
 			FirstName:       "System",
 			LastName:        "Administrator",
 			Email:           "admin@policedept.gov",
-			PasswordHash:    hashPassword("admin123"), // In real system, use proper password hashing
+			PasswordHash:    hashPassword("admin123"),
 			BadgeNumber:     "ADMIN001",
 			DepartmentID:    &departments["headquarters"].ID,
 			ProfileImageURL: "https://example.com/profiles/admin.jpg",
@@ -812,6 +873,111 @@ func seedUserRoles(tx *gorm.DB, users map[string]*models.User, roles map[string]
 	return nil
 }
 
+// Seed Department Heads
+func seedDepartmentHeads(tx *gorm.DB, departments map[string]*models.Department, users map[string]*models.User) error {
+	heads := map[string]string{
+		"headquarters": "chief",
+		"homicide":     "captain1",
+		"narcotics":    "captain2",
+		"cyber":        "lieutenant2",
+	}
+
+	for deptName, userName := range heads {
+		if err := tx.Model(departments[deptName]).Update("head_id", users[userName].ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Seed Case Number Formats, matching the hand-written sample case numbers
+func seedCaseNumberFormats(tx *gorm.DB, departments map[string]*models.Department) error {
+	formats := []*models.CaseNumberFormat{
+		{CaseType: "Homicide", Prefix: "HOM", DepartmentID: &departments["homicide"].ID},
+		{CaseType: "Robbery", Prefix: "ROB"},
+		{CaseType: "Cyber Crime", Prefix: "CYB", DepartmentID: &departments["cyber"].ID},
+		{CaseType: "Narcotics", Prefix: "NAR", DepartmentID: &departments["narcotics"].ID},
+		{CaseType: "Assault", Prefix: "AST"},
+		{CaseType: "Theft", Prefix: "THF"},
+		{CaseType: "Fraud", Prefix: "FRD"},
+		{CaseType: "Vandalism", Prefix: "VND"},
+		{CaseType: "Cold Case", Prefix: "CLD"},
+		{CaseType: "General", Prefix: "CASE", IsDefault: true},
+	}
+
+	for _, format := range formats {
+		format.Padding = 3
+		format.IncludeYear = true
+		if err := tx.Create(format).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Seed the default case workflow
+func seedCaseWorkflow(tx *gorm.DB) error {
+	// The case workflow migration installs the same workflow, so only
+	// databases created without the migrations lack it.
+	var count int64
+	if err := tx.Model(&models.CaseStatus{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	statuses := []*models.CaseStatus{
+		{Name: "Open", Description: "Reported and awaiting an investigator", IsInitial: true},
+		{Name: "Under Investigation", Description: "Actively being investigated"},
+		{Name: "Pending Review", Description: "Investigation finished, awaiting supervisor review"},
+		{Name: "Closed", Description: "Resolved and closed", IsClosed: true},
+		{Name: "Reopened", Description: "Closed or cold case taken up again"},
+		{Name: "Cold", Description: "No active leads, kept on file"},
+	}
+
+	byName := make(map[string]*models.CaseStatus)
+	for i, status := range statuses {
+		status.SortOrder = (i + 1) * 10
+		if err := tx.Create(status).Error; err != nil {
+			return err
+		}
+		byName[status.Name] = status
+	}
+
+	transitions := []struct {
+		name, from, to, permission string
+		requiresReason             bool
+	}{
+		{"Start Investigation", "Open", "Under Investigation", "", false},
+		{"Submit for Review", "Under Investigation", "Pending Review", "", false},
+		{"Return to Investigation", "Pending Review", "Under Investigation", "", true},
+		{"Close", "Pending Review", "Closed", "case.close", true},
+		{"Close Without Investigation", "Open", "Closed", "case.close", true},
+		{"Mark Cold", "Under Investigation", "Cold", "", true},
+		{"Reopen", "Closed", "Reopened", "case.close", true},
+		{"Reopen Cold Case", "Cold", "Reopened", "", false},
+		{"Resume Investigation", "Reopened", "Under Investigation", "", false},
+	}
+
+	for _, t := range transitions {
+		transition := &models.CaseTransition{
+			Name:               t.name,
+			FromStatusID:       byName[t.from].ID,
+			ToStatusID:         byName[t.to].ID,
+			RequiredPermission: t.permission,
+			RequiresReason:     t.requiresReason,
+		}
+		if err := tx.Create(transition).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Seed Tags
 func seedTags(tx *gorm.DB) (map[string]*models.Tag, error) {
 	tags := map[string]*models.Tag{